@@ -1,12 +1,12 @@
 package application
 
 import (
-	"log"
-
+	"context"
 	"github.com/code-wave/go-wave/domain/entity"
 	"github.com/code-wave/go-wave/domain/repository"
 	"github.com/code-wave/go-wave/infrastructure/auth"
 	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/logger"
 )
 
 type AuthApp struct {
@@ -14,10 +14,10 @@ type AuthApp struct {
 }
 
 type AuthAppInterface interface {
-	CreateAuth(*entity.RefreshToken) *errors.RestErr
-	DeleteAuth(string) *errors.RestErr
-	FetchAuth(string) (int64, *errors.RestErr)
-	Refresh(string, int64) (*entity.AccessToken, *errors.RestErr)
+	CreateAuth(context.Context, *entity.RefreshToken) *errors.RestErr
+	DeleteAuth(context.Context, string) *errors.RestErr
+	FetchAuth(context.Context, string) (int64, *errors.RestErr)
+	Refresh(context.Context, string, int64) (*entity.AccessToken, *errors.RestErr)
 }
 
 func NewAuthApp(ar repository.AuthRepository) *AuthApp {
@@ -26,30 +26,29 @@ func NewAuthApp(ar repository.AuthRepository) *AuthApp {
 	}
 }
 
-func (au *AuthApp) CreateAuth(rt *entity.RefreshToken) *errors.RestErr {
-	return au.ar.Create(rt)
+func (au *AuthApp) CreateAuth(ctx context.Context, rt *entity.RefreshToken) *errors.RestErr {
+	return au.ar.Create(ctx, rt)
 }
 
-func (au *AuthApp) DeleteAuth(uuid string) *errors.RestErr {
-	return au.ar.Delete(uuid)
+func (au *AuthApp) DeleteAuth(ctx context.Context, uuid string) *errors.RestErr {
+	return au.ar.Delete(ctx, uuid)
 }
 
-func (au *AuthApp) FetchAuth(uuid string) (int64, *errors.RestErr) {
-	return au.ar.Fetch(uuid)
+func (au *AuthApp) FetchAuth(ctx context.Context, uuid string) (int64, *errors.RestErr) {
+	return au.ar.Fetch(ctx, uuid)
 }
 
-func (au *AuthApp) Refresh(uuid string, uid int64) (*entity.AccessToken, *errors.RestErr) {
-	userID, err := au.ar.Fetch(uuid)
+func (au *AuthApp) Refresh(ctx context.Context, uuid string, uid int64) (*entity.AccessToken, *errors.RestErr) {
+	userID, err := au.ar.Fetch(ctx, uuid)
 	if err != nil {
 		return nil, err
 	}
 
 	if userID != uid {
 		authErr := errors.NewUnauthorizedError("unauthroized, userID which is parsed from access_token is diffrent redis's userID")
-		log.Printf("access_token's userID: %d but redis's userID: %d invalid user access", uid, userID)
+		logger.Warn("invalid user access, access token's user id is different from redis's", logger.F("token_user_id", uid), logger.F("user_id", userID))
 		return nil, authErr
 	}
-	log.Println(userID, uid)
 
 	at, tokenErr := auth.JwtWrapper.GenerateAccessToken(userID)
 	if tokenErr != nil {
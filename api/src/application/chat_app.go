@@ -1,6 +1,9 @@
 package application
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/code-wave/go-wave/domain/entity"
 	"github.com/code-wave/go-wave/domain/repository"
 	"github.com/code-wave/go-wave/infrastructure/chat"
@@ -9,17 +12,30 @@ import (
 
 var _ ChatAppInterface = &ChatApp{}
 
+const (
+	// DefaultMessagePageSize 채팅룸 정보와 함께 보내는 최근 메시지 개수
+	DefaultMessagePageSize = 50
+	maxMessagePageSize     = 100
+)
+
 type ChatApp struct {
 	chatRepo repository.ChatRepository
 }
 
 type ChatAppInterface interface {
-	GetChatRoom(clientID, hostID, studyPostID int64) (*entity.ChatRoom, *errors.RestErr)
-	SaveChatRoom(clientID, hostID, studyPostID int64) (*entity.ChatRoom, *errors.RestErr)
-	GetChatRoomByRoomName(roomName string) (*entity.ChatRoom, *errors.RestErr)
-	GetChatRoomByID(id int64) (*entity.ChatRoom, *errors.RestErr)
-	SaveChatMessage(msg *entity.ChatMessage) (*entity.ChatMessage, *errors.RestErr)
-	GetChatMessages(roomID int64) (chat.Messages, *errors.RestErr)
+	GetChatRoom(ctx context.Context, clientID, hostID, studyPostID int64) (*entity.ChatRoom, *errors.RestErr)
+	SaveChatRoom(ctx context.Context, clientID, hostID, studyPostID int64) (*entity.ChatRoom, *errors.RestErr)
+	GetChatRoomByRoomName(ctx context.Context, roomName string) (*entity.ChatRoom, *errors.RestErr)
+	GetChatRoomByID(ctx context.Context, id int64) (*entity.ChatRoom, *errors.RestErr)
+	CheckChatRoomParticipant(ctx context.Context, roomID, userID int64) *errors.RestErr
+	SaveChatMessage(ctx context.Context, msg *entity.ChatMessage) (*entity.ChatMessage, *errors.RestErr)
+	GetChatMessages(ctx context.Context, roomID int64) (chat.Messages, *errors.RestErr)
+	GetChatMessagesBefore(ctx context.Context, roomID, messageID, limit int64) (chat.Messages, *errors.RestErr)
+	GetChatMessagesAfter(ctx context.Context, roomID, messageID, limit int64) (chat.Messages, *errors.RestErr)
+	ExportChatMessages(ctx context.Context, roomID int64, fn func(chat.Message) error) *errors.RestErr
+	GetUnreadChatRooms(ctx context.Context, userID int64) (chat.UnreadChatRooms, *errors.RestErr)
+	GetChatInbox(ctx context.Context, userID int64) (chat.ChatInbox, *errors.RestErr)
+	GetChatMessageEdits(ctx context.Context, messageID, userID int64) ([]entity.ChatMessageEdit, *errors.RestErr)
 }
 
 func NewChatApp(chatRepo repository.ChatRepository) *ChatApp {
@@ -28,38 +44,141 @@ func NewChatApp(chatRepo repository.ChatRepository) *ChatApp {
 	}
 }
 
-func (chatApp *ChatApp) GetChatRoom(clientID, hostID, studyPostID int64) (*entity.ChatRoom, *errors.RestErr) {
-	return chatApp.chatRepo.GetChatRoom(clientID, hostID, studyPostID)
+func (chatApp *ChatApp) GetChatRoom(ctx context.Context, clientID, hostID, studyPostID int64) (*entity.ChatRoom, *errors.RestErr) {
+	return chatApp.chatRepo.GetChatRoom(ctx, clientID, hostID, studyPostID)
 }
 
-func (chatApp *ChatApp) SaveChatRoom(clientID, hostID, studyPostID int64) (*entity.ChatRoom, *errors.RestErr) {
-	return chatApp.chatRepo.SaveChatRoom(clientID, hostID, studyPostID)
+func (chatApp *ChatApp) SaveChatRoom(ctx context.Context, clientID, hostID, studyPostID int64) (*entity.ChatRoom, *errors.RestErr) {
+	return chatApp.chatRepo.SaveChatRoom(ctx, clientID, hostID, studyPostID)
 }
 
-func (chatApp *ChatApp) GetChatRoomByRoomName(roomName string) (*entity.ChatRoom, *errors.RestErr) {
-	return nil, nil
+func (chatApp *ChatApp) GetChatRoomByRoomName(ctx context.Context, roomName string) (*entity.ChatRoom, *errors.RestErr) {
+	return chatApp.chatRepo.GetChatRoomByRoomName(ctx, roomName)
 }
 
-func (chatApp *ChatApp) GetChatRoomByID(id int64) (*entity.ChatRoom, *errors.RestErr) {
-	return nil, nil
+func (chatApp *ChatApp) GetChatRoomByID(ctx context.Context, id int64) (*entity.ChatRoom, *errors.RestErr) {
+	return chatApp.chatRepo.GetChatRoomByID(ctx, id)
+}
+
+// CheckChatRoomParticipant 유저가 채팅룸의 참여자가 아니면 ForbiddenError
+func (chatApp *ChatApp) CheckChatRoomParticipant(ctx context.Context, roomID, userID int64) *errors.RestErr {
+	isParticipant, err := chatApp.chatRepo.IsChatRoomParticipant(ctx, roomID, userID)
+	if err != nil {
+		return err
+	}
+
+	if !isParticipant {
+		return errors.NewForbiddenError("user is not a participant of the chat room")
+	}
+
+	return nil
 }
 
-func (chatApp *ChatApp) SaveChatMessage(msg *entity.ChatMessage) (*entity.ChatMessage, *errors.RestErr) {
+func (chatApp *ChatApp) SaveChatMessage(ctx context.Context, msg *entity.ChatMessage) (*entity.ChatMessage, *errors.RestErr) {
 	return nil, nil
 }
 
-func (chatApp *ChatApp) GetChatMessages(roomID int64) (chat.Messages, *errors.RestErr) {
+func (chatApp *ChatApp) GetChatMessages(ctx context.Context, roomID int64) (chat.Messages, *errors.RestErr) {
+
+	chatMessages, err := chatApp.chatRepo.GetChatMessages(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	return chat.NewMessages(chatMessages), nil
+}
+
+// GetChatMessagesBefore messageID 이전의 메시지를 최신순으로 반환 (messageID가 0이면 가장 최근 메시지부터)
+func (chatApp *ChatApp) GetChatMessagesBefore(ctx context.Context, roomID, messageID, limit int64) (chat.Messages, *errors.RestErr) {
+	if err := validateMessagePage(messageID, limit); err != nil {
+		return nil, err
+	}
+
+	chatMessages, err := chatApp.chatRepo.GetChatMessagesBefore(ctx, roomID, messageID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return chat.NewMessages(chatMessages), nil
+}
+
+// GetChatMessagesAfter messageID 이후의 메시지를 오래된순으로 반환
+func (chatApp *ChatApp) GetChatMessagesAfter(ctx context.Context, roomID, messageID, limit int64) (chat.Messages, *errors.RestErr) {
+	if err := validateMessagePage(messageID, limit); err != nil {
+		return nil, err
+	}
+
+	chatMessages, err := chatApp.chatRepo.GetChatMessagesAfter(ctx, roomID, messageID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return chat.NewMessages(chatMessages), nil
+}
+
+// ExportChatMessages 채팅룸의 모든 메시지를 오래된순으로 fn에 넘김 (삭제된 메시지는 내용 없이)
+func (chatApp *ChatApp) ExportChatMessages(ctx context.Context, roomID int64, fn func(chat.Message) error) *errors.RestErr {
+	return chatApp.chatRepo.ForEachChatMessage(ctx, roomID, func(chatMessage entity.ChatMessage) error {
+		return fn(chat.NewMessage(chatMessage))
+	})
+}
+
+// GetUnreadChatRooms 유저가 속한 채팅룸들의 안 읽은 메시지 개수와 마지막 메시지를 반환
+func (chatApp *ChatApp) GetUnreadChatRooms(ctx context.Context, userID int64) (chat.UnreadChatRooms, *errors.RestErr) {
+	unreadRooms, err := chatApp.chatRepo.GetUnreadChatRooms(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	chatRooms := make(chat.UnreadChatRooms, 0, len(unreadRooms))
+	for _, unreadRoom := range unreadRooms {
+		chatRooms = append(chatRooms, chat.NewUnreadChatRoom(unreadRoom))
+	}
+
+	return chatRooms, nil
+}
 
-	chatMessages, err := chatApp.chatRepo.GetChatMessages(roomID)
+// GetChatInbox 유저가 참여중인 대화 목록을 최근 활동순으로 반환
+func (chatApp *ChatApp) GetChatInbox(ctx context.Context, userID int64) (chat.ChatInbox, *errors.RestErr) {
+	items, err := chatApp.chatRepo.GetChatInbox(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	var messages chat.Messages
+	inbox := make(chat.ChatInbox, 0, len(items))
+	for _, item := range items {
+		inbox = append(inbox, chat.NewChatInboxItem(item))
+	}
+
+	return inbox, nil
+}
+
+// GetChatMessageEdits 메시지의 수정 전 내용들을 오래된순으로 반환, 채팅룸 참여자만 볼 수 있고 삭제된 메시지는 NotFoundError
+func (chatApp *ChatApp) GetChatMessageEdits(ctx context.Context, messageID, userID int64) ([]entity.ChatMessageEdit, *errors.RestErr) {
+	chatMessage, err := chatApp.chatRepo.GetChatMessage(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = chatApp.CheckChatRoomParticipant(ctx, chatMessage.ChatRoomID, userID); err != nil {
+		return nil, err
+	}
+
+	if chatMessage.DeletedAt.Valid {
+		return nil, errors.NewNotFoundError("chat message doesn't exist")
+	}
+
+	return chatApp.chatRepo.GetChatMessageEdits(ctx, messageID)
+}
+
+func validateMessagePage(messageID, limit int64) *errors.RestErr {
+	if messageID < 0 {
+		return errors.NewBadRequestError("message id can't be negative")
+	}
 
-	for _, msg := range chatMessages {
-		messages = append(messages, chat.NewMessage(msg))
+	if limit <= 0 || limit > maxMessagePageSize {
+		return errors.NewBadRequestError(fmt.Sprintf("limit must be between 1 and %d", maxMessagePageSize))
 	}
 
-	return messages, nil
+	return nil
 }
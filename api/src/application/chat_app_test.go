@@ -0,0 +1,161 @@
+package application
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/chat"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/memory"
+)
+
+// newTestChatRoom client(1)와 host(2)의 1:1 채팅룸에 messages를 client가 보낸 순서대로 저장함
+func newTestChatRoom(t *testing.T, chatRepo repository.ChatRepository, messages ...string) (*entity.ChatRoom, []int64) {
+	ctx := context.Background()
+
+	room, err := chatRepo.SaveChatRoom(ctx, 1, 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []int64
+	for _, message := range messages {
+		saved, err := chatRepo.SaveChatMessage(ctx, &entity.ChatMessage{
+			ChatRoomID:   room.ID,
+			ChatRoomName: room.RoomName,
+			SenderID:     room.ClientID,
+			MessageType:  "message",
+			Message:      message,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, saved.ID)
+	}
+
+	return room, ids
+}
+
+// restErrStatus 에러가 없으면 200
+func restErrStatus(err *errors.RestErr) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	return err.Status
+}
+
+func chatMessageIDs(messages chat.Messages) []int64 {
+	ids := make([]int64, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+	return ids
+}
+
+func TestGetChatMessages_Limit(t *testing.T) {
+	chatApp := NewChatApp(memory.NewRepositories().Chat)
+	room, _ := newTestChatRoom(t, chatApp.chatRepo, "hello")
+
+	tests := []struct {
+		name       string
+		messageID  int64
+		limit      int64
+		wantStatus int
+	}{
+		{name: "zero limit", messageID: 0, limit: 0, wantStatus: http.StatusBadRequest},
+		{name: "negative limit", messageID: 0, limit: -1, wantStatus: http.StatusBadRequest},
+		{name: "limit over max", messageID: 0, limit: maxMessagePageSize + 1, wantStatus: http.StatusBadRequest},
+		{name: "negative message id", messageID: -1, limit: 10, wantStatus: http.StatusBadRequest},
+		{name: "min limit", messageID: 0, limit: 1, wantStatus: http.StatusOK},
+		{name: "max limit", messageID: 0, limit: maxMessagePageSize, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, beforeErr := chatApp.GetChatMessagesBefore(context.Background(), room.ID, tt.messageID, tt.limit)
+			_, afterErr := chatApp.GetChatMessagesAfter(context.Background(), room.ID, tt.messageID, tt.limit)
+
+			if status := restErrStatus(beforeErr); status != tt.wantStatus {
+				t.Errorf("GetChatMessagesBefore status = %d, want %d", status, tt.wantStatus)
+			}
+			if status := restErrStatus(afterErr); status != tt.wantStatus {
+				t.Errorf("GetChatMessagesAfter status = %d, want %d", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestGetChatMessages_Cursor(t *testing.T) {
+	chatApp := NewChatApp(memory.NewRepositories().Chat)
+	room, ids := newTestChatRoom(t, chatApp.chatRepo, "1", "2", "3", "4", "5")
+
+	tests := []struct {
+		name      string
+		before    bool
+		messageID int64
+		limit     int64
+		want      []int64
+	}{
+		{name: "latest", before: true, messageID: 0, limit: 2, want: []int64{ids[4], ids[3]}},
+		{name: "before cursor", before: true, messageID: ids[3], limit: 2, want: []int64{ids[2], ids[1]}},
+		{name: "before first", before: true, messageID: ids[0], limit: 10, want: []int64{}},
+		{name: "after cursor", before: false, messageID: ids[1], limit: 2, want: []int64{ids[2], ids[3]}},
+		{name: "after zero", before: false, messageID: 0, limit: 10, want: ids},
+		{name: "after last", before: false, messageID: ids[4], limit: 10, want: []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getMessages := chatApp.GetChatMessagesAfter
+			if tt.before {
+				getMessages = chatApp.GetChatMessagesBefore
+			}
+
+			messages, err := getMessages(context.Background(), room.ID, tt.messageID, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := chatMessageIDs(messages); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("message ids = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetChatMessageEdits(t *testing.T) {
+	ctx := context.Background()
+	chatApp := NewChatApp(memory.NewRepositories().Chat)
+	room, ids := newTestChatRoom(t, chatApp.chatRepo, "before")
+
+	if _, err := chatApp.chatRepo.EditChatMessage(ctx, room.ID, ids[0], room.ClientID, "after", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	edits, err := chatApp.GetChatMessageEdits(ctx, ids[0], room.HostID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edits) != 1 || edits[0].Message != "before" {
+		t.Errorf("edits = %+v", edits)
+	}
+
+	// 채팅룸 참여자가 아니면 볼 수 없음
+	_, err = chatApp.GetChatMessageEdits(ctx, ids[0], room.HostID+100)
+	if status := restErrStatus(err); status != http.StatusForbidden {
+		t.Errorf("non participant status = %d, want %d", status, http.StatusForbidden)
+	}
+
+	// 삭제된 메시지의 수정 이력은 보여주지 않음
+	if _, err := chatApp.chatRepo.DeleteChatMessage(ctx, room.ID, ids[0], room.ClientID, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	_, err = chatApp.GetChatMessageEdits(ctx, ids[0], room.HostID)
+	if status := restErrStatus(err); status != http.StatusNotFound {
+		t.Errorf("deleted message status = %d, want %d", status, http.StatusNotFound)
+	}
+}
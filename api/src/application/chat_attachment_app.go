@@ -0,0 +1,180 @@
+package application
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/encryption"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/logger"
+	"github.com/google/uuid"
+)
+
+const maxAttachmentFileNameLength = 255
+
+// allowedAttachmentTypes 업로드할 수 있는 파일 형식 (파일 내용으로 판별한 content type 기준)
+var allowedAttachmentTypes = map[string]bool{
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+	"application/zip": true,
+	"text/plain":      true,
+}
+
+type chatAttachmentApp struct {
+	attachmentRepo repository.ChatAttachmentRepository
+	chatRepo       repository.ChatRepository
+	blobStore      repository.BlobStore
+	maxSize        int64
+	urlKey         string
+	urlTTL         time.Duration
+}
+
+var _ ChatAttachmentInterface = &chatAttachmentApp{}
+
+// ChatAttachmentInterface 채팅 첨부파일 업로드/다운로드, 채팅룸 참여자만 접근할 수 있음
+type ChatAttachmentInterface interface {
+	MaxAttachmentSize() int64
+	UploadAttachment(ctx context.Context, uploaderID, chatRoomID int64, fileName string, file io.Reader, size int64) (*entity.ChatAttachment, *errors.RestErr)
+	GetAttachmentURL(ctx context.Context, userID, attachmentID int64) (string, *errors.RestErr)
+	OpenAttachment(ctx context.Context, attachmentID, userID, expires int64, signature string) (*entity.ChatAttachment, io.ReadCloser, *errors.RestErr)
+}
+
+func NewChatAttachmentApp(attachmentRepo repository.ChatAttachmentRepository, chatRepo repository.ChatRepository, blobStore repository.BlobStore,
+	maxSize int64, urlKey string, urlTTL time.Duration) *chatAttachmentApp {
+	return &chatAttachmentApp{
+		attachmentRepo: attachmentRepo,
+		chatRepo:       chatRepo,
+		blobStore:      blobStore,
+		maxSize:        maxSize,
+		urlKey:         urlKey,
+		urlTTL:         urlTTL,
+	}
+}
+
+func (a *chatAttachmentApp) MaxAttachmentSize() int64 {
+	return a.maxSize
+}
+
+func (a *chatAttachmentApp) checkParticipant(ctx context.Context, roomID, userID int64) *errors.RestErr {
+	isParticipant, err := a.chatRepo.IsChatRoomParticipant(ctx, roomID, userID)
+	if err != nil {
+		return err
+	}
+	if !isParticipant {
+		return errors.NewForbiddenError("user is not a participant of the chat room")
+	}
+	return nil
+}
+
+// UploadAttachment 파일을 blob store에 저장하고 메타데이터를 남김, 메시지에는 반환된 id를 attachment_id로 붙여서 보냄
+func (a *chatAttachmentApp) UploadAttachment(ctx context.Context, uploaderID, chatRoomID int64, fileName string, file io.Reader, size int64) (*entity.ChatAttachment, *errors.RestErr) {
+	if err := a.checkParticipant(ctx, chatRoomID, uploaderID); err != nil {
+		return nil, err
+	}
+
+	if size <= 0 {
+		return nil, errors.NewBadRequestError("attachment is empty")
+	}
+	if size > a.maxSize {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("attachment is larger than %d bytes", a.maxSize))
+	}
+
+	fileName = filepath.Base(filepath.Clean("/" + fileName))
+	if fileName == "/" || fileName == "." {
+		return nil, errors.NewBadRequestError("invalid file name")
+	}
+	if len(fileName) > maxAttachmentFileNameLength {
+		return nil, errors.NewBadRequestError("file name is too long")
+	}
+
+	// client가 보낸 content type은 믿지 않고 파일 앞부분으로 판별
+	br := bufio.NewReaderSize(file, 512)
+	head, err := br.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, errors.NewBadRequestError("read attachment error " + err.Error())
+	}
+
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil || !allowedAttachmentTypes[contentType] {
+		return nil, errors.NewBadRequestError("attachment type is not allowed")
+	}
+
+	attachment := entity.ChatAttachment{
+		ChatRoomID:  chatRoomID,
+		UploaderID:  uploaderID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  fmt.Sprintf("chat/%d/%s", chatRoomID, uuid.New().String()),
+	}
+
+	if restErr := a.blobStore.Put(ctx, attachment.StorageKey, br, size, contentType); restErr != nil {
+		return nil, restErr
+	}
+
+	newAttachment, restErr := a.attachmentRepo.SaveAttachment(ctx, &attachment)
+	if restErr != nil {
+		a.blobStore.Delete(logger.Detach(ctx), attachment.StorageKey) // 요청이 취소되어도 파일은 지움
+		return nil, restErr
+	}
+
+	return newAttachment, nil
+}
+
+func (a *chatAttachmentApp) signAttachment(attachmentID, userID, expires int64) string {
+	return encryption.Sign(a.urlKey, fmt.Sprintf("%d:%d:%d", attachmentID, userID, expires))
+}
+
+// GetAttachmentURL 채팅룸 참여자에게 일정 시간 동안만 쓸 수 있는 다운로드 URL을 발급
+func (a *chatAttachmentApp) GetAttachmentURL(ctx context.Context, userID, attachmentID int64) (string, *errors.RestErr) {
+	attachment, err := a.attachmentRepo.GetAttachment(ctx, attachmentID)
+	if err != nil {
+		return "", err
+	}
+
+	if err = a.checkParticipant(ctx, attachment.ChatRoomID, userID); err != nil {
+		return "", err
+	}
+
+	expires := time.Now().Add(a.urlTTL).Unix()
+
+	return fmt.Sprintf("/chat/attachment/attachment_id=%d&user_id=%d&expires=%d&signature=%s",
+		attachment.ID, userID, expires, a.signAttachment(attachment.ID, userID, expires)), nil
+}
+
+// OpenAttachment 서명과 만료 시간을 확인하고, URL을 발급받은 유저가 아직 채팅룸 참여자일 때만 파일을 열어줌
+func (a *chatAttachmentApp) OpenAttachment(ctx context.Context, attachmentID, userID, expires int64, signature string) (*entity.ChatAttachment, io.ReadCloser, *errors.RestErr) {
+	if time.Now().Unix() > expires {
+		return nil, nil, errors.NewForbiddenError("attachment url is expired")
+	}
+	if !encryption.VerifySignature(a.urlKey, fmt.Sprintf("%d:%d:%d", attachmentID, userID, expires), signature) {
+		return nil, nil, errors.NewForbiddenError("invalid attachment url signature")
+	}
+
+	attachment, err := a.attachmentRepo.GetAttachment(ctx, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = a.checkParticipant(ctx, attachment.ChatRoomID, userID); err != nil {
+		return nil, nil, err
+	}
+
+	file, err := a.blobStore.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return attachment, file, nil
+}
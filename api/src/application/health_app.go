@@ -0,0 +1,109 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DependencyStatusUp   = "up"
+	DependencyStatusDown = "down"
+)
+
+// DependencyChecker 요청을 처리하는 데 필요한 서비스 (postgres, redis)
+type DependencyChecker interface {
+	Ping(ctx context.Context) error
+}
+
+type DependencyStatus struct {
+	Status    string `json:"status"` // "up" | "down"
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+type Readiness struct {
+	Ready        bool                        `json:"ready"`
+	ShuttingDown bool                        `json:"shutting_down"`
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+}
+
+type healthApp struct {
+	checkers     map[string]DependencyChecker
+	timeout      time.Duration
+	shuttingDown int32 // atomic
+}
+
+var _ HealthInterface = &healthApp{}
+
+// HealthInterface readiness 확인 (liveness는 프로세스가 응답하면 됨)
+type HealthInterface interface {
+	CheckReadiness(ctx context.Context) Readiness
+	SetShuttingDown()
+}
+
+// NewHealthApp checkers의 key는 응답에 표시되는 이름, timeout은 서비스 하나를 확인하는 최대 시간
+func NewHealthApp(checkers map[string]DependencyChecker, timeout time.Duration) *healthApp {
+	return &healthApp{
+		checkers: checkers,
+		timeout:  timeout,
+	}
+}
+
+// CheckReadiness: 모든 서비스를 동시에 확인, 하나라도 down이거나 종료 중이면 ready가 아님
+func (h *healthApp) CheckReadiness(ctx context.Context) Readiness {
+	readiness := Readiness{
+		Ready:        true,
+		ShuttingDown: atomic.LoadInt32(&h.shuttingDown) == 1,
+		Dependencies: make(map[string]DependencyStatus, len(h.checkers)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, checker := range h.checkers {
+		wg.Add(1)
+		go func(name string, checker DependencyChecker) {
+			defer wg.Done()
+
+			status := h.check(ctx, checker)
+
+			mu.Lock()
+			defer mu.Unlock()
+			readiness.Dependencies[name] = status
+			if status.Status != DependencyStatusUp {
+				readiness.Ready = false
+			}
+		}(name, checker)
+	}
+	wg.Wait()
+
+	if readiness.ShuttingDown {
+		readiness.Ready = false
+	}
+
+	return readiness
+}
+
+func (h *healthApp) check(ctx context.Context, checker DependencyChecker) DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checker.Ping(ctx)
+	status := DependencyStatus{
+		Status:    DependencyStatusUp,
+		LatencyMS: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		status.Status = DependencyStatusDown
+		status.Error = err.Error()
+	}
+
+	return status
+}
+
+// SetShuttingDown: graceful shutdown을 시작하면 호출, 이후 readiness는 항상 false
+func (h *healthApp) SetShuttingDown() {
+	atomic.StoreInt32(&h.shuttingDown, 1)
+}
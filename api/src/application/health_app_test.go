@@ -0,0 +1,49 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeChecker func(ctx context.Context) error
+
+func (f fakeChecker) Ping(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestHealthApp_CheckReadiness(t *testing.T) {
+	up := fakeChecker(func(ctx context.Context) error { return nil })
+	down := fakeChecker(func(ctx context.Context) error { return errors.New("connection refused") })
+	hang := fakeChecker(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	ha := NewHealthApp(map[string]DependencyChecker{"postgres": up, "redis": up}, 50*time.Millisecond)
+	if readiness := ha.CheckReadiness(context.Background()); !readiness.Ready || len(readiness.Dependencies) != 2 {
+		t.Errorf("expected ready, got %+v", readiness)
+	}
+
+	ha = NewHealthApp(map[string]DependencyChecker{"postgres": up, "redis": down}, 50*time.Millisecond)
+	readiness := ha.CheckReadiness(context.Background())
+	if readiness.Ready || readiness.Dependencies["redis"].Status != DependencyStatusDown || readiness.Dependencies["postgres"].Status != DependencyStatusUp {
+		t.Errorf("expected redis down, got %+v", readiness)
+	}
+
+	ha = NewHealthApp(map[string]DependencyChecker{"postgres": hang}, 50*time.Millisecond)
+	start := time.Now()
+	if readiness := ha.CheckReadiness(context.Background()); readiness.Ready {
+		t.Errorf("expected timeout, got %+v", readiness)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("check didn't time out, took %v", elapsed)
+	}
+
+	ha = NewHealthApp(map[string]DependencyChecker{"postgres": up}, 50*time.Millisecond)
+	ha.SetShuttingDown()
+	if readiness := ha.CheckReadiness(context.Background()); readiness.Ready || !readiness.ShuttingDown {
+		t.Errorf("expected not ready while shutting down, got %+v", readiness)
+	}
+}
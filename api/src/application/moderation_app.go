@@ -0,0 +1,174 @@
+package application
+
+import (
+	"context"
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+)
+
+const (
+	defaultReportPageSize = 50
+	maxReportPageSize     = 100
+)
+
+// MessageRemover 모더레이터가 삭제한 메시지를 채팅룸에 접속한 유저들에게 알림 (chat.ChatServer)
+type MessageRemover interface {
+	RemoveMessage(ctx context.Context, chatMessage *entity.ChatMessage) *errors.RestErr
+}
+
+type moderationApp struct {
+	blockRepo    repository.UserBlockRepository
+	reportRepo   repository.ChatReportRepository
+	userRepo     repository.UserRepository
+	chatRepo     repository.ChatRepository
+	remover      MessageRemover
+	moderatorIDs map[int64]bool
+}
+
+var _ ModerationInterface = &moderationApp{}
+
+// ModerationInterface 유저 차단과 채팅 메시지 신고, 신고는 모더레이터만 확인하고 처리할 수 있음
+type ModerationInterface interface {
+	BlockUser(ctx context.Context, blockerID, blockedID int64) *errors.RestErr
+	UnblockUser(ctx context.Context, blockerID, blockedID int64) *errors.RestErr
+	GetBlockedUsers(ctx context.Context, blockerID int64) ([]entity.UserBlock, *errors.RestErr)
+	CheckNotBlocked(ctx context.Context, userID, otherUserID int64) *errors.RestErr
+	ReportMessage(ctx context.Context, report *entity.ChatMessageReport) (*entity.ChatMessageReport, *errors.RestErr)
+	GetReports(ctx context.Context, moderatorID int64, status string, limit int64) ([]entity.ChatMessageReport, *errors.RestErr)
+	ReviewReport(ctx context.Context, moderatorID, reportID int64, status string) (*entity.ChatMessageReport, *errors.RestErr)
+}
+
+func NewModerationApp(blockRepo repository.UserBlockRepository, reportRepo repository.ChatReportRepository, userRepo repository.UserRepository,
+	chatRepo repository.ChatRepository, remover MessageRemover, moderatorIDs []int64) *moderationApp {
+	moderators := make(map[int64]bool)
+	for _, id := range moderatorIDs {
+		moderators[id] = true
+	}
+
+	return &moderationApp{
+		blockRepo:    blockRepo,
+		reportRepo:   reportRepo,
+		userRepo:     userRepo,
+		chatRepo:     chatRepo,
+		remover:      remover,
+		moderatorIDs: moderators,
+	}
+}
+
+func (m *moderationApp) BlockUser(ctx context.Context, blockerID, blockedID int64) *errors.RestErr {
+	if blockerID == blockedID {
+		return errors.NewBadRequestError("can't block yourself")
+	}
+
+	if _, err := m.userRepo.GetUserByID(ctx, blockedID); err != nil {
+		return err
+	}
+
+	return m.blockRepo.BlockUser(ctx, blockerID, blockedID)
+}
+
+func (m *moderationApp) UnblockUser(ctx context.Context, blockerID, blockedID int64) *errors.RestErr {
+	return m.blockRepo.UnblockUser(ctx, blockerID, blockedID)
+}
+
+func (m *moderationApp) GetBlockedUsers(ctx context.Context, blockerID int64) ([]entity.UserBlock, *errors.RestErr) {
+	return m.blockRepo.GetBlockedUsers(ctx, blockerID)
+}
+
+// CheckNotBlocked 둘 중 한 명이라도 상대를 차단했으면 forbidden
+func (m *moderationApp) CheckNotBlocked(ctx context.Context, userID, otherUserID int64) *errors.RestErr {
+	blocked, err := m.blockRepo.IsBlocked(ctx, userID, otherUserID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return errors.NewForbiddenError("user is blocked")
+	}
+
+	return nil
+}
+
+// ReportMessage 메시지가 있는 채팅룸의 참여자만 신고할 수 있음
+func (m *moderationApp) ReportMessage(ctx context.Context, report *entity.ChatMessageReport) (*entity.ChatMessageReport, *errors.RestErr) {
+	if err := report.Validate(); err != nil {
+		return nil, err
+	}
+
+	chatMessage, err := m.chatRepo.GetChatMessage(ctx, report.ChatMessageID)
+	if err != nil {
+		return nil, err
+	}
+	if chatMessage.SenderID == report.ReporterID {
+		return nil, errors.NewBadRequestError("can't report your own message")
+	}
+
+	isParticipant, err := m.chatRepo.IsChatRoomParticipant(ctx, chatMessage.ChatRoomID, report.ReporterID)
+	if err != nil {
+		return nil, err
+	}
+	if !isParticipant {
+		return nil, errors.NewForbiddenError("not a participant of this chatroom")
+	}
+
+	return m.reportRepo.SaveReport(ctx, report)
+}
+
+func (m *moderationApp) checkModerator(userID int64) *errors.RestErr {
+	if !m.moderatorIDs[userID] {
+		return errors.NewForbiddenError("only moderators can review reports")
+	}
+	return nil
+}
+
+func (m *moderationApp) GetReports(ctx context.Context, moderatorID int64, status string, limit int64) ([]entity.ChatMessageReport, *errors.RestErr) {
+	if err := m.checkModerator(moderatorID); err != nil {
+		return nil, err
+	}
+
+	if status != entity.ReportStatusOpen && !entity.IsReviewStatus(status) {
+		return nil, errors.NewBadRequestError("invalid report status")
+	}
+	if limit <= 0 {
+		limit = defaultReportPageSize
+	}
+	if limit > maxReportPageSize {
+		limit = maxReportPageSize
+	}
+
+	return m.reportRepo.GetReports(ctx, status, limit)
+}
+
+// ReviewReport 신고를 처리, removed면 메시지를 삭제하고 채팅룸에 알림 (같은 메시지에 대한 다른 신고도 같이 처리됨)
+func (m *moderationApp) ReviewReport(ctx context.Context, moderatorID, reportID int64, status string) (*entity.ChatMessageReport, *errors.RestErr) {
+	if err := m.checkModerator(moderatorID); err != nil {
+		return nil, err
+	}
+	if !entity.IsReviewStatus(status) {
+		return nil, errors.NewBadRequestError("invalid report status")
+	}
+
+	report, err := m.reportRepo.GetReport(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report.Status != entity.ReportStatusOpen {
+		return nil, errors.NewBadRequestError("report is already reviewed")
+	}
+
+	if status == entity.ReportStatusRemoved {
+		removedMessage, err := m.chatRepo.RemoveChatMessage(ctx, report.ChatMessageID)
+		if err != nil {
+			return nil, err
+		}
+		if err := m.remover.RemoveMessage(ctx, removedMessage); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.reportRepo.ResolveReports(ctx, report.ChatMessageID, moderatorID, status); err != nil {
+		return nil, err
+	}
+
+	return m.reportRepo.GetReport(ctx, reportID)
+}
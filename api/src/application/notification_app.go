@@ -0,0 +1,345 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/chat"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/logger"
+	"github.com/code-wave/go-wave/infrastructure/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	notificationQueueSize       = 1024
+	notificationWorkers         = 4
+	defaultNotificationPageSize = 30
+	maxNotificationPageSize     = 100
+)
+
+// queuedNotification queue에 넣은 메시지와 보낸 쪽의 ctx (요청이 끝나도 취소되지 않음, trace가 이어짐)
+type queuedNotification struct {
+	ctx     context.Context
+	message chat.Message
+}
+
+// pushNotification web push로 보내는 payload (service worker에서 표시)
+type pushNotification struct {
+	Title         string `json:"title"`
+	Body          string `json:"body"`
+	ChatRoomID    int64  `json:"chat_room_id"`
+	ChatMessageID int64  `json:"chat_message_id"`
+}
+
+type notificationApp struct {
+	notificationRepo repository.NotificationRepository
+	chatRepo         repository.ChatRepository
+	presenceRepo     repository.PresenceRepository
+	limiter          repository.NotificationRateLimiter
+	mailer           repository.Mailer
+	pusher           repository.WebPusher
+	queue            chan queuedNotification
+	stop             chan struct{} // Shutdown에서 닫힘
+	stopOnce         sync.Once
+	workers          sync.WaitGroup
+}
+
+var _ NotificationInterface = &notificationApp{}
+var _ chat.MessageNotifier = &notificationApp{}
+
+// NotificationInterface 채팅룸에 접속하지 않은 참여자에게 새 메시지 알림 (in-app, 이메일 요약, web push)
+type NotificationInterface interface {
+	NotifyMessage(ctx context.Context, message chat.Message)
+	Run(digestInterval time.Duration)
+	Shutdown(ctx context.Context) error
+	SendDigests()
+	GetNotifications(ctx context.Context, userID, limit int64) ([]entity.Notification, *errors.RestErr)
+	MarkNotificationsRead(ctx context.Context, userID, lastNotificationID int64) *errors.RestErr
+	GetPreference(ctx context.Context, userID int64) (*entity.NotificationPreference, *errors.RestErr)
+	SavePreference(ctx context.Context, preference *entity.NotificationPreference) *errors.RestErr
+	SavePushSubscription(ctx context.Context, subscription *entity.PushSubscription) (*entity.PushSubscription, *errors.RestErr)
+	DeletePushSubscription(ctx context.Context, userID int64, endpoint string) *errors.RestErr
+}
+
+func NewNotificationApp(notificationRepo repository.NotificationRepository, chatRepo repository.ChatRepository, presenceRepo repository.PresenceRepository,
+	limiter repository.NotificationRateLimiter, mailer repository.Mailer, pusher repository.WebPusher) *notificationApp {
+	return &notificationApp{
+		notificationRepo: notificationRepo,
+		chatRepo:         chatRepo,
+		presenceRepo:     presenceRepo,
+		limiter:          limiter,
+		mailer:           mailer,
+		pusher:           pusher,
+		queue:            make(chan queuedNotification, notificationQueueSize),
+		stop:             make(chan struct{}),
+	}
+}
+
+// NotifyMessage: 채팅룸에서 호출하므로 queue에 넣기만 하고, queue가 가득 차면 알림을 버림
+func (n *notificationApp) NotifyMessage(ctx context.Context, message chat.Message) {
+	select {
+	case <-n.stop:
+		logger.Warn("notification app is stopped, message dropped", logger.F("message_id", message.ID))
+		return
+	default:
+	}
+
+	select {
+	case n.queue <- queuedNotification{ctx: logger.Detach(ctx), message: message}:
+	default:
+		logger.Warn("notification queue is full, message dropped", logger.F("message_id", message.ID))
+	}
+}
+
+// Run: queue를 처리하는 worker와 digestInterval마다 이메일 요약을 보내는 goroutine을 띄움 (Shutdown까지 동작)
+func (n *notificationApp) Run(digestInterval time.Duration) {
+	n.workers.Add(notificationWorkers + 1)
+	for i := 0; i < notificationWorkers; i++ {
+		go n.work()
+	}
+
+	go func() {
+		defer n.workers.Done()
+
+		ticker := time.NewTicker(digestInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				n.SendDigests()
+			case <-n.stop:
+				return
+			}
+		}
+	}()
+}
+
+// work: Shutdown을 호출하면 queue에 남은 알림을 마저 보내고 끝남
+func (n *notificationApp) work() {
+	defer n.workers.Done()
+
+	for {
+		select {
+		case item := <-n.queue:
+			n.notifyParticipants(item.ctx, item.message)
+		case <-n.stop:
+			for {
+				select {
+				case item := <-n.queue:
+					n.notifyParticipants(item.ctx, item.message)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// Shutdown: 새 알림을 받지 않고 worker가 queue를 비울 때까지 기다림 (ctx가 끝나면 ctx.Err())
+func (n *notificationApp) Shutdown(ctx context.Context) error {
+	n.stopOnce.Do(func() {
+		close(n.stop)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		n.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// notifyParticipants: 보낸 유저를 제외하고 채팅룸에 접속하지 않은 참여자에게 설정에 따라 알림을 보냄
+func (n *notificationApp) notifyParticipants(ctx context.Context, message chat.Message) {
+	ctx, span := tracing.Start(ctx, "notification.notify", trace.WithAttributes(attribute.Int64("chat.message_id", message.ID)))
+	defer span.End()
+
+	participantIDs, restErr := n.chatRepo.GetChatRoomParticipantIDs(ctx, message.ChatRoomID)
+	if restErr != nil {
+		logger.Error("get chat room participants error", logger.F("error", restErr.Message), logger.F("chat_room_id", message.ChatRoomID))
+		return
+	}
+
+	for _, userID := range participantIDs {
+		if userID == message.SenderID {
+			continue
+		}
+
+		// 다른 채팅룸에만 접속해 있으면 이 메시지를 보지 못하므로 알림을 보냄
+		connected, restErr := n.presenceRepo.IsConnectedToRoom(ctx, userID, message.ChatRoomID)
+		if restErr != nil {
+			logger.Error("get room presence error", logger.F("error", restErr.Message), logger.F("user_id", userID))
+			continue
+		}
+		if connected {
+			continue
+		}
+
+		preference, restErr := n.notificationRepo.GetPreference(ctx, userID)
+		if restErr != nil {
+			logger.Error("get notification preference error", logger.F("error", restErr.Message), logger.F("user_id", userID))
+			continue
+		}
+		if !preference.InApp && !preference.EmailDigest && !preference.WebPush {
+			continue
+		}
+
+		allowed, restErr := n.limiter.Allow(ctx, userID, message.ChatRoomID)
+		if restErr != nil {
+			logger.Error("notification rate limit error", logger.F("error", restErr.Message), logger.F("user_id", userID))
+			continue
+		}
+		if !allowed {
+			continue
+		}
+
+		notification := entity.Notification{
+			UserID:        userID,
+			Type:          entity.NotificationTypeChatMessage,
+			ChatRoomID:    message.ChatRoomID,
+			ChatMessageID: message.ID,
+			SenderName:    message.SenderName,
+			Message:       messagePreview(message),
+		}
+
+		// 이메일 요약은 저장된 알림 중 읽지 않은 것으로 만들기 때문에 in-app이 꺼져 있어도 저장함
+		if preference.InApp || preference.EmailDigest {
+			if _, restErr := n.notificationRepo.SaveNotification(ctx, &notification); restErr != nil {
+				logger.Error("save notification error", logger.F("error", restErr.Message), logger.F("user_id", userID))
+			}
+		}
+
+		if preference.WebPush {
+			n.push(ctx, userID, notification)
+		}
+	}
+}
+
+func messagePreview(message chat.Message) string {
+	if strings.TrimSpace(message.Message) == "" && message.AttachmentID > 0 {
+		return "(첨부파일)"
+	}
+	return message.Message
+}
+
+// push: 유저의 모든 구독으로 보내고, 만료된 구독은 삭제
+func (n *notificationApp) push(ctx context.Context, userID int64, notification entity.Notification) {
+	subscriptions, restErr := n.notificationRepo.GetPushSubscriptions(ctx, userID)
+	if restErr != nil {
+		logger.Error("get push subscriptions error", logger.F("error", restErr.Message), logger.F("user_id", userID))
+		return
+	}
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(pushNotification{
+		Title:         notification.SenderName,
+		Body:          notification.Message,
+		ChatRoomID:    notification.ChatRoomID,
+		ChatMessageID: notification.ChatMessageID,
+	})
+	if err != nil {
+		logger.Error("marshal error", logger.Err(err))
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		err := n.pusher.Push(subscription, payload)
+		if err == repository.ErrPushSubscriptionGone {
+			if restErr := n.notificationRepo.DeletePushSubscription(ctx, userID, subscription.Endpoint); restErr != nil {
+				logger.Error("delete push subscription error", logger.F("error", restErr.Message), logger.F("user_id", userID))
+			}
+			continue
+		}
+		if err != nil {
+			logger.Error("web push error", logger.Err(err), logger.F("user_id", userID))
+		}
+	}
+}
+
+// SendDigests: 이메일 요약을 켠 유저에게 아직 읽지 않았고 이메일로 보내지 않은 알림을 메일 한 통으로 보냄
+func (n *notificationApp) SendDigests() {
+	ctx, span := tracing.Start(context.Background(), "notification.send_digests")
+	defer span.End()
+
+	digests, restErr := n.notificationRepo.GetPendingDigests(ctx)
+	if restErr != nil {
+		logger.Error("get pending digests error", logger.F("error", restErr.Message))
+		return
+	}
+
+	for _, digest := range digests {
+		if len(digest.Notifications) == 0 {
+			continue
+		}
+
+		subject := fmt.Sprintf("[go-wave] 읽지 않은 메시지 %d개", len(digest.Notifications))
+
+		var body strings.Builder
+		fmt.Fprintf(&body, "%s님, 접속하지 않은 동안 새 메시지가 도착했습니다.\n\n", digest.Nickname)
+		for _, notification := range digest.Notifications {
+			fmt.Fprintf(&body, "[%s] %s: %s\n", notification.CreatedAt, notification.SenderName, notification.Message)
+		}
+
+		if err := n.mailer.Send(digest.Email, subject, body.String()); err != nil {
+			logger.Error("send digest mail error", logger.Err(err), logger.F("user_id", digest.UserID))
+			continue
+		}
+
+		lastNotificationID := digest.Notifications[len(digest.Notifications)-1].ID
+		if restErr := n.notificationRepo.MarkNotificationsEmailed(ctx, digest.UserID, lastNotificationID); restErr != nil {
+			logger.Error("mark notifications emailed error", logger.F("error", restErr.Message), logger.F("user_id", digest.UserID))
+		}
+	}
+}
+
+func (n *notificationApp) GetNotifications(ctx context.Context, userID, limit int64) ([]entity.Notification, *errors.RestErr) {
+	if limit <= 0 {
+		limit = defaultNotificationPageSize
+	}
+	if limit > maxNotificationPageSize {
+		limit = maxNotificationPageSize
+	}
+
+	return n.notificationRepo.GetNotifications(ctx, userID, limit)
+}
+
+func (n *notificationApp) MarkNotificationsRead(ctx context.Context, userID, lastNotificationID int64) *errors.RestErr {
+	return n.notificationRepo.MarkNotificationsRead(ctx, userID, lastNotificationID)
+}
+
+func (n *notificationApp) GetPreference(ctx context.Context, userID int64) (*entity.NotificationPreference, *errors.RestErr) {
+	return n.notificationRepo.GetPreference(ctx, userID)
+}
+
+func (n *notificationApp) SavePreference(ctx context.Context, preference *entity.NotificationPreference) *errors.RestErr {
+	return n.notificationRepo.SavePreference(ctx, preference)
+}
+
+func (n *notificationApp) SavePushSubscription(ctx context.Context, subscription *entity.PushSubscription) (*entity.PushSubscription, *errors.RestErr) {
+	if restErr := subscription.Validate(); restErr != nil {
+		return nil, restErr
+	}
+
+	return n.notificationRepo.SavePushSubscription(ctx, subscription)
+}
+
+func (n *notificationApp) DeletePushSubscription(ctx context.Context, userID int64, endpoint string) *errors.RestErr {
+	return n.notificationRepo.DeletePushSubscription(ctx, userID, endpoint)
+}
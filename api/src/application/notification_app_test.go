@@ -0,0 +1,50 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/code-wave/go-wave/infrastructure/chat"
+	"github.com/code-wave/go-wave/infrastructure/memory"
+)
+
+func TestNotifyParticipants_ConnectedToRoom(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+	room, ids := newTestChatRoom(t, repos.Chat, "hello")
+	notificationApp := NewNotificationApp(repos.Notification, repos.Chat, repos.Presence, memory.NewNotificationLimiter(0), &memory.Mailer{}, &memory.WebPusher{})
+
+	message := chat.Message{ID: ids[0], ChatRoomID: room.ID, SenderID: room.ClientID, SenderName: "client", Message: "hello"}
+	notificationCount := func() int {
+		t.Helper()
+		notifications, restErr := notificationApp.GetNotifications(ctx, room.HostID, 0)
+		if restErr != nil {
+			t.Fatal(restErr)
+		}
+		return len(notifications)
+	}
+
+	// 다른 채팅룸에만 접속해 있으면 알림을 받음
+	if _, restErr := repos.Presence.Connect(ctx, room.HostID, "other-room", []int64{room.ID + 100}, time.Minute); restErr != nil {
+		t.Fatal(restErr)
+	}
+	notificationApp.notifyParticipants(ctx, message)
+	if count := notificationCount(); count != 1 {
+		t.Fatalf("notifications while connected to another room = %d, want 1", count)
+	}
+
+	// 메시지를 보낸 채팅룸에 접속해 있으면 알림을 받지 않음
+	if _, restErr := repos.Presence.Connect(ctx, room.HostID, "this-room", []int64{room.ID}, time.Minute); restErr != nil {
+		t.Fatal(restErr)
+	}
+	notificationApp.notifyParticipants(ctx, message)
+	if count := notificationCount(); count != 1 {
+		t.Errorf("notifications while connected to the room = %d, want 1", count)
+	}
+
+	// 보낸 유저는 알림을 받지 않음
+	if notifications, restErr := notificationApp.GetNotifications(ctx, room.ClientID, 0); restErr != nil || len(notifications) != 0 {
+		t.Errorf("sender notifications = %v, %v", notifications, restErr)
+	}
+}
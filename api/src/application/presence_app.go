@@ -0,0 +1,42 @@
+package application
+
+import (
+	"context"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+)
+
+var _ PresenceAppInterface = &PresenceApp{}
+
+type PresenceApp struct {
+	presenceRepo repository.PresenceRepository
+	chatRepo     repository.ChatRepository
+}
+
+type PresenceAppInterface interface {
+	GetPresence(ctx context.Context, requesterID, userID int64) (*entity.Presence, *errors.RestErr)
+}
+
+func NewPresenceApp(presenceRepo repository.PresenceRepository, chatRepo repository.ChatRepository) *PresenceApp {
+	return &PresenceApp{
+		presenceRepo: presenceRepo,
+		chatRepo:     chatRepo,
+	}
+}
+
+// GetPresence 본인이거나 같은 채팅룸에 참여하고 있는 유저의 접속 상태만 반환
+func (presenceApp *PresenceApp) GetPresence(ctx context.Context, requesterID, userID int64) (*entity.Presence, *errors.RestErr) {
+	if requesterID != userID {
+		shares, err := presenceApp.chatRepo.SharesChatRoom(ctx, requesterID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !shares {
+			return nil, errors.NewForbiddenError("can only see presence of users in your chat rooms")
+		}
+	}
+
+	return presenceApp.presenceRepo.GetPresence(ctx, userID)
+}
@@ -0,0 +1,27 @@
+package application
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/code-wave/go-wave/infrastructure/memory"
+)
+
+func TestGetPresence_SharedChatRoom(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+	room, _ := newTestChatRoom(t, repos.Chat)
+	presenceApp := NewPresenceApp(repos.Presence, repos.Chat)
+
+	for _, requesterID := range []int64{room.ClientID, room.HostID} {
+		if _, restErr := presenceApp.GetPresence(ctx, requesterID, room.HostID); restErr != nil {
+			t.Errorf("GetPresence by %d = %v, want presence", requesterID, restErr)
+		}
+	}
+
+	// 같은 채팅룸에 없는 유저는 볼 수 없음
+	if _, restErr := presenceApp.GetPresence(ctx, room.HostID+100, room.HostID); restErrStatus(restErr) != http.StatusForbidden {
+		t.Errorf("GetPresence by a stranger = %v, want forbidden", restErr)
+	}
+}
@@ -0,0 +1,198 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/chat"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+)
+
+// ChatAnnouncer 서버가 만든 메시지를 채팅룸에 접속한 유저들에게 보냄 (chat.ChatServer)
+type ChatAnnouncer interface {
+	Announce(ctx context.Context, chatMessage *entity.ChatMessage, status string)
+}
+
+type studyTeamApp struct {
+	studyPostRepo repository.StudyPostRepository
+	memberRepo    repository.StudyPostMemberRepository
+	userRepo      repository.UserRepository
+	chatRepo      repository.ChatRepository
+	blockRepo     repository.UserBlockRepository
+	txManager     repository.TxManager
+	announcer     ChatAnnouncer
+}
+
+var _ StudyTeamInterface = &studyTeamApp{}
+
+// StudyTeamInterface 게시글의 팀원 관리, 팀원이 바뀌면 게시글의 팀 채팅룸 참여자도 같이 바뀜
+type StudyTeamInterface interface {
+	AddMember(ctx context.Context, requesterID int64, member *entity.StudyPostMember) *errors.RestErr
+	RemoveMember(ctx context.Context, requesterID, studyPostID, userID int64) *errors.RestErr
+	GetMembers(ctx context.Context, studyPostID int64) (entity.StudyPostMembers, *errors.RestErr)
+}
+
+func NewStudyTeamApp(studyPostRepo repository.StudyPostRepository, memberRepo repository.StudyPostMemberRepository, userRepo repository.UserRepository,
+	chatRepo repository.ChatRepository, blockRepo repository.UserBlockRepository, txManager repository.TxManager, announcer ChatAnnouncer) *studyTeamApp {
+	return &studyTeamApp{
+		studyPostRepo: studyPostRepo,
+		memberRepo:    memberRepo,
+		userRepo:      userRepo,
+		chatRepo:      chatRepo,
+		blockRepo:     blockRepo,
+		txManager:     txManager,
+		announcer:     announcer,
+	}
+}
+
+// AddMember host가 유저를 팀원으로 수락하고 팀 채팅룸에 참여시킴 (팀 채팅룸이 없으면 만듬)
+// 팀원, 채팅룸 참여자, 시스템 메시지는 한 transaction으로 저장하고 commit된 후에 채팅룸에 알림
+func (s *studyTeamApp) AddMember(ctx context.Context, requesterID int64, member *entity.StudyPostMember) *errors.RestErr {
+	if err := member.Validate(); err != nil {
+		return err
+	}
+
+	studyPost, err := s.studyPostRepo.GetPost(ctx, member.StudyPostID)
+	if err != nil {
+		return err
+	}
+
+	if studyPost.UserID != requesterID {
+		return errors.NewForbiddenError("only the host can accept members")
+	}
+	if studyPost.UserID == member.UserID {
+		return errors.NewBadRequestError("host is already in the team")
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, member.UserID)
+	if err != nil {
+		return err
+	}
+
+	blocked, err := s.blockRepo.IsBlocked(ctx, studyPost.UserID, member.UserID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return errors.NewForbiddenError("can't add a blocked user to the team")
+	}
+
+	// 팀 채팅룸은 팀원이 없어도 남아있어도 되므로 transaction 밖에서 만듬 (동시에 만들다 실패해도 transaction이 깨지지 않음)
+	chatRoom, err := s.getOrCreateGroupChatRoom(ctx, studyPost)
+	if err != nil {
+		return err
+	}
+
+	var systemMessage *entity.ChatMessage
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) *errors.RestErr {
+		if err := s.memberRepo.SaveMember(ctx, member); err != nil {
+			return err
+		}
+
+		if err := s.chatRepo.AddChatRoomParticipant(ctx, chatRoom.ID, user.ID); err != nil {
+			return err
+		}
+
+		var restErr *errors.RestErr
+		systemMessage, restErr = s.saveSystemMessage(ctx, chatRoom, user, fmt.Sprintf("%s joined the team", user.Nickname))
+		return restErr
+	})
+	if err != nil {
+		return err
+	}
+	member.Nickname = user.Nickname
+
+	s.announcer.Announce(ctx, systemMessage, chat.SystemStatusJoined)
+
+	return nil
+}
+
+// RemoveMember host가 팀원을 내보내거나 팀원이 스스로 나감, 팀 채팅룸에서도 빠짐 (host는 나갈 수 없음)
+func (s *studyTeamApp) RemoveMember(ctx context.Context, requesterID, studyPostID, userID int64) *errors.RestErr {
+	studyPost, err := s.studyPostRepo.GetPost(ctx, studyPostID)
+	if err != nil {
+		return err
+	}
+
+	if studyPost.UserID != requesterID && userID != requesterID {
+		return errors.NewForbiddenError("only the host or the member can remove the member")
+	}
+	if studyPost.UserID == userID {
+		return errors.NewBadRequestError("host can't leave the team")
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	chatRoom, err := s.chatRepo.GetGroupChatRoom(ctx, studyPostID)
+	if err != nil {
+		if err.Message != errors.ErrNoRows {
+			return err
+		}
+		chatRoom = nil // 팀 채팅룸이 아직 없음
+	}
+
+	var systemMessage *entity.ChatMessage
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) *errors.RestErr {
+		if err := s.memberRepo.DeleteMember(ctx, studyPostID, userID); err != nil {
+			return err
+		}
+		if chatRoom == nil {
+			return nil
+		}
+
+		if err := s.chatRepo.RemoveChatRoomParticipant(ctx, chatRoom.ID, userID); err != nil {
+			return err
+		}
+
+		var restErr *errors.RestErr
+		systemMessage, restErr = s.saveSystemMessage(ctx, chatRoom, user, fmt.Sprintf("%s left the team", user.Nickname))
+		return restErr
+	})
+	if err != nil {
+		return err
+	}
+
+	if systemMessage != nil {
+		s.announcer.Announce(ctx, systemMessage, chat.SystemStatusLeft)
+	}
+
+	return nil
+}
+
+func (s *studyTeamApp) GetMembers(ctx context.Context, studyPostID int64) (entity.StudyPostMembers, *errors.RestErr) {
+	return s.memberRepo.GetMembers(ctx, studyPostID)
+}
+
+func (s *studyTeamApp) getOrCreateGroupChatRoom(ctx context.Context, studyPost *entity.StudyPost) (*entity.ChatRoom, *errors.RestErr) {
+	chatRoom, err := s.chatRepo.GetGroupChatRoom(ctx, studyPost.ID)
+	if err == nil {
+		return chatRoom, nil
+	}
+	if err.Message != errors.ErrNoRows {
+		return nil, err
+	}
+
+	chatRoom, err = s.chatRepo.SaveGroupChatRoom(ctx, studyPost.UserID, studyPost.ID)
+	if err != nil { // 동시에 다른 요청이 먼저 만들었을 수 있음
+		return s.chatRepo.GetGroupChatRoom(ctx, studyPost.ID)
+	}
+
+	return chatRoom, nil
+}
+
+// saveSystemMessage 팀원 변경을 시스템 메시지로 저장 (채팅룸에 알리는 건 commit된 후에 Announce로)
+func (s *studyTeamApp) saveSystemMessage(ctx context.Context, chatRoom *entity.ChatRoom, user *entity.User, text string) (*entity.ChatMessage, *errors.RestErr) {
+	return s.chatRepo.SaveChatMessage(ctx, &entity.ChatMessage{
+		ChatRoomID:   chatRoom.ID,
+		ChatRoomName: chatRoom.RoomName,
+		SenderID:     user.ID,
+		Sender:       user.Nickname,
+		MessageType:  chat.MessageTypeSystem,
+		Message:      text,
+	})
+}
@@ -0,0 +1,125 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/memory"
+)
+
+// recordingAnnouncer Announce로 받은 status를 기록함
+type recordingAnnouncer struct {
+	statuses []string
+}
+
+func (a *recordingAnnouncer) Announce(ctx context.Context, chatMessage *entity.ChatMessage, status string) {
+	a.statuses = append(a.statuses, status)
+}
+
+// failOnSystemMessage 시스템 메시지 저장만 실패하는 chat repository
+type failOnSystemMessage struct {
+	repository.ChatRepository
+}
+
+func (f *failOnSystemMessage) SaveChatMessage(ctx context.Context, chatMessage *entity.ChatMessage) (*entity.ChatMessage, *errors.RestErr) {
+	return nil, errors.NewInternalServerError("database insert error")
+}
+
+// newTestStudyTeam host와 유저 한 명, host의 게시글
+func newTestStudyTeam(t *testing.T) (*memory.Repositories, *entity.StudyPost, *entity.User) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+
+	var users []*entity.User
+	for _, nickname := range []string{"host", "member"} {
+		user := &entity.User{Email: nickname + "@test.com", Password: "password", Name: nickname, Nickname: nickname}
+		if restErr := repos.User.Save(ctx, user); restErr != nil {
+			t.Fatal(restErr)
+		}
+		users = append(users, user)
+	}
+
+	studyPost, restErr := repos.StudyPost.SavePost(ctx, &entity.StudyPost{UserID: users[0].ID, Title: "study title", TechStack: []string{"go"}})
+	if restErr != nil {
+		t.Fatal(restErr)
+	}
+
+	return repos, studyPost, users[1]
+}
+
+func groupChatParticipants(t *testing.T, repos *memory.Repositories, studyPostID int64) []int64 {
+	chatRoom, restErr := repos.Chat.GetGroupChatRoom(context.Background(), studyPostID)
+	if restErr != nil {
+		t.Fatal(restErr)
+	}
+
+	participantIDs, restErr := repos.Chat.GetChatRoomParticipantIDs(context.Background(), chatRoom.ID)
+	if restErr != nil {
+		t.Fatal(restErr)
+	}
+	return participantIDs
+}
+
+func TestStudyTeam_AddMemberRollback(t *testing.T) {
+	ctx := context.Background()
+	repos, studyPost, user := newTestStudyTeam(t)
+	announcer := &recordingAnnouncer{}
+
+	failingChat := &failOnSystemMessage{ChatRepository: repos.Chat}
+	failingApp := NewStudyTeamApp(repos.StudyPost, repos.StudyPostMember, repos.User, failingChat, repos.UserBlock, repos.Tx, announcer)
+	member := &entity.StudyPostMember{StudyPostID: studyPost.ID, UserID: user.ID}
+	if restErr := failingApp.AddMember(ctx, studyPost.UserID, member); restErr == nil {
+		t.Fatal("AddMember should fail when the system message can't be saved")
+	}
+
+	members, restErr := repos.StudyPostMember.GetMembers(ctx, studyPost.ID)
+	if restErr != nil {
+		t.Fatal(restErr)
+	}
+	if len(members) != 0 || len(groupChatParticipants(t, repos, studyPost.ID)) != 1 || len(announcer.statuses) != 0 {
+		t.Fatalf("after failed AddMember: members %v, participants %v, announced %v", members, groupChatParticipants(t, repos, studyPost.ID), announcer.statuses)
+	}
+
+	// 실패한 요청은 전부 되돌려졌으므로 다시 시도하면 성공함
+	studyTeamApp := NewStudyTeamApp(repos.StudyPost, repos.StudyPostMember, repos.User, repos.Chat, repos.UserBlock, repos.Tx, announcer)
+	if restErr := studyTeamApp.AddMember(ctx, studyPost.UserID, member); restErr != nil {
+		t.Fatalf("retry AddMember: %v", restErr)
+	}
+	if participants := groupChatParticipants(t, repos, studyPost.ID); len(participants) != 2 {
+		t.Errorf("participants after AddMember = %v, want host and member", participants)
+	}
+	if fmt.Sprint(announcer.statuses) != "[joined]" {
+		t.Errorf("announced %v, want joined once", announcer.statuses)
+	}
+}
+
+func TestStudyTeam_AddBlockedMember(t *testing.T) {
+	ctx := context.Background()
+	repos, studyPost, user := newTestStudyTeam(t)
+	studyTeamApp := NewStudyTeamApp(repos.StudyPost, repos.StudyPostMember, repos.User, repos.Chat, repos.UserBlock, repos.Tx, &recordingAnnouncer{})
+
+	if restErr := repos.UserBlock.BlockUser(ctx, user.ID, studyPost.UserID); restErr != nil {
+		t.Fatal(restErr)
+	}
+
+	restErr := studyTeamApp.AddMember(ctx, studyPost.UserID, &entity.StudyPostMember{StudyPostID: studyPost.ID, UserID: user.ID})
+	if restErrStatus(restErr) != http.StatusForbidden {
+		t.Errorf("AddMember of a user who blocked the host = %v, want forbidden", restErr)
+	}
+}
+
+func TestStudyTeam_RemoveHost(t *testing.T) {
+	ctx := context.Background()
+	repos, studyPost, _ := newTestStudyTeam(t)
+	studyTeamApp := NewStudyTeamApp(repos.StudyPost, repos.StudyPostMember, repos.User, repos.Chat, repos.UserBlock, repos.Tx, &recordingAnnouncer{})
+
+	restErr := studyTeamApp.RemoveMember(ctx, studyPost.UserID, studyPost.ID, studyPost.UserID)
+	if restErrStatus(restErr) != http.StatusBadRequest {
+		t.Errorf("RemoveMember of the host = %v, want bad request", restErr)
+	}
+}
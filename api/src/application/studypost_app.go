@@ -1,6 +1,7 @@
 package application
 
 import (
+	"context"
 	"github.com/code-wave/go-wave/domain/entity"
 	"github.com/code-wave/go-wave/domain/repository"
 	"github.com/code-wave/go-wave/infrastructure/errors"
@@ -10,80 +11,87 @@ type studyPostApp struct {
 	studyPostRepo          repository.StudyPostRepository // interface
 	techStackRepo          repository.TechStackRepository
 	studyPostTechStackRepo repository.StudyPostTechStackRepository
+	txManager              repository.TxManager
 }
 
 var _ StudyPostInterface = &studyPostApp{}
 
 type StudyPostInterface interface {
-	SavePost(studyPost *entity.StudyPost) *errors.RestErr
-	GetUserIDByPostID(studyPostID int64) (int64, *errors.RestErr)
-	GetPost(id int64) (*entity.StudyPost, *errors.RestErr)
-	GetPostsInLatestOrder(limit, offset int64) (entity.StudyPosts, *errors.RestErr)
-	GetPostsByUserID(userID, limit, offset int64) (entity.StudyPosts, *errors.RestErr)
-	UpdatePost(studyPost *entity.StudyPost) (*entity.StudyPost, *errors.RestErr)
-	DeletePost(studyPostID int64) *errors.RestErr
+	SavePost(ctx context.Context, studyPost *entity.StudyPost) *errors.RestErr
+	GetUserIDByPostID(ctx context.Context, studyPostID int64) (int64, *errors.RestErr)
+	GetPost(ctx context.Context, id int64) (*entity.StudyPost, *errors.RestErr)
+	GetPostsInLatestOrder(ctx context.Context, limit, offset int64) (entity.StudyPosts, *errors.RestErr)
+	GetPostsByUserID(ctx context.Context, userID, limit, offset int64) (entity.StudyPosts, *errors.RestErr)
+	UpdatePost(ctx context.Context, studyPost *entity.StudyPost) (*entity.StudyPost, *errors.RestErr)
+	DeletePost(ctx context.Context, studyPostID int64) *errors.RestErr
 }
 
-func NewStudyPostApp(studyPostRepo repository.StudyPostRepository, techStackRepo repository.TechStackRepository, studyPostTechStackRepo repository.StudyPostTechStackRepository) *studyPostApp {
+func NewStudyPostApp(studyPostRepo repository.StudyPostRepository, techStackRepo repository.TechStackRepository, studyPostTechStackRepo repository.StudyPostTechStackRepository,
+	txManager repository.TxManager) *studyPostApp {
 	return &studyPostApp{
 		studyPostRepo:          studyPostRepo,
 		techStackRepo:          techStackRepo,
 		studyPostTechStackRepo: studyPostTechStackRepo,
+		txManager:              txManager,
 	}
 }
 
 // SavePost study_post 테이블에도 저장하고 study_post_tech_stack 테이블에 (studyPostID, techStackID) 형태로도 저장
-func (s *studyPostApp) SavePost(studyPost *entity.StudyPost) *errors.RestErr {
-	err := s.techStackRepo.CheckTechStack(studyPost.TechStack)
+// 둘 중 하나라도 실패하면 둘 다 저장하지 않음
+func (s *studyPostApp) SavePost(ctx context.Context, studyPost *entity.StudyPost) *errors.RestErr {
+	err := s.techStackRepo.CheckTechStack(ctx, studyPost.TechStack)
 	if err != nil {
 		return err
 	}
 
-	studyPost, err = s.studyPostRepo.SavePost(studyPost)
-	if err != nil {
-		return err
-	}
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) *errors.RestErr {
+		savedPost, err := s.studyPostRepo.SavePost(ctx, studyPost)
+		if err != nil {
+			return err
+		}
 
-	err = s.studyPostTechStackRepo.SaveStudyPostTechStack(studyPost.ID, studyPost.TechStack)
-	if err != nil {
-		return err
-	}
-
-	return nil
+		return s.studyPostTechStackRepo.SaveStudyPostTechStack(ctx, savedPost.ID, savedPost.TechStack)
+	})
 }
 
-func (s *studyPostApp) GetUserIDByPostID(studyPostID int64) (int64, *errors.RestErr) {
-	studyPost, err := s.studyPostRepo.GetPost(studyPostID)
+func (s *studyPostApp) GetUserIDByPostID(ctx context.Context, studyPostID int64) (int64, *errors.RestErr) {
+	studyPost, err := s.studyPostRepo.GetPost(ctx, studyPostID)
 	if err != nil {
 		return 0, err
 	}
 
 	return studyPost.UserID, nil
 }
-func (s *studyPostApp) GetPost(studyPostID int64) (*entity.StudyPost, *errors.RestErr) {
-	return s.studyPostRepo.GetPost(studyPostID)
+func (s *studyPostApp) GetPost(ctx context.Context, studyPostID int64) (*entity.StudyPost, *errors.RestErr) {
+	return s.studyPostRepo.GetPost(ctx, studyPostID)
 }
 
-func (s *studyPostApp) GetPostsInLatestOrder(limit, offset int64) (entity.StudyPosts, *errors.RestErr) {
+func (s *studyPostApp) GetPostsInLatestOrder(ctx context.Context, limit, offset int64) (entity.StudyPosts, *errors.RestErr) {
 	var studyPosts []entity.StudyPost
 	if offset < 0 {
 		return studyPosts, errors.NewBadRequestError("offset can't be negative")
 	}
 
-	return s.studyPostRepo.GetPostsInLatestOrder(limit, offset)
+	return s.studyPostRepo.GetPostsInLatestOrder(ctx, limit, offset)
 }
 
-func (s *studyPostApp) GetPostsByUserID(userID, limit, offset int64) (entity.StudyPosts, *errors.RestErr) {
-	return s.studyPostRepo.GetPostsByUserID(userID, limit, offset)
+func (s *studyPostApp) GetPostsByUserID(ctx context.Context, userID, limit, offset int64) (entity.StudyPosts, *errors.RestErr) {
+	return s.studyPostRepo.GetPostsByUserID(ctx, userID, limit, offset)
 }
 
-func (s *studyPostApp) UpdatePost(studyPost *entity.StudyPost) (*entity.StudyPost, *errors.RestErr) {
-	updatedPost, err := s.studyPostRepo.UpdatePost(studyPost)
-	if err != nil {
-		return nil, err
-	}
+// UpdatePost 게시글과 study_post_tech_stack을 같은 transaction에서 수정
+func (s *studyPostApp) UpdatePost(ctx context.Context, studyPost *entity.StudyPost) (*entity.StudyPost, *errors.RestErr) {
+	var updatedPost *entity.StudyPost
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) *errors.RestErr {
+		var err *errors.RestErr
+		updatedPost, err = s.studyPostRepo.UpdatePost(ctx, studyPost)
+		if err != nil {
+			return err
+		}
 
-	err = s.studyPostTechStackRepo.UpdateStudyPostTechStack(studyPost.ID, studyPost.TechStack)
+		return s.studyPostTechStackRepo.UpdateStudyPostTechStack(ctx, studyPost.ID, studyPost.TechStack)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -91,6 +99,6 @@ func (s *studyPostApp) UpdatePost(studyPost *entity.StudyPost) (*entity.StudyPos
 	return updatedPost, nil
 }
 
-func (s *studyPostApp) DeletePost(studyPostID int64) *errors.RestErr {
-	return s.studyPostRepo.DeletePost(studyPostID)
+func (s *studyPostApp) DeletePost(ctx context.Context, studyPostID int64) *errors.RestErr {
+	return s.studyPostRepo.DeletePost(ctx, studyPostID)
 }
@@ -1,37 +1,34 @@
 package application
 
 import (
+	"context"
 	"github.com/code-wave/go-wave/domain/entity"
-	"github.com/code-wave/go-wave/infrastructure/persistence"
-	"github.com/code-wave/go-wave/utils/config"
-	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/code-wave/go-wave/infrastructure/memory"
 	"log"
 	"net/http"
 	"testing"
 )
 
 var (
-	services *persistence.Repositories
-	err      error
-	//redisService *persistence.RedisService
-	sApp *studyPostApp
+	services *memory.Repositories
+	sApp     *studyPostApp
 )
 
+// init postgres 없이 실행할 수 있도록 메모리 repository를 씀 (postgres 구현과의 차이는 repositorytest가 확인함)
 func init() {
-	services, err = persistence.NewRepositories("pgx", config.Host, config.Port, config.DBUser, config.DBPassword, config.DBName)
-	if err != nil {
-		log.Fatal("init error: ", err.Error())
-	}
+	services = memory.NewRepositories()
+	sApp = NewStudyPostApp(services.StudyPost, services.TechStack, services.StudyPostTechStack, services.Tx)
 
-	//redisService, err = persistence.NewRedisDB(config.RedisHost, config.RedisPort, config.RedisPassword)
-	//if err != nil {
-	//	log.Fatal("init error: ", err.Error())
-	//}
-	sApp = NewStudyPostApp(services.StudyPost, services.TechStack, services.StudyPostTechStack)
+	// TestGetPost가 조회하는 1번 게시글
+	_, restErr := services.StudyPost.SavePost(context.Background(), &entity.StudyPost{UserID: 1, Title: "seed title", TechStack: []string{"go"}})
+	if restErr != nil {
+		log.Fatal("init error: ", restErr.Message)
+	}
 }
 
 func TestSavePost(t *testing.T) {
 	sPost := &entity.StudyPost{
+		ID:           1, // Validate는 POST에도 id를 확인함 (저장할 때 새 id로 바뀜)
 		UserID:       1,
 		Title:        "test title",
 		Topic:        "test topic",
@@ -49,7 +46,7 @@ func TestSavePost(t *testing.T) {
 		t.Error(restErr.Error)
 	}
 
-	err := sApp.SavePost(sPost)
+	err := sApp.SavePost(context.Background(), sPost)
 	if err != nil {
 		t.Error(err)
 	}
@@ -75,7 +72,7 @@ func TestSavePost_WithWrongUserID(t *testing.T) {
 		t.Error("userID is negative but not filtered")
 	}
 
-	//err := sApp.SavePost(sPost)
+	//err := sApp.SavePost(context.Background(), sPost)
 	//if err != nil {
 	//	t.Error(err)
 	//}
@@ -85,13 +82,13 @@ func TestGetPost(t *testing.T) {
 	var studyPostID int64
 
 	studyPostID = -1 // wrong study post id
-	_, err := sApp.GetPost(studyPostID)
+	_, err := sApp.GetPost(context.Background(), studyPostID)
 	if err == nil { // an error will be occurred because of wrong id
 		t.Error(err)
 	}
 
 	studyPostID = 1 // put at least one studypost before testing this
-	_, err = sApp.GetPost(studyPostID)
+	_, err = sApp.GetPost(context.Background(), studyPostID)
 	if err != nil {
 		t.Error(err)
 	}
@@ -100,7 +97,7 @@ func TestGetPost(t *testing.T) {
 func TestGetPostsInLatestOrder(t *testing.T) {
 	var limit, offset int64
 	limit, offset = 10, 0
-	posts, err := sApp.GetPostsInLatestOrder(limit, offset)
+	posts, err := sApp.GetPostsInLatestOrder(context.Background(), limit, offset)
 	if err != nil {
 		t.Error(err)
 	}
@@ -1,6 +1,7 @@
 package application
 
 import (
+	"context"
 	"github.com/code-wave/go-wave/domain/entity"
 	"github.com/code-wave/go-wave/domain/repository"
 	"github.com/code-wave/go-wave/infrastructure/errors"
@@ -15,11 +16,11 @@ type techStackApp struct {
 var _ TechStackInterface = &techStackApp{}
 
 type TechStackInterface interface {
-	SaveTechStack(techName string) *errors.RestErr
-	GetTechStack(id int64) (*entity.TechStack, *errors.RestErr)
-	GetAllTechStack() (entity.TechStacks, *errors.RestErr)
-	GetAllTechStackByStudyPostID(studyPostID int64) (entity.TechStacks, *errors.RestErr)
-	DeleteTechStack(techName string) *errors.RestErr
+	SaveTechStack(ctx context.Context, techName string) *errors.RestErr
+	GetTechStack(ctx context.Context, id int64) (*entity.TechStack, *errors.RestErr)
+	GetAllTechStack(ctx context.Context) (entity.TechStacks, *errors.RestErr)
+	GetAllTechStackByStudyPostID(ctx context.Context, studyPostID int64) (entity.TechStacks, *errors.RestErr)
+	DeleteTechStack(ctx context.Context, techName string) *errors.RestErr
 }
 
 func NewTechStackApp(techStackRepo repository.TechStackRepository) *techStackApp {
@@ -28,28 +29,28 @@ func NewTechStackApp(techStackRepo repository.TechStackRepository) *techStackApp
 	}
 }
 
-func (t *techStackApp) SaveTechStack(techName string) *errors.RestErr {
+func (t *techStackApp) SaveTechStack(ctx context.Context, techName string) *errors.RestErr {
 	techName = strings.ToLower(techName)
-	return t.techStackRepo.SaveTechStack(techName)
+	return t.techStackRepo.SaveTechStack(ctx, techName)
 }
 
-func (t *techStackApp) GetTechStack(id int64) (*entity.TechStack, *errors.RestErr) {
-	return t.techStackRepo.GetTechStack(id)
+func (t *techStackApp) GetTechStack(ctx context.Context, id int64) (*entity.TechStack, *errors.RestErr) {
+	return t.techStackRepo.GetTechStack(ctx, id)
 }
 
-func (t *techStackApp) GetAllTechStack() (entity.TechStacks, *errors.RestErr) {
-	return t.techStackRepo.GetAllTechStack()
+func (t *techStackApp) GetAllTechStack(ctx context.Context) (entity.TechStacks, *errors.RestErr) {
+	return t.techStackRepo.GetAllTechStack(ctx)
 }
 
-func (t *techStackApp) GetAllTechStackByStudyPostID(studyPostID int64) (entity.TechStacks, *errors.RestErr) {
-	return t.techStackRepo.GetAllTechStackByStudyPostID(studyPostID)
+func (t *techStackApp) GetAllTechStackByStudyPostID(ctx context.Context, studyPostID int64) (entity.TechStacks, *errors.RestErr) {
+	return t.techStackRepo.GetAllTechStackByStudyPostID(ctx, studyPostID)
 }
 
-func (t *techStackApp) DeleteTechStack(techName string) *errors.RestErr {
+func (t *techStackApp) DeleteTechStack(ctx context.Context, techName string) *errors.RestErr {
 	err := helpers.CheckStringMinChar(techName, 1)
 	if err != nil {
 		return errors.NewBadRequestError(err.Error())
 	}
 
-	return t.techStackRepo.DeleteTechStack(techName)
+	return t.techStackRepo.DeleteTechStack(ctx, techName)
 }
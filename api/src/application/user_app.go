@@ -1,6 +1,7 @@
 package application
 
 import (
+	"context"
 	"time"
 
 	"github.com/code-wave/go-wave/domain/entity"
@@ -18,16 +19,16 @@ type UserApp struct {
 }
 
 type UserAppInterface interface {
-	SaveUser(*entity.User) (*entity.User, *errors.RestErr)
-	GetUser(int64) (*entity.User, *errors.RestErr)
-	GetUserByID(int64) (*entity.User, *errors.RestErr)
-	GetAllUsers(int64, int64) (entity.Users, *errors.RestErr)
-	UpdateUser(*entity.User) (*entity.User, *errors.RestErr)
-	DeleteUser(int64) *errors.RestErr
-	FindByEmailAndPassword(*entity.User) (*entity.User, *errors.RestErr)
-	LoginUser(*entity.User) (map[string]interface{}, *errors.RestErr)
-	CheckDuplicatedEmail(string) *errors.RestErr
-	CheckDuplicatedNickname(string) *errors.RestErr
+	SaveUser(context.Context, *entity.User) (*entity.User, *errors.RestErr)
+	GetUser(context.Context, int64) (*entity.User, *errors.RestErr)
+	GetUserByID(context.Context, int64) (*entity.User, *errors.RestErr)
+	GetAllUsers(context.Context, int64, int64) (entity.Users, *errors.RestErr)
+	UpdateUser(context.Context, *entity.User) (*entity.User, *errors.RestErr)
+	DeleteUser(context.Context, int64) *errors.RestErr
+	FindByEmailAndPassword(context.Context, *entity.User) (*entity.User, *errors.RestErr)
+	LoginUser(context.Context, *entity.User) (map[string]interface{}, *errors.RestErr)
+	CheckDuplicatedEmail(context.Context, string) *errors.RestErr
+	CheckDuplicatedNickname(context.Context, string) *errors.RestErr
 }
 
 func NewUserApp(ur repository.UserRepository) *UserApp {
@@ -36,7 +37,7 @@ func NewUserApp(ur repository.UserRepository) *UserApp {
 	}
 }
 
-func (ua *UserApp) SaveUser(user *entity.User) (*entity.User, *errors.RestErr) {
+func (ua *UserApp) SaveUser(ctx context.Context, user *entity.User) (*entity.User, *errors.RestErr) {
 	if err := user.Validate(); err != nil {
 		return nil, err
 	}
@@ -46,50 +47,50 @@ func (ua *UserApp) SaveUser(user *entity.User) (*entity.User, *errors.RestErr) {
 		return nil, err
 	}
 
-	if err := ua.ur.Save(user); err != nil {
+	if err := ua.ur.Save(ctx, user); err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
-func (ua *UserApp) GetUser(userID int64) (*entity.User, *errors.RestErr) {
+func (ua *UserApp) GetUser(ctx context.Context, userID int64) (*entity.User, *errors.RestErr) {
 	user := &entity.User{
 		ID: userID,
 	}
 
-	if err := ua.ur.Get(user); err != nil {
+	if err := ua.ur.Get(ctx, user); err != nil {
 		return nil, err
 	}
 
 	return user, nil
 }
 
-func (ua *UserApp) GetUserByID(userID int64) (*entity.User, *errors.RestErr) {
-	return ua.ur.GetUserByID(userID)
+func (ua *UserApp) GetUserByID(ctx context.Context, userID int64) (*entity.User, *errors.RestErr) {
+	return ua.ur.GetUserByID(ctx, userID)
 }
 
-func (ua *UserApp) GetAllUsers(limit, offset int64) (entity.Users, *errors.RestErr) {
-	return ua.ur.GetAll(limit, offset)
+func (ua *UserApp) GetAllUsers(ctx context.Context, limit, offset int64) (entity.Users, *errors.RestErr) {
+	return ua.ur.GetAll(ctx, limit, offset)
 }
 
-func (ua *UserApp) UpdateUser(user *entity.User) (*entity.User, *errors.RestErr) {
+func (ua *UserApp) UpdateUser(ctx context.Context, user *entity.User) (*entity.User, *errors.RestErr) {
 	user.UpdatedAt.Valid = true
 	user.UpdatedAt.String = helpers.GetDateString(time.Now())
 	user.Password, _ = encryption.Hash(user.Password)
 
-	if err := ua.ur.Update(user); err != nil {
+	if err := ua.ur.Update(ctx, user); err != nil {
 		return nil, err
 	}
 
 	return user, nil
 }
 
-func (ua *UserApp) DeleteUser(userID int64) *errors.RestErr {
-	return ua.ur.Delete(userID)
+func (ua *UserApp) DeleteUser(ctx context.Context, userID int64) *errors.RestErr {
+	return ua.ur.Delete(ctx, userID)
 }
 
-func (ua *UserApp) FindByEmailAndPassword(lu *entity.User) (*entity.User, *errors.RestErr) {
-	user, err := ua.ur.FindByEmailAndPassword(lu)
+func (ua *UserApp) FindByEmailAndPassword(ctx context.Context, lu *entity.User) (*entity.User, *errors.RestErr) {
+	user, err := ua.ur.FindByEmailAndPassword(ctx, lu)
 	if err != nil {
 		if err.Message == "wrong, email does not matched" || err.Message == "wrong, password does not matched" {
 			wrongInfoErr := errors.NewWrongInfoError(err.Message)
@@ -101,7 +102,7 @@ func (ua *UserApp) FindByEmailAndPassword(lu *entity.User) (*entity.User, *error
 	return user, nil
 }
 
-func (ua *UserApp) LoginUser(user *entity.User) (map[string]interface{}, *errors.RestErr) {
+func (ua *UserApp) LoginUser(ctx context.Context, user *entity.User) (map[string]interface{}, *errors.RestErr) {
 	token, tokenErr := auth.JwtWrapper.GenerateTokenPair(user.ID)
 	if tokenErr != nil {
 		restErr := errors.NewInternalServerError("token generation error")
@@ -115,9 +116,9 @@ func (ua *UserApp) LoginUser(user *entity.User) (map[string]interface{}, *errors
 	}, nil
 }
 
-func (ua *UserApp) CheckDuplicatedEmail(email string) *errors.RestErr {
+func (ua *UserApp) CheckDuplicatedEmail(ctx context.Context, email string) *errors.RestErr {
 	//err == nil 이면 email이 이미 존재한다는 뜻
-	if err := ua.ur.FindByEmail(email); err == nil {
+	if err := ua.ur.FindByEmail(ctx, email); err == nil {
 		restErr := errors.NewDuplicatedError("duplicated email")
 		return restErr
 	}
@@ -125,9 +126,9 @@ func (ua *UserApp) CheckDuplicatedEmail(email string) *errors.RestErr {
 	return nil
 }
 
-func (ua *UserApp) CheckDuplicatedNickname(nickname string) *errors.RestErr {
+func (ua *UserApp) CheckDuplicatedNickname(ctx context.Context, nickname string) *errors.RestErr {
 	//err == nil 이면 ncikname 이미 존재한다는 뜻
-	if err := ua.ur.FindByNickname(nickname); err == nil {
+	if err := ua.ur.FindByNickname(ctx, nickname); err == nil {
 		restErr := errors.NewDuplicatedError("duplicated nickname")
 		return restErr
 	}
@@ -0,0 +1,13 @@
+package entity
+
+// ChatAttachment 채팅 메시지에 첨부한 파일 정보 (파일 자체는 BlobStore에 저장)
+type ChatAttachment struct {
+	ID          int64  `json:"id"`
+	ChatRoomID  int64  `json:"chat_room_id"`
+	UploaderID  int64  `json:"uploader_id"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	StorageKey  string `json:"-"`
+	CreatedAt   string `json:"created_at"`
+}
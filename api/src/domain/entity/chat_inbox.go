@@ -0,0 +1,11 @@
+package entity
+
+// ChatInboxItem 유저의 대화 목록 항목 (상대방 정보, 게시글 제목, 마지막 메시지)
+type ChatInboxItem struct {
+	ChatRoom       ChatRoom
+	Counterpart    *PublicUser  // 1:1 채팅의 상대방, host면 client, client면 host (팀 채팅이면 nil)
+	Participants   []PublicUser // 팀 채팅의 다른 참여자들 (1:1 채팅이면 nil)
+	StudyPostTitle string
+	UnreadCount    int64
+	LastMessage    *ChatMessage // 메시지가 하나도 없으면 nil
+}
@@ -1,6 +1,9 @@
 package entity
 
+import "database/sql"
+
 type ChatMessage struct {
+	ID           int64
 	ChatRoomID   int64
 	ChatRoomName string
 	SenderID     int64
@@ -8,4 +11,17 @@ type ChatMessage struct {
 	MessageType  string // 필요 없나? (나가기 등 표시할 때)
 	Message      string
 	CreatedAt    string
+	EditedAt     sql.NullString
+	DeletedAt    sql.NullString // soft delete
+	AttachmentID sql.NullInt64
+	// ClientMessageID client가 만든 메시지 ID, 같은 sender가 같은 ID로 다시 보내면 새로 저장하지 않음
+	ClientMessageID sql.NullString
+}
+
+// ChatMessageEdit 메시지를 수정하기 전의 내용
+type ChatMessageEdit struct {
+	ID            int64  `json:"id"`
+	ChatMessageID int64  `json:"chat_message_id"`
+	Message       string `json:"message"`
+	EditedAt      string `json:"edited_at"`
 }
@@ -0,0 +1,17 @@
+package entity
+
+// ChatReadMarker 유저가 채팅룸에서 마지막으로 읽은 메시지
+type ChatReadMarker struct {
+	ChatRoomID        int64
+	UserID            int64
+	LastReadMessageID int64
+	UpdatedAt         string
+}
+
+// UnreadChatRoom 유저가 속한 채팅룸과 안 읽은 메시지 개수, 마지막 메시지
+type UnreadChatRoom struct {
+	ChatRoom          ChatRoom
+	LastReadMessageID int64
+	UnreadCount       int64
+	LastMessage       *ChatMessage // 메시지가 하나도 없으면 nil
+}
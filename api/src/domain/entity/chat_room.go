@@ -1,9 +1,15 @@
 package entity
 
+const (
+	ChatRoomTypeDirect = "direct" // client와 host의 1:1 채팅
+	ChatRoomTypeGroup  = "group"  // 게시글의 팀원들이 함께 쓰는 채팅 (chat_room_participant)
+)
+
 type ChatRoom struct {
 	ID          int64  `json:"id"`
 	RoomName    string `json:"room_name"`
-	ClientID    int64  `json:"client_id"` // 메시지 보내기 누른 사람 ID
+	ClientID    int64  `json:"client_id"` // 메시지 보내기 누른 사람 ID (group이면 host와 같음)
 	HostID      int64  `json:"host_id"`   // 게시글 쓴 사람 ID
 	StudyPostID int64  `json:"study_post_id"`
+	RoomType    string `json:"room_type"`
 }
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/code-wave/go-wave/infrastructure/errors"
+)
+
+const maxReportReasonLength = 500
+
+// 신고 처리 상태
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusDismissed = "dismissed" // 문제 없음
+	ReportStatusRemoved   = "removed"   // 메시지를 삭제함
+)
+
+// UserBlock blocker가 blocked를 차단 (서로 새 1:1 채팅룸을 만들거나 1:1 채팅룸에 메시지를 보낼 수 없음)
+type UserBlock struct {
+	BlockerID int64  `json:"blocker_id"`
+	BlockedID int64  `json:"blocked_id"`
+	Nickname  string `json:"nickname"` // 차단된 유저의 닉네임
+	CreatedAt string `json:"created_at"`
+}
+
+// ChatMessageReport 유저가 신고한 채팅 메시지, 모더레이터가 확인 후 처리함
+type ChatMessageReport struct {
+	ID            int64  `json:"id"`
+	ChatMessageID int64  `json:"chat_message_id"`
+	ChatRoomID    int64  `json:"chat_room_id"`
+	ReporterID    int64  `json:"reporter_id"`
+	Reason        string `json:"reason"`
+	Status        string `json:"status"`
+	SenderID      int64  `json:"sender_id"` // 신고된 메시지를 보낸 유저
+	Sender        string `json:"sender"`
+	Message       string `json:"message"` // 신고된 메시지 내용
+	ReviewedBy    int64  `json:"reviewed_by,omitempty"`
+	ReviewedAt    string `json:"reviewed_at,omitempty"`
+	CreatedAt     string `json:"created_at"`
+}
+
+func (r *ChatMessageReport) Validate() *errors.RestErr {
+	if r.ChatMessageID <= 0 {
+		return errors.NewBadRequestError("chat_message_id is required")
+	}
+
+	r.Reason = strings.TrimSpace(r.Reason)
+	if r.Reason == "" {
+		return errors.NewBadRequestError("reason is required")
+	}
+	if utf8.RuneCountInString(r.Reason) > maxReportReasonLength {
+		return errors.NewBadRequestError("reason is too long")
+	}
+
+	return nil
+}
+
+// IsReviewStatus 모더레이터가 신고를 처리할 때 쓸 수 있는 상태인지
+func IsReviewStatus(status string) bool {
+	return status == ReportStatusDismissed || status == ReportStatusRemoved
+}
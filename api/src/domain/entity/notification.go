@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/code-wave/go-wave/infrastructure/errors"
+)
+
+const NotificationTypeChatMessage = "chat_message"
+
+// Notification 접속하지 않은 유저에게 온 메시지 알림 (in-app 목록, 이메일 요약에 쓰임)
+type Notification struct {
+	ID            int64  `json:"id"`
+	UserID        int64  `json:"user_id"`
+	Type          string `json:"type"`
+	ChatRoomID    int64  `json:"chat_room_id"`
+	ChatMessageID int64  `json:"chat_message_id"`
+	SenderName    string `json:"sender_name"`
+	Message       string `json:"message"` // 메시지 미리보기
+	Read          bool   `json:"read"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// NotificationPreference 유저가 받을 알림 종류
+type NotificationPreference struct {
+	UserID      int64 `json:"user_id"`
+	InApp       bool  `json:"in_app"`
+	EmailDigest bool  `json:"email_digest"`
+	WebPush     bool  `json:"web_push"`
+}
+
+// DefaultNotificationPreference 설정을 저장하지 않은 유저의 알림 설정
+func DefaultNotificationPreference(userID int64) *NotificationPreference {
+	return &NotificationPreference{
+		UserID:      userID,
+		InApp:       true,
+		EmailDigest: false,
+		WebPush:     true,
+	}
+}
+
+// NotificationDigest 이메일로 한 번에 보낼 유저의 읽지 않은 알림들
+type NotificationDigest struct {
+	UserID        int64
+	Email         string
+	Nickname      string
+	Notifications []Notification
+}
+
+// PushSubscription 브라우저의 web push 구독 정보 (PushSubscription.toJSON())
+type PushSubscription struct {
+	ID        int64  `json:"id"`
+	UserID    int64  `json:"user_id"`
+	Endpoint  string `json:"endpoint"`
+	P256dh    string `json:"p256dh"`
+	Auth      string `json:"auth"`
+	CreatedAt string `json:"created_at"`
+}
+
+func (s *PushSubscription) Validate() *errors.RestErr {
+	if !strings.HasPrefix(s.Endpoint, "https://") {
+		return errors.NewBadRequestError("invalid push endpoint")
+	}
+
+	if s.P256dh == "" || s.Auth == "" {
+		return errors.NewBadRequestError("push subscription keys are required")
+	}
+
+	return nil
+}
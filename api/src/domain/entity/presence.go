@@ -0,0 +1,8 @@
+package entity
+
+// Presence 유저의 접속 상태
+type Presence struct {
+	UserID   int64  `json:"user_id"`
+	Online   bool   `json:"online"`
+	LastSeen string `json:"last_seen,omitempty"` // 마지막으로 접속이 끊긴 시간 (접속한 적이 없으면 빈 문자열)
+}
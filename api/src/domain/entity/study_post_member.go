@@ -0,0 +1,25 @@
+package entity
+
+import "github.com/code-wave/go-wave/infrastructure/errors"
+
+// StudyPostMember 게시글의 팀원으로 수락된 유저
+type StudyPostMember struct {
+	StudyPostID int64  `json:"study_post_id"`
+	UserID      int64  `json:"user_id"`
+	Nickname    string `json:"nickname,omitempty"`
+	JoinedAt    string `json:"joined_at,omitempty"`
+}
+
+type StudyPostMembers []StudyPostMember
+
+func (m *StudyPostMember) Validate() *errors.RestErr {
+	if m.StudyPostID <= 0 {
+		return errors.NewBadRequestError("wrong study_post id")
+	}
+
+	if m.UserID <= 0 {
+		return errors.NewBadRequestError("wrong user id")
+	}
+
+	return nil
+}
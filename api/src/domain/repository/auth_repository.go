@@ -1,12 +1,13 @@
 package repository
 
 import (
+	"context"
 	"github.com/code-wave/go-wave/domain/entity"
 	"github.com/code-wave/go-wave/infrastructure/errors"
 )
 
 type AuthRepository interface {
-	Create(*entity.RefreshToken) *errors.RestErr
-	Delete(string) *errors.RestErr
-	Fetch(string) (int64, *errors.RestErr)
+	Create(context.Context, *entity.RefreshToken) *errors.RestErr
+	Delete(context.Context, string) *errors.RestErr
+	Fetch(context.Context, string) (int64, *errors.RestErr)
 }
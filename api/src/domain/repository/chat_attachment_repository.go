@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"io"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+)
+
+type ChatAttachmentRepository interface {
+	SaveAttachment(ctx context.Context, attachment *entity.ChatAttachment) (*entity.ChatAttachment, *errors.RestErr)
+	GetAttachment(ctx context.Context, id int64) (*entity.ChatAttachment, *errors.RestErr)
+	DeleteAttachment(ctx context.Context, id int64) *errors.RestErr
+}
+
+// BlobStore 첨부파일 원본을 저장하는 저장소 (로컬 파일시스템, S3 호환 스토리지 등)
+// key는 "chat/{chat_room_id}/{uuid}" 형태로 S3 object key로도 그대로 쓸 수 있음
+// Put은 ctx가 끝나면 저장을 멈추고 쓰던 파일을 남기지 않음
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) *errors.RestErr
+	Get(ctx context.Context, key string) (io.ReadCloser, *errors.RestErr)
+	Delete(ctx context.Context, key string) *errors.RestErr
+}
@@ -1,15 +1,39 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/code-wave/go-wave/domain/entity"
 	"github.com/code-wave/go-wave/infrastructure/errors"
 )
 
 type ChatRepository interface {
-	GetChatRoom(clientID, hostID, studyPostID int64) (*entity.ChatRoom, *errors.RestErr)
-	SaveChatRoom(clientID, hostID, studyPostID int64) (*entity.ChatRoom, *errors.RestErr)
-	GetChatRoomByRoomName(roomName string) (*entity.ChatRoom, *errors.RestErr)
-	GetChatRoomByID(id int64) (*entity.ChatRoom, *errors.RestErr)
-	SaveChatMessage(msg *entity.ChatMessage) (*entity.ChatMessage, *errors.RestErr)
-	GetChatMessages(roomID int64) ([]entity.ChatMessage, *errors.RestErr)
+	GetChatRoom(ctx context.Context, clientID, hostID, studyPostID int64) (*entity.ChatRoom, *errors.RestErr)
+	SaveChatRoom(ctx context.Context, clientID, hostID, studyPostID int64) (*entity.ChatRoom, *errors.RestErr)
+	GetGroupChatRoom(ctx context.Context, studyPostID int64) (*entity.ChatRoom, *errors.RestErr)
+	SaveGroupChatRoom(ctx context.Context, hostID, studyPostID int64) (*entity.ChatRoom, *errors.RestErr)
+	AddChatRoomParticipant(ctx context.Context, roomID, userID int64) *errors.RestErr
+	RemoveChatRoomParticipant(ctx context.Context, roomID, userID int64) *errors.RestErr
+	IsChatRoomParticipant(ctx context.Context, roomID, userID int64) (bool, *errors.RestErr)
+	GetChatRoomParticipantIDs(ctx context.Context, roomID int64) ([]int64, *errors.RestErr)
+	// SharesChatRoom 두 유저가 같이 참여하고 있는 채팅룸이 있는지 확인
+	SharesChatRoom(ctx context.Context, userID, otherUserID int64) (bool, *errors.RestErr)
+	GetChatRoomByRoomName(ctx context.Context, roomName string) (*entity.ChatRoom, *errors.RestErr)
+	GetChatRoomByID(ctx context.Context, id int64) (*entity.ChatRoom, *errors.RestErr)
+	SaveChatMessage(ctx context.Context, msg *entity.ChatMessage) (*entity.ChatMessage, *errors.RestErr)
+	// GetChatMessageByClientMessageID client_message_id는 채팅룸과 보낸 유저마다 유일함
+	GetChatMessageByClientMessageID(ctx context.Context, roomID, senderID int64, clientMessageID string) (*entity.ChatMessage, *errors.RestErr)
+	GetChatMessages(ctx context.Context, roomID int64) ([]entity.ChatMessage, *errors.RestErr)
+	GetChatMessagesBefore(ctx context.Context, roomID, messageID, limit int64) ([]entity.ChatMessage, *errors.RestErr)
+	GetChatMessagesAfter(ctx context.Context, roomID, messageID, limit int64) ([]entity.ChatMessage, *errors.RestErr)
+	ForEachChatMessage(ctx context.Context, roomID int64, fn func(entity.ChatMessage) error) *errors.RestErr
+	SaveReadMarker(ctx context.Context, marker *entity.ChatReadMarker) *errors.RestErr
+	GetUnreadChatRooms(ctx context.Context, userID int64) ([]entity.UnreadChatRoom, *errors.RestErr)
+	GetChatInbox(ctx context.Context, userID int64) ([]entity.ChatInboxItem, *errors.RestErr)
+	EditChatMessage(ctx context.Context, roomID, messageID, senderID int64, message string, editWindow time.Duration) (*entity.ChatMessage, *errors.RestErr)
+	DeleteChatMessage(ctx context.Context, roomID, messageID, senderID int64, editWindow time.Duration) (*entity.ChatMessage, *errors.RestErr)
+	RemoveChatMessage(ctx context.Context, messageID int64) (*entity.ChatMessage, *errors.RestErr)
+	GetChatMessage(ctx context.Context, messageID int64) (*entity.ChatMessage, *errors.RestErr)
+	GetChatMessageEdits(ctx context.Context, messageID int64) ([]entity.ChatMessageEdit, *errors.RestErr)
 }
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+)
+
+type UserBlockRepository interface {
+	BlockUser(ctx context.Context, blockerID, blockedID int64) *errors.RestErr
+	UnblockUser(ctx context.Context, blockerID, blockedID int64) *errors.RestErr
+	GetBlockedUsers(ctx context.Context, blockerID int64) ([]entity.UserBlock, *errors.RestErr)
+	// IsBlocked 둘 중 한 명이라도 상대를 차단했으면 true
+	IsBlocked(ctx context.Context, userID, otherUserID int64) (bool, *errors.RestErr)
+	// IsDirectChatRoomBlocked 1:1 채팅룸의 두 유저 중 한 명이라도 상대를 차단했으면 true (팀 채팅룸은 항상 false)
+	IsDirectChatRoomBlocked(ctx context.Context, roomName string) (bool, *errors.RestErr)
+}
+
+type ChatReportRepository interface {
+	SaveReport(ctx context.Context, report *entity.ChatMessageReport) (*entity.ChatMessageReport, *errors.RestErr)
+	GetReport(ctx context.Context, reportID int64) (*entity.ChatMessageReport, *errors.RestErr)
+	GetReports(ctx context.Context, status string, limit int64) ([]entity.ChatMessageReport, *errors.RestErr)
+	// ResolveReports 같은 메시지에 대한 처리되지 않은 신고를 모두 status로 처리
+	ResolveReports(ctx context.Context, chatMessageID, moderatorID int64, status string) *errors.RestErr
+}
+
+// MessageRateLimiter 유저가 일정 시간 동안 보낼 수 있는 메시지 수를 제한 (모든 인스턴스, 연결을 합쳐서)
+type MessageRateLimiter interface {
+	Allow(ctx context.Context, userID int64) (bool, *errors.RestErr)
+}
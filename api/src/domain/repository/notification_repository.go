@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+)
+
+// ErrPushSubscriptionGone push 서비스가 더 이상 유효하지 않다고 응답한 구독 (삭제해야 함)
+var ErrPushSubscriptionGone = errors.NewError("push subscription is gone")
+
+type NotificationRepository interface {
+	SaveNotification(ctx context.Context, notification *entity.Notification) (*entity.Notification, *errors.RestErr)
+	GetNotifications(ctx context.Context, userID, limit int64) ([]entity.Notification, *errors.RestErr)
+	MarkNotificationsRead(ctx context.Context, userID, lastNotificationID int64) *errors.RestErr
+	GetPendingDigests(ctx context.Context) ([]entity.NotificationDigest, *errors.RestErr)
+	MarkNotificationsEmailed(ctx context.Context, userID, lastNotificationID int64) *errors.RestErr
+	GetPreference(ctx context.Context, userID int64) (*entity.NotificationPreference, *errors.RestErr)
+	SavePreference(ctx context.Context, preference *entity.NotificationPreference) *errors.RestErr
+	SavePushSubscription(ctx context.Context, subscription *entity.PushSubscription) (*entity.PushSubscription, *errors.RestErr)
+	GetPushSubscriptions(ctx context.Context, userID int64) ([]entity.PushSubscription, *errors.RestErr)
+	DeletePushSubscription(ctx context.Context, userID int64, endpoint string) *errors.RestErr
+}
+
+// NotificationRateLimiter 같은 채팅룸의 알림을 일정 시간에 한 번만 보내도록 제한
+type NotificationRateLimiter interface {
+	Allow(ctx context.Context, userID, chatRoomID int64) (bool, *errors.RestErr)
+}
+
+// Mailer 이메일 발송 (SMTP 등)
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// WebPusher web push 발송, 구독이 만료되었으면 ErrPushSubscriptionGone을 반환
+type WebPusher interface {
+	Push(subscription entity.PushSubscription, payload []byte) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+)
+
+// PresenceRepository roomIDs는 연결이 접속한 채팅룸들 (채팅룸별 접속 여부는 IsConnectedToRoom으로 확인)
+type PresenceRepository interface {
+	Connect(ctx context.Context, userID int64, connID string, roomIDs []int64, ttl time.Duration) (bool, *errors.RestErr)
+	Refresh(ctx context.Context, userID int64, connID string, roomIDs []int64, ttl time.Duration) *errors.RestErr
+	Disconnect(ctx context.Context, userID int64, connID string, roomIDs []int64) (bool, *errors.RestErr)
+	GetPresence(ctx context.Context, userID int64) (*entity.Presence, *errors.RestErr)
+	GetConnections(ctx context.Context, userID int64) ([]string, *errors.RestErr)
+	IsConnectedToRoom(ctx context.Context, userID, roomID int64) (bool, *errors.RestErr)
+}
@@ -0,0 +1,47 @@
+package repositorytest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func testBlobStore(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	key := "chat/1/" + unique()
+
+	noErr(t, r.BlobStore.Put(ctx, key, strings.NewReader("hello"), 5, "text/plain"))
+	// 같은 key에 덮어쓰지 않음
+	wantStatus(t, r.BlobStore.Put(ctx, key, strings.NewReader("again"), 5, "text/plain"), 0)
+	wantStatus(t, r.BlobStore.Put(ctx, "chat/1/"+unique(), strings.NewReader("short"), 10, "text/plain"), 0)
+	wantStatus(t, r.BlobStore.Put(ctx, "chat/1/"+unique(), strings.NewReader("too long"), 3, "text/plain"), 0)
+
+	rc, restErr := r.BlobStore.Get(ctx, key)
+	noErr(t, restErr)
+	data, err := ioutil.ReadAll(rc)
+	rc.Close()
+	if err != nil || string(data) != "hello" {
+		t.Errorf("Get = %q, %v, want hello", data, err)
+	}
+
+	_, restErr = r.BlobStore.Get(ctx, "chat/1/"+unique())
+	wantStatus(t, restErr, http.StatusNotFound)
+	_, restErr = r.BlobStore.Get(ctx, "../"+unique())
+	wantStatus(t, restErr, http.StatusBadRequest)
+	wantStatus(t, r.BlobStore.Put(ctx, "../"+unique(), strings.NewReader("x"), 1, "text/plain"), http.StatusBadRequest)
+
+	noErr(t, r.BlobStore.Delete(ctx, key))
+	noErr(t, r.BlobStore.Delete(ctx, key))
+	_, restErr = r.BlobStore.Get(ctx, key)
+	wantStatus(t, restErr, http.StatusNotFound)
+
+	// 취소된 요청의 파일은 저장하지 않음
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	key = "chat/1/" + unique()
+	wantStatus(t, r.BlobStore.Put(canceled, key, strings.NewReader("hello"), 5, "text/plain"), 0)
+	_, restErr = r.BlobStore.Get(ctx, key)
+	wantStatus(t, restErr, http.StatusNotFound)
+}
@@ -0,0 +1,430 @@
+package repositorytest
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+)
+
+func testChatRoom(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	client, host := newUser(t, r), newUser(t, r)
+	post := newStudyPost(t, r, host.ID)
+
+	_, restErr := r.Chat.GetChatRoom(ctx, client.ID, host.ID, post.ID)
+	wantStatus(t, restErr, http.StatusOK)
+	if restErr.Message != errors.ErrNoRows {
+		t.Errorf("GetChatRoom without room = %s, want %s", restErr.Message, errors.ErrNoRows)
+	}
+
+	room, restErr := r.Chat.SaveChatRoom(ctx, client.ID, host.ID, post.ID)
+	noErr(t, restErr)
+	if room.ID == 0 || room.RoomName == "" || room.RoomType != entity.ChatRoomTypeDirect {
+		t.Fatalf("SaveChatRoom = %+v", room)
+	}
+
+	byIDs, restErr := r.Chat.GetChatRoom(ctx, client.ID, host.ID, post.ID)
+	noErr(t, restErr)
+	byName, restErr := r.Chat.GetChatRoomByRoomName(ctx, room.RoomName)
+	noErr(t, restErr)
+	byID, restErr := r.Chat.GetChatRoomByID(ctx, room.ID)
+	noErr(t, restErr)
+	for _, got := range []*entity.ChatRoom{byIDs, byName, byID} {
+		if *got != *room {
+			t.Errorf("got chat room %+v, want %+v", got, room)
+		}
+	}
+	_, restErr = r.Chat.GetChatRoomByRoomName(ctx, "missing-"+unique())
+	wantStatus(t, restErr, http.StatusNotFound)
+	_, restErr = r.Chat.GetChatRoomByID(ctx, -1)
+	wantStatus(t, restErr, http.StatusNotFound)
+
+	stranger := newUser(t, r)
+	for userID, want := range map[int64]bool{client.ID: true, host.ID: true, stranger.ID: false} {
+		isParticipant, restErr := r.Chat.IsChatRoomParticipant(ctx, room.ID, userID)
+		noErr(t, restErr)
+		if isParticipant != want {
+			t.Errorf("IsChatRoomParticipant(%d) = %v, want %v", userID, isParticipant, want)
+		}
+
+		shares, restErr := r.Chat.SharesChatRoom(ctx, client.ID, userID)
+		noErr(t, restErr)
+		if shares != want {
+			t.Errorf("SharesChatRoom(client, %d) = %v, want %v", userID, shares, want)
+		}
+	}
+	participantIDs, restErr := r.Chat.GetChatRoomParticipantIDs(ctx, room.ID)
+	noErr(t, restErr)
+	if ids := sortedIDs(participantIDs); !reflect.DeepEqual(ids, sortedIDs([]int64{client.ID, host.ID})) {
+		t.Errorf("GetChatRoomParticipantIDs = %v, want client and host", ids)
+	}
+
+	// 팀 채팅룸은 게시글마다 하나, host는 만들 때 참여자로 추가됨
+	_, restErr = r.Chat.GetGroupChatRoom(ctx, post.ID)
+	wantStatus(t, restErr, http.StatusOK)
+	group, restErr := r.Chat.SaveGroupChatRoom(ctx, host.ID, post.ID)
+	noErr(t, restErr)
+	if group.RoomType != entity.ChatRoomTypeGroup || group.HostID != host.ID || group.ClientID != host.ID {
+		t.Errorf("SaveGroupChatRoom = %+v", group)
+	}
+	_, restErr = r.Chat.SaveGroupChatRoom(ctx, host.ID, post.ID)
+	wantStatus(t, restErr, 0)
+	got, restErr := r.Chat.GetGroupChatRoom(ctx, post.ID)
+	noErr(t, restErr)
+	if got.ID != group.ID {
+		t.Errorf("GetGroupChatRoom id = %d, want %d", got.ID, group.ID)
+	}
+
+	noErr(t, r.Chat.AddChatRoomParticipant(ctx, group.ID, client.ID))
+	noErr(t, r.Chat.AddChatRoomParticipant(ctx, group.ID, client.ID))
+	participantIDs, restErr = r.Chat.GetChatRoomParticipantIDs(ctx, group.ID)
+	noErr(t, restErr)
+	if ids := sortedIDs(participantIDs); !reflect.DeepEqual(ids, sortedIDs([]int64{client.ID, host.ID})) {
+		t.Errorf("GetChatRoomParticipantIDs of group = %v, want host and client", ids)
+	}
+
+	noErr(t, r.Chat.RemoveChatRoomParticipant(ctx, group.ID, client.ID))
+	isParticipant, restErr := r.Chat.IsChatRoomParticipant(ctx, group.ID, client.ID)
+	noErr(t, restErr)
+	if isParticipant {
+		t.Error("client should not be a participant after RemoveChatRoomParticipant")
+	}
+}
+
+func testChatMessage(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	chat := newDirectChat(t, r)
+	first := chat.send(t, r, chat.client, "first")
+	second := chat.send(t, r, chat.host, "second")
+	third := chat.send(t, r, chat.client, "third")
+	if first.CreatedAt == "" || first.EditedAt.Valid || first.DeletedAt.Valid {
+		t.Errorf("SaveChatMessage = %+v", first)
+	}
+
+	msgs, restErr := r.Chat.GetChatMessages(ctx, chat.room.ID)
+	noErr(t, restErr)
+	if ids := messageIDs(msgs); len(ids) != 3 || !contains(messageTexts(msgs), "second") {
+		t.Errorf("GetChatMessages ids = %v", ids)
+	}
+
+	msgs, restErr = r.Chat.GetChatMessagesBefore(ctx, chat.room.ID, 0, 2)
+	noErr(t, restErr)
+	if ids := messageIDs(msgs); !reflect.DeepEqual(ids, []int64{third.ID, second.ID}) {
+		t.Errorf("GetChatMessagesBefore(0, 2) = %v, want [%d %d]", ids, third.ID, second.ID)
+	}
+	msgs, restErr = r.Chat.GetChatMessagesBefore(ctx, chat.room.ID, third.ID, 10)
+	noErr(t, restErr)
+	if ids := messageIDs(msgs); !reflect.DeepEqual(ids, []int64{second.ID, first.ID}) {
+		t.Errorf("GetChatMessagesBefore(third, 10) = %v, want [%d %d]", ids, second.ID, first.ID)
+	}
+	msgs, restErr = r.Chat.GetChatMessagesAfter(ctx, chat.room.ID, first.ID, 10)
+	noErr(t, restErr)
+	if ids := messageIDs(msgs); !reflect.DeepEqual(ids, []int64{second.ID, third.ID}) {
+		t.Errorf("GetChatMessagesAfter(first, 10) = %v, want [%d %d]", ids, second.ID, third.ID)
+	}
+
+	var visited []int64
+	noErr(t, r.Chat.ForEachChatMessage(ctx, chat.room.ID, func(m entity.ChatMessage) error {
+		visited = append(visited, m.ID)
+		return nil
+	}))
+	if !reflect.DeepEqual(visited, []int64{first.ID, second.ID, third.ID}) {
+		t.Errorf("ForEachChatMessage visited %v", visited)
+	}
+	restErr = r.Chat.ForEachChatMessage(ctx, chat.room.ID, func(m entity.ChatMessage) error {
+		return errors.NewError("stop")
+	})
+	wantStatus(t, restErr, http.StatusInternalServerError)
+
+	got, restErr := r.Chat.GetChatMessage(ctx, second.ID)
+	noErr(t, restErr)
+	if got.Message != "second" || got.SenderID != chat.host.ID || got.ChatRoomName != chat.room.RoomName {
+		t.Errorf("GetChatMessage = %+v", got)
+	}
+	_, restErr = r.Chat.GetChatMessage(ctx, -1)
+	wantStatus(t, restErr, http.StatusNotFound)
+
+	// 같은 client_message_id로 다시 보내면 처음 저장한 메시지를 반환
+	clientMessageID := unique()
+	retry := &entity.ChatMessage{
+		ChatRoomID:      chat.room.ID,
+		ChatRoomName:    chat.room.RoomName,
+		SenderID:        chat.client.ID,
+		Sender:          chat.client.Nickname,
+		MessageType:     "message",
+		Message:         "retry",
+		ClientMessageID: nullString(clientMessageID),
+	}
+	saved, restErr := r.Chat.SaveChatMessage(ctx, retry)
+	noErr(t, restErr)
+	again, restErr := r.Chat.SaveChatMessage(ctx, retry)
+	noErr(t, restErr)
+	if again.ID != saved.ID {
+		t.Errorf("SaveChatMessage with the same client_message_id saved a new message %d, want %d", again.ID, saved.ID)
+	}
+	byClientID, restErr := r.Chat.GetChatMessageByClientMessageID(ctx, chat.room.ID, chat.client.ID, clientMessageID)
+	noErr(t, restErr)
+	if byClientID.ID != saved.ID {
+		t.Errorf("GetChatMessageByClientMessageID id = %d, want %d", byClientID.ID, saved.ID)
+	}
+	_, restErr = r.Chat.GetChatMessageByClientMessageID(ctx, chat.room.ID, chat.host.ID, clientMessageID)
+	wantStatus(t, restErr, http.StatusNotFound)
+
+	// 다른 채팅룸에 같은 client_message_id로 보낸 메시지는 따로 저장됨
+	otherRoom, restErr := r.Chat.SaveChatRoom(ctx, chat.client.ID, chat.host.ID, newStudyPost(t, r, chat.host.ID).ID)
+	noErr(t, restErr)
+	inOtherRoom, restErr := r.Chat.SaveChatMessage(ctx, &entity.ChatMessage{
+		ChatRoomID:      otherRoom.ID,
+		ChatRoomName:    otherRoom.RoomName,
+		SenderID:        chat.client.ID,
+		Sender:          chat.client.Nickname,
+		MessageType:     "message",
+		Message:         "retry",
+		ClientMessageID: nullString(clientMessageID),
+	})
+	noErr(t, restErr)
+	if inOtherRoom.ID == saved.ID || inOtherRoom.ChatRoomID != otherRoom.ID {
+		t.Errorf("SaveChatMessage in another room = %+v, want a new message in room %d", inOtherRoom, otherRoom.ID)
+	}
+	byClientID, restErr = r.Chat.GetChatMessageByClientMessageID(ctx, otherRoom.ID, chat.client.ID, clientMessageID)
+	noErr(t, restErr)
+	if byClientID.ID != inOtherRoom.ID {
+		t.Errorf("GetChatMessageByClientMessageID in another room id = %d, want %d", byClientID.ID, inOtherRoom.ID)
+	}
+}
+
+func testChatMessageEdit(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	chat := newDirectChat(t, r)
+	msg := chat.send(t, r, chat.client, "before")
+
+	_, restErr := r.Chat.EditChatMessage(ctx, chat.room.ID, msg.ID, chat.host.ID, "not mine", time.Hour)
+	wantStatus(t, restErr, http.StatusForbidden)
+	_, restErr = r.Chat.EditChatMessage(ctx, chat.room.ID, msg.ID, chat.client.ID, "too late", -time.Hour)
+	wantStatus(t, restErr, http.StatusForbidden)
+	_, restErr = r.Chat.EditChatMessage(ctx, chat.room.ID, -1, chat.client.ID, "missing", time.Hour)
+	wantStatus(t, restErr, http.StatusNotFound)
+	// 다른 채팅룸의 메시지는 수정, 삭제할 수 없음
+	otherRoom := newDirectChat(t, r)
+	_, restErr = r.Chat.EditChatMessage(ctx, otherRoom.room.ID, msg.ID, chat.client.ID, "other room", time.Hour)
+	wantStatus(t, restErr, http.StatusNotFound)
+	_, restErr = r.Chat.DeleteChatMessage(ctx, otherRoom.room.ID, msg.ID, chat.client.ID, time.Hour)
+	wantStatus(t, restErr, http.StatusNotFound)
+
+	edited, restErr := r.Chat.EditChatMessage(ctx, chat.room.ID, msg.ID, chat.client.ID, "after", time.Hour)
+	noErr(t, restErr)
+	if edited.Message != "after" || !edited.EditedAt.Valid {
+		t.Errorf("EditChatMessage = %+v", edited)
+	}
+	edits, restErr := r.Chat.GetChatMessageEdits(ctx, msg.ID)
+	noErr(t, restErr)
+	if len(edits) != 1 || edits[0].Message != "before" || edits[0].ChatMessageID != msg.ID {
+		t.Errorf("GetChatMessageEdits = %+v", edits)
+	}
+
+	_, restErr = r.Chat.DeleteChatMessage(ctx, chat.room.ID, msg.ID, chat.host.ID, time.Hour)
+	wantStatus(t, restErr, http.StatusForbidden)
+	deleted, restErr := r.Chat.DeleteChatMessage(ctx, chat.room.ID, msg.ID, chat.client.ID, time.Hour)
+	noErr(t, restErr)
+	if !deleted.DeletedAt.Valid || deleted.Message != "after" {
+		t.Errorf("DeleteChatMessage = %+v", deleted)
+	}
+	_, restErr = r.Chat.EditChatMessage(ctx, chat.room.ID, msg.ID, chat.client.ID, "again", time.Hour)
+	wantStatus(t, restErr, http.StatusBadRequest)
+	_, restErr = r.Chat.DeleteChatMessage(ctx, chat.room.ID, msg.ID, chat.client.ID, time.Hour)
+	wantStatus(t, restErr, http.StatusBadRequest)
+
+	// 모더레이터는 보낸 사람, 수정 가능 시간과 상관없이 삭제할 수 있음
+	other := chat.send(t, r, chat.host, "reported")
+	removed, restErr := r.Chat.RemoveChatMessage(ctx, other.ID)
+	noErr(t, restErr)
+	if !removed.DeletedAt.Valid {
+		t.Errorf("RemoveChatMessage = %+v", removed)
+	}
+	again, restErr := r.Chat.RemoveChatMessage(ctx, other.ID)
+	noErr(t, restErr)
+	if again.DeletedAt != removed.DeletedAt {
+		t.Errorf("RemoveChatMessage should keep the first deleted_at, got %v want %v", again.DeletedAt, removed.DeletedAt)
+	}
+	_, restErr = r.Chat.RemoveChatMessage(ctx, -1)
+	wantStatus(t, restErr, http.StatusNotFound)
+
+	empty, restErr := r.Chat.GetChatMessageEdits(ctx, other.ID)
+	noErr(t, restErr)
+	if empty == nil || len(empty) != 0 {
+		t.Errorf("GetChatMessageEdits without edits = %#v, want an empty list", empty)
+	}
+}
+
+func testChatReadMarker(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	chat := newDirectChat(t, r)
+	quiet := newDirectChat(t, r)
+
+	unreadRooms, restErr := r.Chat.GetUnreadChatRooms(ctx, chat.client.ID)
+	noErr(t, restErr)
+	if len(unreadRooms) != 1 || unreadRooms[0].LastMessage != nil || unreadRooms[0].UnreadCount != 0 {
+		t.Fatalf("GetUnreadChatRooms without messages = %+v", unreadRooms)
+	}
+
+	chat.send(t, r, chat.client, "mine")
+	chat.send(t, r, chat.host, "first")
+	last := chat.send(t, r, chat.host, "second")
+
+	unreadRooms, restErr = r.Chat.GetUnreadChatRooms(ctx, chat.client.ID)
+	noErr(t, restErr)
+	if len(unreadRooms) != 1 {
+		t.Fatalf("GetUnreadChatRooms returned %d rooms, want 1", len(unreadRooms))
+	}
+	// 자기가 보낸 메시지는 세지 않음
+	if room := unreadRooms[0]; room.ChatRoom.ID != chat.room.ID || room.UnreadCount != 2 || room.LastMessage == nil || room.LastMessage.ID != last.ID {
+		t.Errorf("GetUnreadChatRooms = %+v", room)
+	}
+
+	marker := &entity.ChatReadMarker{ChatRoomID: chat.room.ID, UserID: chat.client.ID, LastReadMessageID: last.ID}
+	noErr(t, r.Chat.SaveReadMarker(ctx, marker))
+	if marker.UpdatedAt == "" {
+		t.Error("SaveReadMarker should set updated_at")
+	}
+	// 더 오래된 메시지를 읽음으로 표시해도 되돌아가지 않음
+	noErr(t, r.Chat.SaveReadMarker(ctx, &entity.ChatReadMarker{ChatRoomID: chat.room.ID, UserID: chat.client.ID, LastReadMessageID: 1}))
+
+	unreadRooms, restErr = r.Chat.GetUnreadChatRooms(ctx, chat.client.ID)
+	noErr(t, restErr)
+	if room := unreadRooms[0]; room.UnreadCount != 0 || room.LastReadMessageID != last.ID {
+		t.Errorf("GetUnreadChatRooms after SaveReadMarker = %+v", room)
+	}
+
+	// 상대방 정보와 게시글 제목, 메시지가 있는 채팅룸이 먼저
+	inbox, restErr := r.Chat.GetChatInbox(ctx, chat.host.ID)
+	noErr(t, restErr)
+	if len(inbox) != 1 {
+		t.Fatalf("GetChatInbox returned %d items, want 1", len(inbox))
+	}
+	item := inbox[0]
+	wantCounterpart := entity.PublicUser{ID: chat.client.ID, Email: chat.client.Email, Nickname: chat.client.Nickname}
+	if item.Counterpart == nil || *item.Counterpart != wantCounterpart || item.Participants != nil || item.StudyPostTitle != chat.post.Title ||
+		item.UnreadCount != 1 || item.LastMessage == nil || item.LastMessage.ID != last.ID {
+		t.Errorf("GetChatInbox = %+v", item)
+	}
+
+	room, restErr := r.Chat.SaveChatRoom(ctx, quiet.client.ID, chat.host.ID, quiet.post.ID)
+	noErr(t, restErr)
+	inbox, restErr = r.Chat.GetChatInbox(ctx, chat.host.ID)
+	noErr(t, restErr)
+	if len(inbox) != 2 || inbox[0].ChatRoom.ID != chat.room.ID || inbox[1].ChatRoom.ID != room.ID || inbox[1].LastMessage != nil {
+		t.Errorf("GetChatInbox should list rooms without messages last, got %+v", inbox)
+	}
+
+	// 팀 채팅은 상대방 대신 본인을 뺀 참여자들을 반환
+	group, restErr := r.Chat.SaveGroupChatRoom(ctx, chat.host.ID, chat.post.ID)
+	noErr(t, restErr)
+	noErr(t, r.Chat.AddChatRoomParticipant(ctx, group.ID, chat.client.ID))
+	inbox, restErr = r.Chat.GetChatInbox(ctx, chat.client.ID)
+	noErr(t, restErr)
+	if len(inbox) != 2 || inbox[1].ChatRoom.ID != group.ID {
+		t.Fatalf("GetChatInbox with a group chat = %+v", inbox)
+	}
+	wantParticipant := entity.PublicUser{ID: chat.host.ID, Email: chat.host.Email, Nickname: chat.host.Nickname}
+	if groupItem := inbox[1]; groupItem.Counterpart != nil || len(groupItem.Participants) != 1 || groupItem.Participants[0] != wantParticipant {
+		t.Errorf("GetChatInbox group chat item = %+v", groupItem)
+	}
+}
+
+func testChatAttachment(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	chat := newDirectChat(t, r)
+
+	attachment, restErr := r.ChatAttachment.SaveAttachment(ctx, &entity.ChatAttachment{
+		ChatRoomID:  chat.room.ID,
+		UploaderID:  chat.client.ID,
+		FileName:    "notes.txt",
+		ContentType: "text/plain",
+		Size:        5,
+		StorageKey:  "chat/" + unique(),
+	})
+	noErr(t, restErr)
+	if attachment.ID == 0 || attachment.CreatedAt == "" {
+		t.Fatalf("SaveAttachment = %+v", attachment)
+	}
+	_, restErr = r.ChatAttachment.SaveAttachment(ctx, attachment)
+	wantStatus(t, restErr, 0)
+
+	got, restErr := r.ChatAttachment.GetAttachment(ctx, attachment.ID)
+	noErr(t, restErr)
+	if *got != *attachment {
+		t.Errorf("GetAttachment = %+v, want %+v", got, attachment)
+	}
+	_, restErr = r.ChatAttachment.GetAttachment(ctx, -1)
+	wantStatus(t, restErr, http.StatusNotFound)
+
+	withAttachment := func(sender *entity.User) (*entity.ChatMessage, *errors.RestErr) {
+		return r.Chat.SaveChatMessage(ctx, &entity.ChatMessage{
+			ChatRoomID:   chat.room.ID,
+			ChatRoomName: chat.room.RoomName,
+			SenderID:     sender.ID,
+			Sender:       sender.Nickname,
+			MessageType:  "message",
+			Message:      attachment.FileName,
+			AttachmentID: sql.NullInt64{Int64: attachment.ID, Valid: true},
+		})
+	}
+
+	// 첨부파일은 올린 사람만, 한 번만 보낼 수 있음
+	_, restErr = withAttachment(chat.host)
+	wantStatus(t, restErr, http.StatusBadRequest)
+	msg, restErr := withAttachment(chat.client)
+	noErr(t, restErr)
+	if msg.AttachmentID.Int64 != attachment.ID {
+		t.Errorf("SaveChatMessage attachment = %v, want %d", msg.AttachmentID, attachment.ID)
+	}
+	_, restErr = withAttachment(chat.client)
+	wantStatus(t, restErr, http.StatusBadRequest)
+
+	unused, restErr := r.ChatAttachment.SaveAttachment(ctx, &entity.ChatAttachment{
+		ChatRoomID:  chat.room.ID,
+		UploaderID:  chat.client.ID,
+		FileName:    "unused.txt",
+		ContentType: "text/plain",
+		Size:        1,
+		StorageKey:  "chat/" + unique(),
+	})
+	noErr(t, restErr)
+	noErr(t, r.ChatAttachment.DeleteAttachment(ctx, unused.ID))
+	_, restErr = r.ChatAttachment.GetAttachment(ctx, unused.ID)
+	wantStatus(t, restErr, http.StatusNotFound)
+}
+
+func messageIDs(msgs []entity.ChatMessage) []int64 {
+	var ids []int64
+	for _, m := range msgs {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+func messageTexts(msgs []entity.ChatMessage) []string {
+	var texts []string
+	for _, m := range msgs {
+		texts = append(texts, m.Message)
+	}
+	return texts
+}
+
+func sortedIDs(ids []int64) []int64 {
+	sorted := append([]int64(nil), ids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
@@ -0,0 +1,176 @@
+package repositorytest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/entity"
+)
+
+func testUserBlock(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	chat := newDirectChat(t, r)
+	blocker, blocked := chat.client, chat.host
+
+	isBlocked := func(userID, otherUserID int64) bool {
+		t.Helper()
+		b, restErr := r.UserBlock.IsBlocked(ctx, userID, otherUserID)
+		noErr(t, restErr)
+		return b
+	}
+	isRoomBlocked := func(roomName string) bool {
+		t.Helper()
+		b, restErr := r.UserBlock.IsDirectChatRoomBlocked(ctx, roomName)
+		noErr(t, restErr)
+		return b
+	}
+
+	if isBlocked(blocker.ID, blocked.ID) || isRoomBlocked(chat.room.RoomName) {
+		t.Fatal("nobody is blocked yet")
+	}
+
+	noErr(t, r.UserBlock.BlockUser(ctx, blocker.ID, blocked.ID))
+	noErr(t, r.UserBlock.BlockUser(ctx, blocker.ID, blocked.ID))
+
+	// 차단은 양쪽 모두에게 적용됨
+	if !isBlocked(blocker.ID, blocked.ID) || !isBlocked(blocked.ID, blocker.ID) {
+		t.Error("IsBlocked should be true in both directions")
+	}
+	if !isRoomBlocked(chat.room.RoomName) {
+		t.Error("IsDirectChatRoomBlocked should be true after BlockUser")
+	}
+	if isRoomBlocked("missing-" + unique()) {
+		t.Error("IsDirectChatRoomBlocked of a missing room should be false")
+	}
+
+	blocks, restErr := r.UserBlock.GetBlockedUsers(ctx, blocker.ID)
+	noErr(t, restErr)
+	if len(blocks) != 1 || blocks[0].BlockedID != blocked.ID || blocks[0].Nickname != blocked.Nickname || blocks[0].CreatedAt == "" {
+		t.Errorf("GetBlockedUsers = %+v", blocks)
+	}
+	blocks, restErr = r.UserBlock.GetBlockedUsers(ctx, blocked.ID)
+	noErr(t, restErr)
+	if len(blocks) != 0 {
+		t.Errorf("GetBlockedUsers of the blocked user = %+v", blocks)
+	}
+
+	// 팀 채팅룸은 차단과 상관없음
+	group, restErr := r.Chat.SaveGroupChatRoom(ctx, blocked.ID, chat.post.ID)
+	noErr(t, restErr)
+	if isRoomBlocked(group.RoomName) {
+		t.Error("IsDirectChatRoomBlocked of a group room should be false")
+	}
+
+	noErr(t, r.UserBlock.UnblockUser(ctx, blocker.ID, blocked.ID))
+	noErr(t, r.UserBlock.UnblockUser(ctx, blocker.ID, blocked.ID))
+	if isBlocked(blocker.ID, blocked.ID) || isRoomBlocked(chat.room.RoomName) {
+		t.Error("nobody should be blocked after UnblockUser")
+	}
+}
+
+func testChatReport(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	chat := newDirectChat(t, r)
+	msg := chat.send(t, r, chat.host, "spam")
+	moderator := newUser(t, r)
+
+	report, restErr := r.ChatReport.SaveReport(ctx, &entity.ChatMessageReport{ChatMessageID: msg.ID, ReporterID: chat.client.ID, Reason: "spam"})
+	noErr(t, restErr)
+	if report.ID == 0 || report.Status != entity.ReportStatusOpen || report.ChatRoomID != chat.room.ID ||
+		report.SenderID != chat.host.ID || report.Sender != chat.host.Nickname || report.Message != "spam" {
+		t.Fatalf("SaveReport = %+v", report)
+	}
+
+	// 같은 유저가 다시 신고하면 기존 신고
+	again, restErr := r.ChatReport.SaveReport(ctx, &entity.ChatMessageReport{ChatMessageID: msg.ID, ReporterID: chat.client.ID, Reason: "again"})
+	noErr(t, restErr)
+	if again.ID != report.ID {
+		t.Errorf("SaveReport of the same message = %d, want %d", again.ID, report.ID)
+	}
+	_, restErr = r.ChatReport.SaveReport(ctx, &entity.ChatMessageReport{ChatMessageID: -1, ReporterID: chat.client.ID, Reason: "missing"})
+	wantStatus(t, restErr, 0)
+
+	got, restErr := r.ChatReport.GetReport(ctx, report.ID)
+	noErr(t, restErr)
+	if got.Reason != "spam" || got.ReporterID != chat.client.ID || got.Message != "spam" {
+		t.Errorf("GetReport = %+v", got)
+	}
+	_, restErr = r.ChatReport.GetReport(ctx, -1)
+	wantStatus(t, restErr, http.StatusNotFound)
+
+	if !hasReport(t, r, entity.ReportStatusOpen, report.ID) {
+		t.Errorf("GetReports(open) should contain report %d", report.ID)
+	}
+
+	noErr(t, r.ChatReport.ResolveReports(ctx, msg.ID, moderator.ID, entity.ReportStatusRemoved))
+	got, restErr = r.ChatReport.GetReport(ctx, report.ID)
+	noErr(t, restErr)
+	if got.Status != entity.ReportStatusRemoved || got.ReviewedBy != moderator.ID || got.ReviewedAt == "" {
+		t.Errorf("GetReport after ResolveReports = %+v", got)
+	}
+	if hasReport(t, r, entity.ReportStatusOpen, report.ID) || !hasReport(t, r, entity.ReportStatusRemoved, report.ID) {
+		t.Errorf("GetReports should list report %d as removed", report.ID)
+	}
+}
+
+func hasReport(t *testing.T, r Repositories, status string, reportID int64) bool {
+	t.Helper()
+
+	reports, restErr := r.ChatReport.GetReports(context.Background(), status, 1000)
+	noErr(t, restErr)
+	for _, report := range reports {
+		if report.ID == reportID {
+			return true
+		}
+	}
+	return false
+}
+
+func testMessageRateLimiter(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	limiter := r.NewMessageRateLimiter(2, time.Hour)
+	userID, otherUserID := uniqueID(), uniqueID()+1
+
+	for i, want := range []bool{true, true, false} {
+		allowed, restErr := limiter.Allow(ctx, userID)
+		noErr(t, restErr)
+		if allowed != want {
+			t.Errorf("Allow #%d = %v, want %v", i+1, allowed, want)
+		}
+	}
+
+	// 유저마다 따로 셈
+	allowed, restErr := limiter.Allow(ctx, otherUserID)
+	noErr(t, restErr)
+	if !allowed {
+		t.Error("Allow of another user should be true")
+	}
+}
+
+func testNotificationLimiter(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	limiter := r.NewNotificationLimiter(time.Hour)
+	userID, roomID := uniqueID(), uniqueID()
+
+	for _, tc := range []struct {
+		userID, roomID int64
+		want           bool
+	}{
+		{userID, roomID, true},
+		{userID, roomID, false},
+		{userID, roomID + 1, true},
+		{userID + 1, roomID, true},
+	} {
+		allowed, restErr := limiter.Allow(ctx, tc.userID, tc.roomID)
+		noErr(t, restErr)
+		if allowed != tc.want {
+			t.Errorf("Allow(%d, %d) = %v, want %v", tc.userID, tc.roomID, allowed, tc.want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package repositorytest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/code-wave/go-wave/domain/entity"
+)
+
+func testNotification(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	chat := newDirectChat(t, r)
+	msg := chat.send(t, r, chat.client, "hello")
+
+	newNotification := func(message string) *entity.Notification {
+		notification, restErr := r.Notification.SaveNotification(ctx, &entity.Notification{
+			UserID:        chat.host.ID,
+			Type:          entity.NotificationTypeChatMessage,
+			ChatRoomID:    chat.room.ID,
+			ChatMessageID: msg.ID,
+			SenderName:    chat.client.Nickname,
+			Message:       message,
+		})
+		noErr(t, restErr)
+		return notification
+	}
+
+	// 미리보기는 200자까지만 저장
+	first := newNotification(strings.Repeat("가", 250))
+	if first.ID == 0 || first.Read || first.CreatedAt == "" || first.Message != strings.Repeat("가", 200) {
+		t.Errorf("SaveNotification = %+v", first)
+	}
+	second := newNotification("second")
+	third := newNotification("third")
+
+	notifications, restErr := r.Notification.GetNotifications(ctx, chat.host.ID, 2)
+	noErr(t, restErr)
+	if len(notifications) != 2 || notifications[0].ID != third.ID || notifications[1].ID != second.ID {
+		t.Errorf("GetNotifications(2) = %+v, want third and second", notifications)
+	}
+	empty, restErr := r.Notification.GetNotifications(ctx, chat.client.ID, 10)
+	noErr(t, restErr)
+	if len(empty) != 0 {
+		t.Errorf("GetNotifications of another user = %+v", empty)
+	}
+
+	noErr(t, r.Notification.MarkNotificationsRead(ctx, chat.host.ID, second.ID))
+	notifications, restErr = r.Notification.GetNotifications(ctx, chat.host.ID, 10)
+	noErr(t, restErr)
+	for _, n := range notifications {
+		if n.Read != (n.ID <= second.ID) {
+			t.Errorf("notification %d read = %v after MarkNotificationsRead(%d)", n.ID, n.Read, second.ID)
+		}
+	}
+
+	// 설정을 저장하지 않았으면 기본 설정
+	preference, restErr := r.Notification.GetPreference(ctx, chat.host.ID)
+	noErr(t, restErr)
+	if *preference != *entity.DefaultNotificationPreference(chat.host.ID) {
+		t.Errorf("GetPreference without preference = %+v", preference)
+	}
+	if digest := findDigest(t, r, chat.host.ID); digest != nil {
+		t.Errorf("GetPendingDigests should skip users without email_digest, got %+v", digest)
+	}
+
+	noErr(t, r.Notification.SavePreference(ctx, &entity.NotificationPreference{UserID: chat.host.ID, InApp: true, EmailDigest: true}))
+	preference, restErr = r.Notification.GetPreference(ctx, chat.host.ID)
+	noErr(t, restErr)
+	if !preference.EmailDigest || preference.WebPush {
+		t.Errorf("GetPreference after SavePreference = %+v", preference)
+	}
+
+	// 읽은 알림은 이메일로 보내지 않음
+	digest := findDigest(t, r, chat.host.ID)
+	if digest == nil || digest.Email != chat.host.Email || digest.Nickname != chat.host.Nickname ||
+		len(digest.Notifications) != 1 || digest.Notifications[0].ID != third.ID {
+		t.Fatalf("GetPendingDigests = %+v, want only the third notification", digest)
+	}
+
+	noErr(t, r.Notification.MarkNotificationsEmailed(ctx, chat.host.ID, third.ID))
+	if digest := findDigest(t, r, chat.host.ID); digest != nil {
+		t.Errorf("GetPendingDigests after MarkNotificationsEmailed = %+v", digest)
+	}
+}
+
+func findDigest(t *testing.T, r Repositories, userID int64) *entity.NotificationDigest {
+	t.Helper()
+
+	digests, restErr := r.Notification.GetPendingDigests(context.Background())
+	noErr(t, restErr)
+	for i := range digests {
+		if digests[i].UserID == userID {
+			return &digests[i]
+		}
+	}
+	return nil
+}
+
+func testPushSubscription(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	user, other := newUser(t, r), newUser(t, r)
+	endpoint := "https://push.example.com/" + unique()
+
+	subscription, restErr := r.Notification.SavePushSubscription(ctx, &entity.PushSubscription{UserID: user.ID, Endpoint: endpoint, P256dh: "p256dh", Auth: "auth"})
+	noErr(t, restErr)
+	if subscription.ID == 0 || subscription.CreatedAt == "" {
+		t.Fatalf("SavePushSubscription = %+v", subscription)
+	}
+
+	// 같은 endpoint는 다른 유저가 구독해도 하나만 남음
+	moved, restErr := r.Notification.SavePushSubscription(ctx, &entity.PushSubscription{UserID: other.ID, Endpoint: endpoint, P256dh: "new p256dh", Auth: "new auth"})
+	noErr(t, restErr)
+	if moved.ID != subscription.ID || moved.UserID != other.ID || moved.P256dh != "new p256dh" {
+		t.Errorf("SavePushSubscription with the same endpoint = %+v, want id %d", moved, subscription.ID)
+	}
+
+	subscriptions, restErr := r.Notification.GetPushSubscriptions(ctx, user.ID)
+	noErr(t, restErr)
+	if len(subscriptions) != 0 {
+		t.Errorf("GetPushSubscriptions of the previous user = %+v", subscriptions)
+	}
+	subscriptions, restErr = r.Notification.GetPushSubscriptions(ctx, other.ID)
+	noErr(t, restErr)
+	if len(subscriptions) != 1 || subscriptions[0].Endpoint != endpoint || subscriptions[0].Auth != "new auth" {
+		t.Errorf("GetPushSubscriptions = %+v", subscriptions)
+	}
+
+	// 다른 유저의 구독은 지우지 않음
+	noErr(t, r.Notification.DeletePushSubscription(ctx, user.ID, endpoint))
+	subscriptions, restErr = r.Notification.GetPushSubscriptions(ctx, other.ID)
+	noErr(t, restErr)
+	if len(subscriptions) != 1 {
+		t.Errorf("DeletePushSubscription removed another user's subscription")
+	}
+	noErr(t, r.Notification.DeletePushSubscription(ctx, other.ID, endpoint))
+	subscriptions, restErr = r.Notification.GetPushSubscriptions(ctx, other.ID)
+	noErr(t, restErr)
+	if len(subscriptions) != 0 {
+		t.Errorf("GetPushSubscriptions after DeletePushSubscription = %+v", subscriptions)
+	}
+}
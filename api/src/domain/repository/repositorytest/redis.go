@@ -0,0 +1,99 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/entity"
+)
+
+func testAuth(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	rt := &entity.RefreshToken{
+		Uuid:         "repositorytest-" + unique(),
+		RefreshToken: "refresh-token",
+		UserID:       uniqueID(),
+		ExpiresAt:    time.Now().Add(time.Hour).Unix(),
+	}
+	noErr(t, r.Auth.Create(ctx, rt))
+
+	userID, restErr := r.Auth.Fetch(ctx, rt.Uuid)
+	noErr(t, restErr)
+	if userID != rt.UserID {
+		t.Errorf("Fetch = %d, want %d", userID, rt.UserID)
+	}
+
+	noErr(t, r.Auth.Delete(ctx, rt.Uuid))
+	_, restErr = r.Auth.Fetch(ctx, rt.Uuid)
+	wantStatus(t, restErr, 0)
+	wantStatus(t, r.Auth.Delete(ctx, rt.Uuid), 0)
+}
+
+func testPresence(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	userID := uniqueID()
+	presence := func() *entity.Presence {
+		t.Helper()
+		p, restErr := r.Presence.GetPresence(ctx, userID)
+		noErr(t, restErr)
+		return p
+	}
+
+	if p := presence(); p.Online || p.LastSeen != "" {
+		t.Fatalf("GetPresence before Connect = %+v", p)
+	}
+
+	// 처음 연결할 때만 상태가 바뀜
+	changed, restErr := r.Presence.Connect(ctx, userID, "first", []int64{1}, time.Minute)
+	noErr(t, restErr)
+	if !changed {
+		t.Error("first Connect should change the presence")
+	}
+	changed, restErr = r.Presence.Connect(ctx, userID, "second", []int64{2}, time.Minute)
+	noErr(t, restErr)
+	if changed {
+		t.Error("second Connect should not change the presence")
+	}
+	noErr(t, r.Presence.Refresh(ctx, userID, "first", []int64{1}, time.Minute))
+
+	if p := presence(); !p.Online || p.UserID != userID {
+		t.Errorf("GetPresence after Connect = %+v", p)
+	}
+	connIDs, restErr := r.Presence.GetConnections(ctx, userID)
+	noErr(t, restErr)
+	if len(connIDs) != 2 || !contains(connIDs, "first") || !contains(connIDs, "second") {
+		t.Errorf("GetConnections = %v, want first and second", connIDs)
+	}
+
+	connectedToRoom := func(roomID int64) bool {
+		t.Helper()
+		connected, restErr := r.Presence.IsConnectedToRoom(ctx, userID, roomID)
+		noErr(t, restErr)
+		return connected
+	}
+	if !connectedToRoom(1) || !connectedToRoom(2) || connectedToRoom(3) {
+		t.Error("IsConnectedToRoom should be true only for the rooms of the connections")
+	}
+
+	// 마지막 연결이 끊길 때만 offline
+	changed, restErr = r.Presence.Disconnect(ctx, userID, "first", []int64{1})
+	noErr(t, restErr)
+	if changed {
+		t.Error("Disconnect with another connection should not change the presence")
+	}
+	if connectedToRoom(1) || !connectedToRoom(2) {
+		t.Error("IsConnectedToRoom after Disconnect should be false only for the room of the connection")
+	}
+	changed, restErr = r.Presence.Disconnect(ctx, userID, "second", []int64{2})
+	noErr(t, restErr)
+	if !changed {
+		t.Error("Disconnect of the last connection should change the presence")
+	}
+
+	if p := presence(); p.Online || p.LastSeen == "" {
+		t.Errorf("GetPresence after Disconnect = %+v", p)
+	}
+}
@@ -0,0 +1,183 @@
+// Package repositorytest repository 구현들(postgres/redis, memory)이 같은 동작을 하는지 확인하는 공통 테스트
+// 다른 테스트와 같은 DB를 써도 되도록 테스트마다 새 유저, 게시글을 만들고 자기가 만든 데이터만 확인함
+package repositorytest
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/helpers"
+	"github.com/google/uuid"
+)
+
+// Repositories 확인할 구현들, nil인 repository의 테스트는 건너뜀
+type Repositories struct {
+	Tx                 repository.TxManager
+	User               repository.UserRepository
+	StudyPost          repository.StudyPostRepository
+	TechStack          repository.TechStackRepository
+	StudyPostTechStack repository.StudyPostTechStackRepository
+	StudyPostMember    repository.StudyPostMemberRepository
+	Chat               repository.ChatRepository
+	ChatAttachment     repository.ChatAttachmentRepository
+	Notification       repository.NotificationRepository
+	UserBlock          repository.UserBlockRepository
+	ChatReport         repository.ChatReportRepository
+	Auth               repository.AuthRepository
+	Presence           repository.PresenceRepository
+	BlobStore          repository.BlobStore
+
+	NewMessageRateLimiter  func(limit int64, window time.Duration) repository.MessageRateLimiter
+	NewNotificationLimiter func(window time.Duration) repository.NotificationRateLimiter
+}
+
+func Run(t *testing.T, r Repositories) {
+	hasChat := r.User != nil && r.StudyPost != nil && r.Chat != nil
+
+	tests := []struct {
+		name string
+		ok   bool
+		fn   func(t *testing.T, r Repositories)
+	}{
+		{"User", r.User != nil, testUser},
+		{"UserLogin", r.User != nil, testUserLogin},
+		{"StudyPost", r.User != nil && r.StudyPost != nil, testStudyPost},
+		{"TechStack", r.TechStack != nil, testTechStack},
+		{"StudyPostTechStack", r.User != nil && r.StudyPost != nil && r.TechStack != nil && r.StudyPostTechStack != nil, testStudyPostTechStack},
+		{"StudyPostMember", r.User != nil && r.StudyPost != nil && r.StudyPostMember != nil, testStudyPostMember},
+		{"ChatRoom", hasChat, testChatRoom},
+		{"ChatMessage", hasChat, testChatMessage},
+		{"ChatMessageEdit", hasChat, testChatMessageEdit},
+		{"ChatReadMarker", hasChat, testChatReadMarker},
+		{"ChatAttachment", hasChat && r.ChatAttachment != nil, testChatAttachment},
+		{"Notification", hasChat && r.Notification != nil, testNotification},
+		{"PushSubscription", r.User != nil && r.Notification != nil, testPushSubscription},
+		{"UserBlock", hasChat && r.UserBlock != nil, testUserBlock},
+		{"ChatReport", hasChat && r.ChatReport != nil, testChatReport},
+		{"Tx", r.Tx != nil && r.User != nil, testTx},
+		{"Auth", r.Auth != nil, testAuth},
+		{"Presence", r.Presence != nil, testPresence},
+		{"MessageRateLimiter", r.NewMessageRateLimiter != nil, testMessageRateLimiter},
+		{"NotificationLimiter", r.NewNotificationLimiter != nil, testNotificationLimiter},
+		{"BlobStore", r.BlobStore != nil, testBlobStore},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if !tc.ok {
+				t.Skip("not provided")
+			}
+			tc.fn(t, r)
+		})
+	}
+}
+
+// unique 다른 테스트 실행과 겹치지 않는 12자리 문자열
+func unique() string {
+	return strings.ReplaceAll(uuid.New().String(), "-", "")[:12]
+}
+
+// uniqueID redis key처럼 DB에 저장하지 않는 유저 ID
+func uniqueID() int64 {
+	return time.Now().UnixNano()
+}
+
+func noErr(t *testing.T, restErr *errors.RestErr) {
+	t.Helper()
+	if restErr != nil {
+		t.Fatalf("unexpected error: %d %s", restErr.Status, restErr.Message)
+	}
+}
+
+// wantStatus status가 0이면 error인지만 확인
+func wantStatus(t *testing.T, restErr *errors.RestErr, status int) {
+	t.Helper()
+	if restErr == nil {
+		t.Fatalf("expected error with status %d, got nil", status)
+	}
+	if status != 0 && restErr.Status != status {
+		t.Fatalf("expected status %d, got %d %s", status, restErr.Status, restErr.Message)
+	}
+}
+
+func newUser(t *testing.T, r Repositories) *entity.User {
+	t.Helper()
+
+	id := unique()
+	user := &entity.User{
+		Email:     id + "@repositorytest.dev",
+		Password:  "password",
+		Name:      "name " + id[:4],
+		Nickname:  "nick" + id,
+		CreatedAt: helpers.GetCurrentTimeForDB(),
+	}
+	noErr(t, r.User.Save(context.Background(), user))
+
+	return user
+}
+
+func newStudyPost(t *testing.T, r Repositories, userID int64) *entity.StudyPost {
+	t.Helper()
+
+	studyPost, restErr := r.StudyPost.SavePost(context.Background(), &entity.StudyPost{
+		UserID:       userID,
+		Title:        "title " + unique(),
+		Topic:        "topic",
+		Content:      "repository contract test content",
+		NumOfMembers: 3,
+		StartDate:    "2021/6/19",
+		EndDate:      "2021/6/20",
+		IsOnline:     true,
+		TechStack:    []string{"go", "react"},
+	})
+	noErr(t, restErr)
+
+	return studyPost
+}
+
+// directChat host가 쓴 게시글에 client가 만든 1:1 채팅룸
+type directChat struct {
+	room   *entity.ChatRoom
+	client *entity.User
+	host   *entity.User
+	post   *entity.StudyPost
+}
+
+func newDirectChat(t *testing.T, r Repositories) directChat {
+	t.Helper()
+
+	client, host := newUser(t, r), newUser(t, r)
+	post := newStudyPost(t, r, host.ID)
+
+	room, restErr := r.Chat.SaveChatRoom(context.Background(), client.ID, host.ID, post.ID)
+	noErr(t, restErr)
+
+	return directChat{room: room, client: client, host: host, post: post}
+}
+
+func (c directChat) send(t *testing.T, r Repositories, sender *entity.User, message string) *entity.ChatMessage {
+	t.Helper()
+
+	msg, restErr := r.Chat.SaveChatMessage(context.Background(), &entity.ChatMessage{
+		ChatRoomID:   c.room.ID,
+		ChatRoomName: c.room.RoomName,
+		SenderID:     sender.ID,
+		Sender:       sender.Nickname,
+		MessageType:  "message",
+		Message:      message,
+	})
+	noErr(t, restErr)
+
+	return msg
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: true}
+}
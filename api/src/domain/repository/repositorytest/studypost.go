@@ -0,0 +1,181 @@
+package repositorytest
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/code-wave/go-wave/domain/entity"
+)
+
+func testStudyPost(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	user := newUser(t, r)
+	first := newStudyPost(t, r, user.ID)
+	second := newStudyPost(t, r, user.ID)
+	if first.ID == 0 || second.ID == first.ID {
+		t.Fatalf("SavePost should set a new id, got %d and %d", first.ID, second.ID)
+	}
+
+	got, restErr := r.StudyPost.GetPost(ctx, first.ID)
+	noErr(t, restErr)
+	if got.Title != first.Title || got.UserID != user.ID || !reflect.DeepEqual(got.TechStack, []string{"go", "react"}) {
+		t.Errorf("GetPost = %+v, want %+v", got, first)
+	}
+	_, restErr = r.StudyPost.GetPost(ctx, -1)
+	wantStatus(t, restErr, http.StatusBadRequest)
+
+	posts, restErr := r.StudyPost.GetPostsByUserID(ctx, user.ID, 10, 0)
+	noErr(t, restErr)
+	if ids := postIDs(posts); !reflect.DeepEqual(ids, []int64{first.ID, second.ID}) {
+		t.Errorf("GetPostsByUserID ids = %v, want %d and %d", ids, first.ID, second.ID)
+	}
+	posts, restErr = r.StudyPost.GetPostsByUserID(ctx, user.ID, 1, 1)
+	noErr(t, restErr)
+	if len(posts) != 1 {
+		t.Errorf("GetPostsByUserID with limit 1 offset 1 returned %d posts", len(posts))
+	}
+
+	latest, restErr := r.StudyPost.GetPostsInLatestOrder(ctx, 1, 0)
+	noErr(t, restErr)
+	if len(latest) != 1 {
+		t.Errorf("GetPostsInLatestOrder with limit 1 returned %d posts", len(latest))
+	}
+
+	first.Title = "updated " + unique()
+	first.TechStack = []string{"go"}
+	updated, restErr := r.StudyPost.UpdatePost(ctx, first)
+	noErr(t, restErr)
+	if updated.Title != first.Title || updated.UserID != user.ID {
+		t.Errorf("UpdatePost = %+v", updated)
+	}
+	got, restErr = r.StudyPost.GetPost(ctx, first.ID)
+	noErr(t, restErr)
+	if got.Title != first.Title || !reflect.DeepEqual(got.TechStack, []string{"go"}) {
+		t.Errorf("GetPost after UpdatePost = %+v", got)
+	}
+	_, restErr = r.StudyPost.UpdatePost(ctx, &entity.StudyPost{ID: -1, Title: "missing"})
+	wantStatus(t, restErr, 0)
+
+	noErr(t, r.StudyPost.DeletePost(ctx, second.ID))
+	_, restErr = r.StudyPost.GetPost(ctx, second.ID)
+	wantStatus(t, restErr, http.StatusBadRequest)
+	wantStatus(t, r.StudyPost.DeletePost(ctx, second.ID), http.StatusBadRequest)
+}
+
+func postIDs(posts entity.StudyPosts) []int64 {
+	var ids []int64
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+	}
+	// 같은 초에 저장된 게시글은 순서가 정해져 있지 않음
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func techNames(techStacks entity.TechStacks) []string {
+	var names []string
+	for _, t := range techStacks {
+		names = append(names, t.TechName)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func testTechStack(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	// 0001 migration에서 넣는 기술 스택
+	noErr(t, r.TechStack.CheckTechStack(ctx, []string{"go", "react"}))
+	wantStatus(t, r.TechStack.CheckTechStack(ctx, []string{"go", "unknown-" + unique()}), http.StatusBadRequest)
+
+	techName := "tech-" + unique()
+	noErr(t, r.TechStack.SaveTechStack(ctx, techName))
+	wantStatus(t, r.TechStack.SaveTechStack(ctx, techName), 0)
+	noErr(t, r.TechStack.CheckTechStack(ctx, []string{techName}))
+
+	all, restErr := r.TechStack.GetAllTechStack(ctx)
+	noErr(t, restErr)
+	if names := techNames(all); !contains(names, techName) || !contains(names, "go") {
+		t.Errorf("GetAllTechStack = %v, want go and %s", names, techName)
+	}
+
+	_, restErr = r.TechStack.GetTechStack(ctx, -1)
+	wantStatus(t, restErr, http.StatusBadRequest)
+
+	noErr(t, r.TechStack.DeleteTechStack(ctx, techName))
+	wantStatus(t, r.TechStack.DeleteTechStack(ctx, techName), http.StatusBadRequest)
+	wantStatus(t, r.TechStack.CheckTechStack(ctx, []string{techName}), http.StatusBadRequest)
+}
+
+func testStudyPostTechStack(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	post := newStudyPost(t, r, newUser(t, r).ID)
+
+	noErr(t, r.StudyPostTechStack.SaveStudyPostTechStack(ctx, post.ID, []string{"go", "react"}))
+	techStacks, restErr := r.TechStack.GetAllTechStackByStudyPostID(ctx, post.ID)
+	noErr(t, restErr)
+	if names := techNames(techStacks); !reflect.DeepEqual(names, []string{"go", "react"}) {
+		t.Errorf("GetAllTechStackByStudyPostID = %v, want [go react]", names)
+	}
+
+	noErr(t, r.StudyPostTechStack.UpdateStudyPostTechStack(ctx, post.ID, []string{"react"}))
+	techStacks, restErr = r.TechStack.GetAllTechStackByStudyPostID(ctx, post.ID)
+	noErr(t, restErr)
+	if names := techNames(techStacks); !reflect.DeepEqual(names, []string{"react"}) {
+		t.Errorf("GetAllTechStackByStudyPostID after update = %v, want [react]", names)
+	}
+}
+
+func testStudyPostMember(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	post := newStudyPost(t, r, newUser(t, r).ID)
+	first, second := newUser(t, r), newUser(t, r)
+
+	member := &entity.StudyPostMember{StudyPostID: post.ID, UserID: first.ID}
+	noErr(t, r.StudyPostMember.SaveMember(ctx, member))
+	if member.JoinedAt == "" {
+		t.Error("SaveMember should set joined_at")
+	}
+	noErr(t, r.StudyPostMember.SaveMember(ctx, &entity.StudyPostMember{StudyPostID: post.ID, UserID: second.ID}))
+	wantStatus(t, r.StudyPostMember.SaveMember(ctx, &entity.StudyPostMember{StudyPostID: post.ID, UserID: first.ID}), http.StatusBadRequest)
+
+	members, restErr := r.StudyPostMember.GetMembers(ctx, post.ID)
+	noErr(t, restErr)
+	nicknames := make(map[int64]string)
+	for _, m := range members {
+		nicknames[m.UserID] = m.Nickname
+	}
+	if len(members) != 2 || nicknames[first.ID] != first.Nickname || nicknames[second.ID] != second.Nickname {
+		t.Errorf("GetMembers = %+v", members)
+	}
+
+	noErr(t, r.StudyPostMember.DeleteMember(ctx, post.ID, first.ID))
+	wantStatus(t, r.StudyPostMember.DeleteMember(ctx, post.ID, first.ID), http.StatusBadRequest)
+
+	members, restErr = r.StudyPostMember.GetMembers(ctx, post.ID)
+	noErr(t, restErr)
+	if len(members) != 1 || members[0].UserID != second.ID {
+		t.Errorf("GetMembers after DeleteMember = %+v", members)
+	}
+
+	empty, restErr := r.StudyPostMember.GetMembers(ctx, -1)
+	noErr(t, restErr)
+	if empty == nil || len(empty) != 0 {
+		t.Errorf("GetMembers without members = %#v, want an empty list", empty)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
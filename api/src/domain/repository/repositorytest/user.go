@@ -0,0 +1,93 @@
+package repositorytest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/infrastructure/encryption"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/helpers"
+)
+
+func testUser(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	user := newUser(t, r)
+	if user.ID == 0 {
+		t.Fatal("Save should set the user id")
+	}
+
+	duplicated := *user
+	wantStatus(t, r.User.Save(ctx, &duplicated), http.StatusBadRequest)
+
+	saved, restErr := r.User.GetUserByID(ctx, user.ID)
+	noErr(t, restErr)
+	if saved.Email != user.Email || saved.Nickname != user.Nickname || saved.Password != "" {
+		t.Errorf("GetUserByID = %+v, want %s %s without password", saved, user.Email, user.Nickname)
+	}
+
+	noErr(t, r.User.FindByEmail(ctx, user.Email))
+	wantStatus(t, r.User.FindByEmail(ctx, "nobody-"+unique()+"@repositorytest.dev"), http.StatusNotFound)
+	noErr(t, r.User.FindByNickname(ctx, user.Nickname))
+	wantStatus(t, r.User.FindByNickname(ctx, "nobody"+unique()), http.StatusNotFound)
+
+	user.Name = "renamed"
+	noErr(t, r.User.Update(ctx, user))
+	got := &entity.User{ID: user.ID}
+	noErr(t, r.User.Get(ctx, got))
+	if got.Name != "renamed" || got.Email != user.Email {
+		t.Errorf("Get after Update = %+v", got)
+	}
+
+	noErr(t, r.User.Delete(ctx, user.ID))
+	_, restErr = r.User.GetUserByID(ctx, user.ID)
+	wantStatus(t, restErr, 0)
+}
+
+func testUserLogin(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	hash, err := encryption.Hash("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &entity.User{Email: unique() + "@repositorytest.dev", Password: hash, Name: "name", Nickname: "nick" + unique(), CreatedAt: helpers.GetCurrentTimeForDB()}
+	noErr(t, r.User.Save(ctx, user))
+
+	found, restErr := r.User.FindByEmailAndPassword(ctx, &entity.User{Email: user.Email, Password: "password"})
+	noErr(t, restErr)
+	if found.ID != user.ID {
+		t.Errorf("FindByEmailAndPassword id = %d, want %d", found.ID, user.ID)
+	}
+
+	_, restErr = r.User.FindByEmailAndPassword(ctx, &entity.User{Email: user.Email, Password: "wrong password"})
+	wantStatus(t, restErr, http.StatusNotFound)
+	_, restErr = r.User.FindByEmailAndPassword(ctx, &entity.User{Email: "nobody-" + unique() + "@repositorytest.dev", Password: "password"})
+	wantStatus(t, restErr, http.StatusNotFound)
+}
+
+func testTx(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	rolledBack := &entity.User{Email: unique() + "@repositorytest.dev", Password: "password", Name: "name", Nickname: "nick" + unique(), CreatedAt: helpers.GetCurrentTimeForDB()}
+	restErr := r.Tx.WithinTx(ctx, func(ctx context.Context) *errors.RestErr {
+		noErr(t, r.User.Save(ctx, rolledBack))
+		return errors.NewBadRequestError("second write failed")
+	})
+	if restErr == nil || restErr.Message != "second write failed" {
+		t.Fatalf("WithinTx should return the fn error, got %v", restErr)
+	}
+	wantStatus(t, r.User.FindByEmail(ctx, rolledBack.Email), http.StatusNotFound)
+
+	committed := &entity.User{Email: unique() + "@repositorytest.dev", Password: "password", Name: "name", Nickname: "nick" + unique(), CreatedAt: helpers.GetCurrentTimeForDB()}
+	restErr = r.Tx.WithinTx(ctx, func(ctx context.Context) *errors.RestErr {
+		// 안에서 다시 WithinTx를 호출해도 같은 transaction을 씀
+		return r.Tx.WithinTx(ctx, func(ctx context.Context) *errors.RestErr {
+			return r.User.Save(ctx, committed)
+		})
+	})
+	noErr(t, restErr)
+	noErr(t, r.User.FindByEmail(ctx, committed.Email))
+}
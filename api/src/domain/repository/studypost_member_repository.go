@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+)
+
+type StudyPostMemberRepository interface {
+	SaveMember(ctx context.Context, member *entity.StudyPostMember) *errors.RestErr
+	DeleteMember(ctx context.Context, studyPostID, userID int64) *errors.RestErr
+	GetMembers(ctx context.Context, studyPostID int64) (entity.StudyPostMembers, *errors.RestErr)
+}
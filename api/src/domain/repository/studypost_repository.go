@@ -1,15 +1,16 @@
 package repository
 
 import (
+	"context"
 	"github.com/code-wave/go-wave/domain/entity"
 	"github.com/code-wave/go-wave/infrastructure/errors"
 )
 
 type StudyPostRepository interface {
-	SavePost(studyPost *entity.StudyPost) (*entity.StudyPost, *errors.RestErr)
-	GetPost(id int64) (*entity.StudyPost, *errors.RestErr)
-	GetPostsInLatestOrder(limit, offset int64) (entity.StudyPosts, *errors.RestErr)
-	GetPostsByUserID(userID, limit, offset int64) (entity.StudyPosts, *errors.RestErr)
-	UpdatePost(studyPost *entity.StudyPost) (*entity.StudyPost, *errors.RestErr)
-	DeletePost(studyPostID int64) *errors.RestErr
+	SavePost(ctx context.Context, studyPost *entity.StudyPost) (*entity.StudyPost, *errors.RestErr)
+	GetPost(ctx context.Context, id int64) (*entity.StudyPost, *errors.RestErr)
+	GetPostsInLatestOrder(ctx context.Context, limit, offset int64) (entity.StudyPosts, *errors.RestErr)
+	GetPostsByUserID(ctx context.Context, userID, limit, offset int64) (entity.StudyPosts, *errors.RestErr)
+	UpdatePost(ctx context.Context, studyPost *entity.StudyPost) (*entity.StudyPost, *errors.RestErr)
+	DeletePost(ctx context.Context, studyPostID int64) *errors.RestErr
 }
@@ -1,11 +1,12 @@
 package repository
 
 import (
+	"context"
 	"github.com/code-wave/go-wave/infrastructure/errors"
 )
 
 type StudyPostTechStackRepository interface {
-	SaveStudyPostTechStack(studyPostID int64, techStack []string) *errors.RestErr
+	SaveStudyPostTechStack(ctx context.Context, studyPostID int64, techStack []string) *errors.RestErr
 	//GetStudyPostTechStack(studyPostTechStack *entity.StudyPostTechStack) (*entity.StudyPostTechStack, *errors.RestErr)
-	UpdateStudyPostTechStack(studyPostID int64, techStack []string) *errors.RestErr
+	UpdateStudyPostTechStack(ctx context.Context, studyPostID int64, techStack []string) *errors.RestErr
 }
@@ -1,15 +1,16 @@
 package repository
 
 import (
+	"context"
 	"github.com/code-wave/go-wave/domain/entity"
 	"github.com/code-wave/go-wave/infrastructure/errors"
 )
 
 type TechStackRepository interface {
-	SaveTechStack(techName string) *errors.RestErr
-	GetTechStack(id int64) (*entity.TechStack, *errors.RestErr)
-	GetAllTechStack() (entity.TechStacks, *errors.RestErr)
-	GetAllTechStackByStudyPostID(studyPostID int64) (entity.TechStacks, *errors.RestErr)
-	DeleteTechStack(techName string) *errors.RestErr
-	CheckTechStack(techStack []string) *errors.RestErr
+	SaveTechStack(ctx context.Context, techName string) *errors.RestErr
+	GetTechStack(ctx context.Context, id int64) (*entity.TechStack, *errors.RestErr)
+	GetAllTechStack(ctx context.Context) (entity.TechStacks, *errors.RestErr)
+	GetAllTechStackByStudyPostID(ctx context.Context, studyPostID int64) (entity.TechStacks, *errors.RestErr)
+	DeleteTechStack(ctx context.Context, techName string) *errors.RestErr
+	CheckTechStack(ctx context.Context, techStack []string) *errors.RestErr
 }
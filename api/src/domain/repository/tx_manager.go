@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/code-wave/go-wave/infrastructure/errors"
+)
+
+// TxManager 여러 repository 호출을 하나의 transaction으로 묶음 (unit of work)
+type TxManager interface {
+	// WithinTx fn에 넘긴 ctx로 호출한 repository는 같은 transaction을 씀, fn이 error를 반환하면 rollback
+	WithinTx(ctx context.Context, fn func(ctx context.Context) *errors.RestErr) *errors.RestErr
+}
@@ -1,18 +1,19 @@
 package repository
 
 import (
+	"context"
 	"github.com/code-wave/go-wave/domain/entity"
 	"github.com/code-wave/go-wave/infrastructure/errors"
 )
 
 type UserRepository interface {
-	Save(*entity.User) *errors.RestErr
-	Get(*entity.User) *errors.RestErr
-	GetUserByID(int64) (*entity.User, *errors.RestErr)
-	GetAll(int64, int64) (entity.Users, *errors.RestErr)
-	Update(*entity.User) *errors.RestErr
-	Delete(int64) *errors.RestErr
-	FindByEmailAndPassword(*entity.User) (*entity.User, *errors.RestErr)
-	FindByEmail(string) *errors.RestErr
-	FindByNickname(string) *errors.RestErr
+	Save(context.Context, *entity.User) *errors.RestErr
+	Get(context.Context, *entity.User) *errors.RestErr
+	GetUserByID(context.Context, int64) (*entity.User, *errors.RestErr)
+	GetAll(context.Context, int64, int64) (entity.Users, *errors.RestErr)
+	Update(context.Context, *entity.User) *errors.RestErr
+	Delete(context.Context, int64) *errors.RestErr
+	FindByEmailAndPassword(context.Context, *entity.User) (*entity.User, *errors.RestErr)
+	FindByEmail(context.Context, string) *errors.RestErr
+	FindByNickname(context.Context, string) *errors.RestErr
 }
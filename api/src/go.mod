@@ -1,16 +1,24 @@
 module github.com/code-wave/go-wave
 
-go 1.15
+go 1.16
 
 require (
+	github.com/BurntSushi/toml v1.2.1
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
 	github.com/go-chi/chi/v5 v5.0.3
-	github.com/go-redis/redis/v8 v8.10.0
-	github.com/google/uuid v1.0.0
+	github.com/go-redis/redis/v8 v8.11.4
+	github.com/google/uuid v1.1.2
 	github.com/gorilla/websocket v0.0.0-20170926233335-4201258b820c
 	github.com/jackc/pgx/v4 v4.11.0
 	github.com/lib/pq v1.3.0
 	github.com/pborman/uuid v1.2.0
+	github.com/prometheus/client_golang v1.11.1
 	github.com/rs/cors v1.7.0
+	go.opentelemetry.io/otel v1.3.0
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp v1.3.0
+	go.opentelemetry.io/otel/exporters/stdout/stdouttrace v1.3.0
+	go.opentelemetry.io/otel/sdk v1.3.0
+	go.opentelemetry.io/otel/trace v1.3.0
 	golang.org/x/crypto v0.0.0-20210322153248-0c34fe9e7dc2
+	gopkg.in/yaml.v3 v3.0.1
 )
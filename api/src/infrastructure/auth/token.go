@@ -2,31 +2,34 @@ package auth
 
 import (
 	"errors"
-	"log"
 	"strings"
 	"time"
 
 	"github.com/code-wave/go-wave/domain/entity"
-	"github.com/code-wave/go-wave/utils/config"
+	"github.com/code-wave/go-wave/infrastructure/logger"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/google/uuid"
 )
 
-var JwtWrapper = &JwtInfo{
-	AccessTokenKey:  config.AccessTokenKey,
-	RefreshTokenKey: config.RefreshTokenKey,
-	Issuer:          config.Issuer,
-}
-
-var (
-	AtExpiresTime = time.Now().Add(15 * time.Minute)
-	RtExpiresTime = time.Now().Add(24 * 7 * time.Hour)
-)
+// JwtWrapper main에서 설정을 읽은 후 NewJwtInfo로 설정함
+var JwtWrapper = &JwtInfo{}
 
 type JwtInfo struct {
 	AccessTokenKey  string
 	RefreshTokenKey string
 	Issuer          string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+func NewJwtInfo(accessTokenKey, refreshTokenKey, issuer string, accessTokenTTL, refreshTokenTTL time.Duration) *JwtInfo {
+	return &JwtInfo{
+		AccessTokenKey:  accessTokenKey,
+		RefreshTokenKey: refreshTokenKey,
+		Issuer:          issuer,
+		AccessTokenTTL:  accessTokenTTL,
+		RefreshTokenTTL: refreshTokenTTL,
+	}
 }
 
 type Claims struct {
@@ -38,17 +41,15 @@ func (j *JwtInfo) GenerateAccessToken(userID int64) (*entity.AccessToken, error)
 	atClaims := &Claims{
 		UserID: userID,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(15 * time.Minute).Unix(),
+			ExpiresAt: time.Now().Add(j.AccessTokenTTL).Unix(),
 			Issuer:    j.Issuer,
 			IssuedAt:  time.Now().Unix(),
 		},
 	}
 
-	log.Println(atClaims.ExpiresAt)
-
 	tokenSgined, err := jwt.NewWithClaims(jwt.SigningMethodHS256, atClaims).SignedString([]byte(j.AccessTokenKey))
 	if err != nil {
-		log.Println("error when trying to genereate access token with claims,", err)
+		logger.Error("error when trying to generate access token with claims", logger.Err(err), logger.F("user_id", userID))
 		return nil, err
 	}
 
@@ -63,7 +64,7 @@ func (j *JwtInfo) GenerateRefreshToken(userID int64) (*entity.RefreshToken, erro
 	rtClaims := &Claims{
 		UserID: userID,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(24 * 7 * time.Hour).Unix(),
+			ExpiresAt: time.Now().Add(j.RefreshTokenTTL).Unix(),
 			Issuer:    j.Issuer,
 			IssuedAt:  time.Now().Unix(),
 		},
@@ -71,7 +72,7 @@ func (j *JwtInfo) GenerateRefreshToken(userID int64) (*entity.RefreshToken, erro
 
 	tokenSigned, err := jwt.NewWithClaims(jwt.SigningMethodHS256, rtClaims).SignedString([]byte(j.RefreshTokenKey))
 	if err != nil {
-		log.Println("error when trying to generate refresh token with claims, ", err)
+		logger.Error("error when trying to generate refresh token with claims", logger.Err(err), logger.F("user_id", userID))
 		return nil, err
 	}
 
@@ -112,30 +113,30 @@ func (j *JwtInfo) ValidateToken(token string) (*Claims, error) {
 	)
 
 	if err != nil {
-		log.Println("error when parsing token with claims, ", err)
+		logger.Debug("error when parsing token with claims", logger.Err(err))
 		return nil, err
 	}
 
 	claims, ok := parseToken.Claims.(*Claims)
 	if !ok {
-		log.Println("token is not valid")
+		logger.Debug("token is not valid")
 		return nil, err
 	}
 
 	if claims.ExpiresAt < time.Now().Unix() {
-		log.Println("access token expired, ", err)
+		logger.Debug("access token expired", logger.F("user_id", claims.UserID))
 		err = errors.New("access token expired")
 		return nil, err
 	}
 
 	if claims.IssuedAt > time.Now().Unix() {
-		log.Println("token is issued at after now, ", err)
+		logger.Debug("token is issued at after now", logger.F("user_id", claims.UserID))
 		err = errors.New("token is issued at after now")
 		return nil, err
 	}
 
 	if claims.Issuer != j.Issuer {
-		log.Println("token issuer is wrong, ", err)
+		logger.Debug("token issuer is wrong", logger.F("user_id", claims.UserID), logger.F("issuer", claims.Issuer))
 		err = errors.New("token issuer is wrong")
 		return nil, err
 	}
@@ -0,0 +1,50 @@
+package chat
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/code-wave/go-wave/infrastructure/errors"
+)
+
+// linkPattern scheme이나 www.로 시작하는 주소, 자주 쓰이는 도메인으로 끝나는 주소
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)\S+|\b[a-z0-9-]+(\.[a-z0-9-]+)*\.(com|net|org|io|kr|me|ly|gg|co|xyz)\b`)
+
+// ContentFilter 금지어는 *로 가리고, 링크를 막도록 설정하면 링크가 포함된 메시지를 거부함
+type ContentFilter struct {
+	bannedWords *regexp.Regexp // 금지어가 없으면 nil
+	blockLinks  bool
+}
+
+// NewContentFilter bannedWords는 대소문자를 구분하지 않고, 단어 안에 포함되어도 가림
+func NewContentFilter(bannedWords []string, blockLinks bool) *ContentFilter {
+	var quoted []string
+	for _, word := range bannedWords {
+		if word = strings.TrimSpace(word); word != "" {
+			quoted = append(quoted, regexp.QuoteMeta(word))
+		}
+	}
+
+	filter := &ContentFilter{blockLinks: blockLinks}
+	if len(quoted) > 0 {
+		filter.bannedWords = regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+	}
+
+	return filter
+}
+
+// Filter 금지어를 가린 메시지를 반환, 링크가 막혀 있는데 링크가 있으면 에러
+func (f *ContentFilter) Filter(message string) (string, *errors.RestErr) {
+	if f.blockLinks && linkPattern.MatchString(message) {
+		return "", errors.NewBadRequestError("links are not allowed")
+	}
+
+	if f.bannedWords != nil {
+		message = f.bannedWords.ReplaceAllStringFunc(message, func(word string) string {
+			return strings.Repeat("*", utf8.RuneCountInString(word))
+		})
+	}
+
+	return message, nil
+}
@@ -0,0 +1,36 @@
+package chat
+
+import "testing"
+
+func TestContentFilter_Filter(t *testing.T) {
+	filter := NewContentFilter([]string{"바보", "Dumb", " "}, true)
+
+	tests := []struct {
+		message string
+		want    string
+		wantErr bool
+	}{
+		{message: "안녕하세요", want: "안녕하세요"},
+		{message: "너 바보야", want: "너 **야"},
+		{message: "DUMB dumbest", want: "**** ****est"},
+		{message: "version 1.2", want: "version 1.2"},
+		{message: "https://example.com 보세요", wantErr: true},
+		{message: "www.example", wantErr: true},
+		{message: "example.com 보세요", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, restErr := filter.Filter(tt.message)
+		if (restErr != nil) != tt.wantErr {
+			t.Errorf("Filter(%q) error = %v, wantErr %v", tt.message, restErr, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Filter(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+
+	if got, _ := NewContentFilter(nil, false).Filter("https://example.com"); got != "https://example.com" {
+		t.Errorf("links should be allowed, got %q", got)
+	}
+}
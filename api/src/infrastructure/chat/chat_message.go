@@ -2,8 +2,39 @@ package chat
 
 import "github.com/code-wave/go-wave/domain/entity"
 
+// 채팅 메시지가 아닌 이벤트의 message_type (chat_message 테이블에 저장되지 않음)
+const (
+	// MessageTypeRead: 유저가 read_message_id까지 읽었다는 이벤트
+	MessageTypeRead = "read"
+	// MessageTypeTyping: 유저가 입력중이라는 이벤트 (status: "start" | "stop")
+	MessageTypeTyping = "typing"
+	// MessageTypePresence: 유저의 접속 상태가 바뀌었다는 이벤트 (status: "online" | "offline"), 서버만 보냄
+	MessageTypePresence = "presence"
+	// MessageTypeEdit: id의 메시지를 message로 수정 (서버는 수정된 메시지를 같은 타입으로 보냄)
+	MessageTypeEdit = "edit"
+	// MessageTypeDelete: id의 메시지를 삭제 (서버는 삭제된 메시지를 같은 타입으로 보냄)
+	MessageTypeDelete = "delete"
+	// MessageTypeError: 요청한 이벤트를 처리하지 못했을 때 요청한 유저에게만 보냄 (client_message_id 포함)
+	MessageTypeError = "error"
+	// MessageTypeAck: 보낸 메시지가 저장되고 publish된 후 보낸 유저에게만 보냄 (id, client_message_id 포함), 서버만 보냄
+	MessageTypeAck = "ack"
+	// MessageTypeSystem: 팀원 변경 등 서버가 만든 메시지, DB에 저장됨 (status: "joined" | "left", sender가 대상 유저)
+	MessageTypeSystem = "system"
+)
+
+const (
+	SystemStatusJoined = "joined"
+	SystemStatusLeft   = "left"
+)
+
+const (
+	PresenceOnline  = "online"
+	PresenceOffline = "offline"
+)
+
 // response data type to front server
 type Message struct {
+	ID           int64  `json:"id"` // DB에 저장된 후에 부여됨
 	ChatRoomID   int64  `json:"chat_room_id"`
 	ChatRoomName string `json:"chat_room_name"`
 	SenderID     int64  `json:"sender_id"`
@@ -11,12 +42,23 @@ type Message struct {
 	Message      string `json:"message"`
 	MessageType  string `json:"message_type"`
 	CreatedAt    string `json:"created_at"`
+	EditedAt     string `json:"edited_at,omitempty"`
+	Deleted      bool   `json:"deleted,omitempty"` // 삭제된 메시지는 내용 없이 표시만 남김
+	AttachmentID int64  `json:"attachment_id,omitempty"`
+
+	// ClientMessageID client가 만든 메시지 ID (uuid 등)
+	// ack를 받지 못하면 같은 ID로 다시 보내면 되고, 서버는 한 번만 저장함 (다시 보낸 메시지는 다른 유저에게 중복으로 갈 수 있으니 id로 걸러야 함)
+	ClientMessageID string `json:"client_message_id,omitempty"`
+
+	ReadMessageID int64  `json:"read_message_id,omitempty"` // MessageTypeRead 이벤트에서 사용
+	Status        string `json:"status,omitempty"`          // MessageTypeTyping, MessageTypePresence 이벤트에서 사용
 }
 
 type Messages []Message
 
 func NewMessage(chatMessage entity.ChatMessage) Message {
-	return Message{
+	message := Message{
+		ID:           chatMessage.ID,
 		ChatRoomID:   chatMessage.ChatRoomID,
 		ChatRoomName: chatMessage.ChatRoomName,
 		SenderID:     chatMessage.SenderID,
@@ -24,5 +66,27 @@ func NewMessage(chatMessage entity.ChatMessage) Message {
 		Message:      chatMessage.Message,
 		MessageType:  chatMessage.MessageType,
 		CreatedAt:    chatMessage.CreatedAt,
+		EditedAt:     chatMessage.EditedAt.String,
+		AttachmentID: chatMessage.AttachmentID.Int64,
+
+		ClientMessageID: chatMessage.ClientMessageID.String,
+	}
+
+	if chatMessage.DeletedAt.Valid { // tombstone
+		message.Message = ""
+		message.Deleted = true
+		message.AttachmentID = 0
 	}
+
+	return message
+}
+
+func NewMessages(chatMessages []entity.ChatMessage) Messages {
+	var messages Messages
+
+	for _, msg := range chatMessages {
+		messages = append(messages, NewMessage(msg))
+	}
+
+	return messages
 }
@@ -1,13 +1,14 @@
 package chat
 
+// ChatRequest 게시글의 host와 1:1 채팅룸을 열 때 보내는 요청, 유저는 access token으로 확인함
 type ChatRequest struct {
-	UserID      int64 `json:"user_id"`
 	StudyPostID int64 `json:"study_post_id"`
 }
 
+// WsRequest websocket 연결 후 처음 보내는 요청, 유저는 access token으로 확인함
 type WsRequest struct {
-	UserID       int64  `json:"user_id"`
-	ChatRoomName string `json:"chat_room_name"`
+	ChatRoomName  string `json:"chat_room_name"`
+	LastMessageID int64  `json:"last_message_id"` // 재접속시 마지막으로 받은 메시지 ID (0이면 replay 안함)
 }
 
 type ChatServerRequest struct {
@@ -19,3 +19,81 @@ func (r *ChatResponse) ResponseJSON() ([]byte, error) {
 	}
 	return chatJson, nil
 }
+
+// UnreadChatRoom 채팅룸과 안 읽은 메시지 개수, 마지막 메시지 미리보기
+type UnreadChatRoom struct {
+	ChatRoom          entity.ChatRoom `json:"chat_room"`
+	LastReadMessageID int64           `json:"last_read_message_id"`
+	UnreadCount       int64           `json:"unread_count"`
+	LastMessage       *Message        `json:"last_message"`
+}
+
+type UnreadChatRooms []UnreadChatRoom
+
+func NewUnreadChatRoom(unreadRoom entity.UnreadChatRoom) UnreadChatRoom {
+	res := UnreadChatRoom{
+		ChatRoom:          unreadRoom.ChatRoom,
+		LastReadMessageID: unreadRoom.LastReadMessageID,
+		UnreadCount:       unreadRoom.UnreadCount,
+	}
+
+	if unreadRoom.LastMessage != nil {
+		lastMessage := NewMessage(*unreadRoom.LastMessage)
+		res.LastMessage = &lastMessage
+	}
+
+	return res
+}
+
+func (r UnreadChatRooms) ResponseJSON() ([]byte, error) {
+	m := make(map[string]UnreadChatRooms)
+	m["chat_rooms"] = r
+
+	rJSON, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return rJSON, nil
+}
+
+// ChatInboxItem 대화 목록의 항목
+type ChatInboxItem struct {
+	ChatRoom       entity.ChatRoom     `json:"chat_room"`
+	Counterpart    *entity.PublicUser  `json:"counterpart,omitempty"`  // 1:1 채팅의 상대방
+	Participants   []entity.PublicUser `json:"participants,omitempty"` // 팀 채팅의 다른 참여자들
+	StudyPostTitle string              `json:"study_post_title"`
+	UnreadCount    int64               `json:"unread_count"`
+	LastMessage    *Message            `json:"last_message"`
+	LastActivityAt string              `json:"last_activity_at"` // 마지막 메시지 시간 (메시지가 없으면 빈 문자열)
+}
+
+type ChatInbox []ChatInboxItem
+
+func NewChatInboxItem(item entity.ChatInboxItem) ChatInboxItem {
+	res := ChatInboxItem{
+		ChatRoom:       item.ChatRoom,
+		Counterpart:    item.Counterpart,
+		Participants:   item.Participants,
+		StudyPostTitle: item.StudyPostTitle,
+		UnreadCount:    item.UnreadCount,
+	}
+
+	if item.LastMessage != nil {
+		lastMessage := NewMessage(*item.LastMessage)
+		res.LastMessage = &lastMessage
+		res.LastActivityAt = lastMessage.CreatedAt
+	}
+
+	return res
+}
+
+func (i ChatInbox) ResponseJSON() ([]byte, error) {
+	m := make(map[string]ChatInbox)
+	m["inbox"] = i
+
+	iJSON, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return iJSON, nil
+}
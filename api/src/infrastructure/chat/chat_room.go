@@ -2,38 +2,88 @@ package chat
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
-	"log"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/code-wave/go-wave/domain/entity"
 	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/helpers"
+	"github.com/code-wave/go-wave/infrastructure/logger"
+	"github.com/code-wave/go-wave/infrastructure/metrics"
 	"github.com/code-wave/go-wave/infrastructure/persistence"
+	"github.com/code-wave/go-wave/infrastructure/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// client_message_id 최대 길이 (chat_message.client_message_id 컬럼 크기)
+const maxClientMessageIDLength = 64
+
+// roomMessage 메시지와 메시지를 보낸 쪽의 ctx (로그의 request_id, trace span이 이어짐)
+type roomMessage struct {
+	ctx  context.Context
+	data []byte
+}
+
 type ChatRoom struct {
-	register     chan *ChatUser
-	unregister   chan *ChatUser
-	broadcast    chan []byte
-	roomName     string
-	users        map[int64]*ChatUser // key=userID
-	redisService *persistence.RedisService
-	chatRepo     repository.ChatRepository
+	register           chan *ChatUser
+	unregister         chan *ChatUser
+	broadcast          chan roomMessage
+	deliver            chan roomMessage // bus에서 subscribe한 메시지
+	ID                 int64            // 참여자를 확인한 채팅룸의 ID, 저장하는 메시지와 읽음 표시에 씀
+	roomName           string
+	users              map[*ChatUser]bool // 같은 유저가 여러 연결(탭, websocket/SSE)로 접속할 수 있음
+	redisService       *persistence.RedisService
+	bus                RoomBus
+	ephemeralBus       RoomBus // nil이면 bus로 보냄
+	chatRepo           repository.ChatRepository
+	slowConsumerPolicy SlowConsumerPolicy
+	messageEditWindow  time.Duration
+	notifier           MessageNotifier
+	maxMessageLength   int
+	filter             *ContentFilter
+	blockRepo          repository.UserBlockRepository
+	log                *logger.Logger
+
+	ctx    context.Context // 채팅룸을 쓰는 연결이 모두 끊기면 취소됨
+	cancel context.CancelFunc
+	refs   int // 채팅룸을 쓰는 연결 수 (ChatServer.roomsMu로 보호)
 }
 
-func NewChatRoom(roomName string, redis *persistence.RedisService, chatRepository repository.ChatRepository) *ChatRoom {
+// NewChatRoom: chatServer의 redis, repository, 설정을 그대로 사용하는 채팅룸 생성
+func NewChatRoom(roomID int64, roomName string, chatServer *ChatServer) *ChatRoom {
+	ctx, cancel := context.WithCancel(context.Background())
+
 	return &ChatRoom{
-		register:     make(chan *ChatUser),
-		unregister:   make(chan *ChatUser),
-		broadcast:    make(chan []byte),
-		roomName:     roomName,
-		users:        make(map[int64]*ChatUser),
-		redisService: redis,
-		chatRepo:     chatRepository,
+		register:           make(chan *ChatUser),
+		unregister:         make(chan *ChatUser),
+		broadcast:          make(chan roomMessage),
+		deliver:            make(chan roomMessage),
+		ID:                 roomID,
+		roomName:           roomName,
+		users:              make(map[*ChatUser]bool),
+		redisService:       chatServer.redisService,
+		bus:                chatServer.Bus,
+		ephemeralBus:       chatServer.EphemeralBus,
+		chatRepo:           chatServer.chatRepo,
+		slowConsumerPolicy: chatServer.SlowConsumerPolicy,
+		messageEditWindow:  chatServer.MessageEditWindow,
+		notifier:           chatServer.Notifier,
+		maxMessageLength:   chatServer.MaxMessageLength,
+		filter:             chatServer.Filter,
+		blockRepo:          chatServer.BlockRepo,
+		log:                logger.Default().With(logger.F("room", roomName)),
+		ctx:                ctx,
+		cancel:             cancel,
 	}
 }
 
 func (c *ChatRoom) RunRoom() {
-	log.Println(c.redisService)
 	go c.subscribeRoom()
 
 	for {
@@ -46,37 +96,134 @@ func (c *ChatRoom) RunRoom() {
 			c.unregisterUser(user)
 
 		case message := <-c.broadcast:
-			// DB에 메시지 저장 후 publish
-			c.SaveMessage(message)
-			c.publishMessage(message)
+			c.handleMessage(message)
+
+		case message := <-c.deliver:
+			c.deliverMessage(message)
+
+		case <-c.ctx.Done():
+			return
 		}
 	}
 }
 
 func (c *ChatRoom) registerUser(user *ChatUser) {
-	c.users[user.ID] = user
+	c.users[user] = true
 }
 
 func (c *ChatRoom) unregisterUser(user *ChatUser) {
-	if _, ok := c.users[user.ID]; ok {
-		delete(c.users, user.ID)
+	if _, ok := c.users[user]; ok {
+		delete(c.users, user)
 	}
 }
 
 // broadcastToUsers: 채팅룸안에 있는 유저들에게 메시지를 보냄
+// 느린 유저 한명 때문에 방 전체가 막히지 않도록 send queue가 가득 차면 기다리지 않음
 func (c *ChatRoom) broadcastToUsers(message []byte) {
-	for _, user := range c.users {
-		user.Send <- message
+	for user := range c.users {
+		if !user.enqueue(message, c.slowConsumerPolicy) && c.slowConsumerPolicy == DisconnectSlowConsumer {
+			delete(c.users, user)
+		}
 	}
 }
 
-func (c *ChatRoom) SaveMessage(message []byte) { // TODO: 메시지 저장할 때 에러 발생시 어떻게?
+// deliverMessage: publish된 메시지를 유저들에게 보내고, 팀에서 나간 유저는 채팅룸에서 내보냄
+func (c *ChatRoom) deliverMessage(message roomMessage) {
+	_, span := tracing.Start(message.ctx, "chat.deliver", trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attribute.String("chat.room", c.roomName), attribute.Int("chat.recipients", len(c.users))))
+	defer span.End()
+
+	c.broadcastToUsers(message.data)
+
 	var chatMessage Message
+	if err := json.Unmarshal(message.data, &chatMessage); err != nil {
+		c.log.Error("unmarshal error", logger.Err(err))
+		return
+	}
 
-	err := json.Unmarshal(message, &chatMessage)
+	if chatMessage.MessageType == MessageTypeSystem && chatMessage.Status == SystemStatusLeft {
+		for user := range c.users {
+			if user.ID == chatMessage.SenderID {
+				delete(c.users, user)
+				user.close() // ReadPump(StreamPump)가 끝나면서 disconnect를 호출함
+			}
+		}
+	}
+}
+
+// handleMessage: 채팅룸에 접속한 유저가 보낸 메시지를 처리하고 결과(ack, error)를 보낸 유저에게만 보냄
+// 메시지마다 새 trace를 시작하고 연결의 span은 link로만 남김 (연결은 몇 시간씩 이어짐)
+func (c *ChatRoom) handleMessage(message roomMessage) {
+	ctx, span := tracing.Start(message.ctx, "chat.receive", trace.WithNewRoot(),
+		trace.WithLinks(trace.LinkFromContext(message.ctx)), trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attribute.String("chat.room", c.roomName)))
+	defer span.End()
+
+	var chatMessage Message
+
+	err := json.Unmarshal(message.data, &chatMessage)
 	if err != nil {
-		log.Fatal("unmarshal error: ", err.Error())
+		span.RecordError(err)
+		c.logFrom(ctx).Error("unmarshal error", logger.Err(err))
+		return
+	}
+	span.SetAttributes(attribute.String("chat.message_type", chatMessage.MessageType), attribute.Int64("chat.sender_id", chatMessage.SenderID))
+
+	ack, restErr := c.processMessage(ctx, chatMessage)
+	if restErr != nil {
+		c.sendError(chatMessage, restErr.Message)
+		return
+	}
+	if ack != nil {
+		c.sendToUser(chatMessage.SenderID, *ack)
+	}
+}
+
+// processMessage: message_type에 따라 처리한 후 publish, 새 메시지면 보낸 유저에게 보낼 ack를 반환
+// c.users에 접근하지 않으므로 RunRoom 밖에서도 호출할 수 있음 (ChatServer.SendMessage)
+func (c *ChatRoom) processMessage(ctx context.Context, chatMessage Message) (*Message, *errors.RestErr) {
+	switch chatMessage.MessageType {
+	case MessageTypeRead:
+		// 읽음 표시 저장 후 상대방에게 알림
+		c.saveReadMarker(ctx, chatMessage)
+		return nil, c.publishEvent(ctx, chatMessage)
+	case MessageTypeTyping, MessageTypePresence:
+		// 저장하지 않고 상대방에게 전달만 함
+		return nil, c.publishEvent(ctx, chatMessage)
+	case MessageTypeEdit:
+		return nil, c.editMessage(ctx, chatMessage)
+	case MessageTypeDelete:
+		return nil, c.deleteMessage(ctx, chatMessage)
+	default:
+		// DB에 메시지 저장 후 publish (저장할 때 부여된 메시지 ID를 포함해서 보냄)
+		// 저장과 publish가 모두 성공해야 ack를 보냄, ack를 못 받은 client는 같은 client_message_id로 다시 보냄
+		if restErr := c.checkBlocked(ctx); restErr != nil {
+			return nil, restErr
+		}
+		message, restErr := c.checkContent(chatMessage.Message)
+		if restErr != nil {
+			return nil, restErr
+		}
+		chatMessage.Message = message
+
+		savedMessage, restErr := c.SaveMessage(ctx, chatMessage)
+		if restErr != nil {
+			return nil, restErr
+		}
+		if restErr = c.publishEvent(ctx, *savedMessage); restErr != nil {
+			return nil, restErr
+		}
+		if c.notifier != nil {
+			c.notifier.NotifyMessage(ctx, *savedMessage)
+		}
+		return newAck(*savedMessage), nil
 	}
+}
+
+// SaveMessage: 메시지를 DB에 저장 (저장에 실패하면 publish하지 않고 보낸 유저에게 에러를 보냄)
+func (c *ChatRoom) SaveMessage(ctx context.Context, chatMessage Message) (_ *Message, restErr *errors.RestErr) {
+	ctx, span := tracing.Start(ctx, "chat.save")
+	defer func() { tracing.End(span, restErr) }()
 
 	// DB에 저장하기 위한 객체 생성
 	var savedMessage entity.ChatMessage
@@ -88,26 +235,229 @@ func (c *ChatRoom) SaveMessage(message []byte) { // TODO: 메시지 저장할 
 	savedMessage.MessageType = chatMessage.MessageType
 	savedMessage.Message = chatMessage.Message
 	savedMessage.CreatedAt = chatMessage.CreatedAt
+	if chatMessage.ClientMessageID != "" {
+		if len(chatMessage.ClientMessageID) > maxClientMessageIDLength {
+			return nil, errors.NewBadRequestError("client_message_id is too long")
+		}
+		savedMessage.ClientMessageID = sql.NullString{String: chatMessage.ClientMessageID, Valid: true}
+	}
+	if chatMessage.AttachmentID > 0 {
+		savedMessage.AttachmentID = sql.NullInt64{Int64: chatMessage.AttachmentID, Valid: true}
+	}
+
+	newMessage, restErr := c.chatRepo.SaveChatMessage(ctx, &savedMessage)
+	if restErr != nil {
+		c.logFrom(ctx).Error("save chat message error", logger.F("error", restErr.Message), logger.F("sender_id", chatMessage.SenderID))
+		return nil, restErr
+	}
+
+	span.SetAttributes(attribute.Int64("chat.message_id", newMessage.ID))
+	message := NewMessage(*newMessage)
+	return &message, nil
+}
+
+// checkContent: 메시지 길이를 확인하고 필터를 적용한 메시지를 반환
+func (c *ChatRoom) checkContent(message string) (string, *errors.RestErr) {
+	if c.maxMessageLength > 0 && utf8.RuneCountInString(message) > c.maxMessageLength {
+		return "", errors.NewBadRequestError(fmt.Sprintf("message can't be longer than %d characters", c.maxMessageLength))
+	}
+
+	if c.filter == nil {
+		return message, nil
+	}
+	return c.filter.Filter(message)
+}
+
+// checkBlocked: 1:1 채팅룸에서 한 명이라도 상대를 차단했으면 메시지를 보낼 수 없음
+func (c *ChatRoom) checkBlocked(ctx context.Context) *errors.RestErr {
+	if c.blockRepo == nil {
+		return nil
+	}
+
+	blocked, restErr := c.blockRepo.IsDirectChatRoomBlocked(ctx, c.roomName)
+	if restErr != nil {
+		return restErr
+	}
+	if blocked {
+		return errors.NewForbiddenError("can't send messages to this chatroom")
+	}
+
+	return nil
+}
 
-	_, restErr := c.chatRepo.SaveChatMessage(&savedMessage)
+// saveReadMarker: client가 보낸 chat_room_id가 아니라 이 채팅룸에 읽음 표시를 저장
+func (c *ChatRoom) saveReadMarker(ctx context.Context, readEvent Message) {
+	marker := entity.ChatReadMarker{
+		ChatRoomID:        c.ID,
+		UserID:            readEvent.SenderID,
+		LastReadMessageID: readEvent.ReadMessageID,
+	}
+
+	if restErr := c.chatRepo.SaveReadMarker(ctx, &marker); restErr != nil {
+		c.logFrom(ctx).Error("save read marker error", logger.F("error", restErr.Message), logger.F("user_id", readEvent.SenderID))
+	}
+}
+
+// editMessage: 메시지를 수정하고 수정된 메시지를 edit 이벤트로 publish
+func (c *ChatRoom) editMessage(ctx context.Context, editEvent Message) *errors.RestErr {
+	if strings.TrimSpace(editEvent.Message) == "" {
+		return errors.NewBadRequestError("edited message can't be empty")
+	}
+	message, restErr := c.checkContent(editEvent.Message)
+	if restErr != nil {
+		return restErr
+	}
+	editEvent.Message = message
+
+	editedMessage, restErr := c.chatRepo.EditChatMessage(ctx, c.ID, editEvent.ID, editEvent.SenderID, editEvent.Message, c.messageEditWindow)
+	if restErr != nil {
+		return restErr
+	}
+
+	return c.publishModifiedMessage(ctx, MessageTypeEdit, editedMessage)
+}
+
+// deleteMessage: 메시지를 삭제하고 tombstone을 delete 이벤트로 publish
+func (c *ChatRoom) deleteMessage(ctx context.Context, deleteEvent Message) *errors.RestErr {
+	deletedMessage, restErr := c.chatRepo.DeleteChatMessage(ctx, c.ID, deleteEvent.ID, deleteEvent.SenderID, c.messageEditWindow)
 	if restErr != nil {
-		log.Fatal("save chat message error: ", restErr.Message)
+		return restErr
+	}
+
+	return c.publishModifiedMessage(ctx, MessageTypeDelete, deletedMessage)
+}
+
+func (c *ChatRoom) publishModifiedMessage(ctx context.Context, messageType string, chatMessage *entity.ChatMessage) *errors.RestErr {
+	event := NewMessage(*chatMessage)
+	event.MessageType = messageType
+
+	return c.publishEvent(ctx, event)
+}
+
+// sendError: 이벤트를 보낸 유저에게만 에러를 보냄
+func (c *ChatRoom) sendError(event Message, errMessage string) {
+	event.ChatRoomName = c.roomName
+	c.sendToUser(event.SenderID, newError(event, errMessage))
+}
+
+// newError: 처리하지 못한 이벤트에 대한 에러 메시지
+func newError(event Message, errMessage string) Message {
+	return Message{
+		ID:              event.ID,
+		ChatRoomName:    event.ChatRoomName,
+		MessageType:     MessageTypeError,
+		Message:         errMessage,
+		CreatedAt:       helpers.GetDateString(time.Now()),
+		ClientMessageID: event.ClientMessageID,
+	}
+}
+
+// newAck: 메시지를 보낸 유저에게 저장된 메시지의 id를 알려주는 ack
+func newAck(savedMessage Message) *Message {
+	return &Message{
+		ID:              savedMessage.ID,
+		ChatRoomID:      savedMessage.ChatRoomID,
+		ChatRoomName:    savedMessage.ChatRoomName,
+		MessageType:     MessageTypeAck,
+		CreatedAt:       savedMessage.CreatedAt,
+		ClientMessageID: savedMessage.ClientMessageID,
+	}
+}
+
+// sendToUser: 이 인스턴스에서 채팅룸에 접속한 유저의 연결들에게만 보냄
+func (c *ChatRoom) sendToUser(userID int64, event Message) {
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		c.log.Error("marshal error", logger.Err(err))
+		return
+	}
+
+	for user := range c.users {
+		if user.ID == userID {
+			user.enqueue(eventJSON, c.slowConsumerPolicy)
+		}
 	}
 }
 
-func (c *ChatRoom) publishMessage(message []byte) {
-	err := c.redisService.RClient.Publish(context.Background(), c.roomName, message).Err()
+// publishEvent: span의 trace context도 함께 publish해서 다른 인스턴스의 chat.deliver가 같은 trace에 남음
+func (c *ChatRoom) publishEvent(ctx context.Context, event Message) (restErr *errors.RestErr) {
+	ctx, span := tracing.Start(ctx, "chat.publish", trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attribute.String("chat.room", c.roomName), attribute.String("chat.message_type", event.MessageType)))
+	defer func() { tracing.End(span, restErr) }()
+
+	eventJSON, err := json.Marshal(event)
 	if err != nil {
-		log.Fatal("redis publish error: ", err.Error())
+		c.logFrom(ctx).Error("marshal error", logger.Err(err))
+		return errors.NewInternalServerError("marshal error " + err.Error())
 	}
+
+	if err := c.publishMessage(ctx, event.MessageType, eventJSON); err != nil {
+		span.RecordError(err)
+		c.logFrom(ctx).Error("room bus publish error", logger.Err(err), logger.F("message_type", event.MessageType))
+		return errors.NewInternalServerError("failed to deliver message")
+	}
+	metrics.ChatMessages.WithLabelValues(metricMessageType(event.MessageType)).Inc()
+
+	return nil
 }
 
+// metricMessageType: 저장되는 메시지는 client가 보낸 message_type과 상관없이 "message"로 셈 (label 수 제한)
+func metricMessageType(messageType string) string {
+	switch messageType {
+	case MessageTypeRead, MessageTypeTyping, MessageTypePresence, MessageTypeEdit, MessageTypeDelete, MessageTypeSystem:
+		return messageType
+	}
+	return "message"
+}
+
+// publishMessage: 요청이 끊겨도 저장한 메시지는 끝까지 publish함, typing, presence는 ephemeralBus로 보냄
+func (c *ChatRoom) publishMessage(ctx context.Context, messageType string, message []byte) error {
+	bus := c.bus
+	if isEphemeral(messageType) && c.ephemeralBus != nil {
+		bus = c.ephemeralBus
+	}
+	return bus.Publish(logger.Detach(ctx), c.roomName, message)
+}
+
+// isEphemeral: 저장하지 않고 재접속해도 다시 보낼 필요 없는 이벤트
+func isEphemeral(messageType string) bool {
+	return messageType == MessageTypeTyping || messageType == MessageTypePresence
+}
+
+// logFrom: ctx의 request_id와 채팅룸 이름을 남기는 logger
+func (c *ChatRoom) logFrom(ctx context.Context) *logger.Logger {
+	return logger.FromContext(ctx).With(logger.F("room", c.roomName))
+}
+
+// subscribeRoom: bus, ephemeralBus로 받은 메시지를 RunRoom을 통해 채팅룸안에 있는 유저들에게 보냄
 func (c *ChatRoom) subscribeRoom() {
+	if c.ephemeralBus != nil {
+		go c.subscribeBus(c.ephemeralBus)
+	}
+	c.subscribeBus(c.bus)
+}
+
+// subscribeBus: 구독이 끊기면 마지막으로 받은 offset부터 다시 구독해서 끊긴 동안의 메시지도 보냄
+func (c *ChatRoom) subscribeBus(bus RoomBus) {
+	var offset string
 
-	pubsub := c.redisService.RClient.Subscribe(context.Background(), c.roomName)
-	ch := pubsub.Channel()
+	for {
+		err := bus.Subscribe(c.ctx, c.roomName, offset, func(msgCtx context.Context, messageOffset string, message []byte) {
+			offset = messageOffset
+			select {
+			case c.deliver <- roomMessage{ctx: msgCtx, data: message}:
+			case <-c.ctx.Done():
+			}
+		})
+		if err == nil || c.ctx.Err() != nil {
+			return
+		}
 
-	for msg := range ch { // publish로 받은 메시지를 채팅룸안에 있는 유저들에게 보냄
-		c.broadcastToUsers([]byte(msg.Payload))
+		c.log.Error("room bus subscribe error", logger.Err(err))
+		select {
+		case <-time.After(busRetryInterval):
+		case <-c.ctx.Done():
+			return
+		}
 	}
 }
@@ -0,0 +1,206 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/infrastructure/memory"
+)
+
+// newTestRoom client(1)와 host(2)의 1:1 채팅룸, RunRoom 없이 processMessage로 메시지를 처리함
+func newTestRoom(t *testing.T) (*ChatRoom, *memory.Repositories, *entity.ChatRoom) {
+	repos := memory.NewRepositories()
+	roomInfo, restErr := repos.Chat.SaveChatRoom(context.Background(), 1, 2, 1)
+	if restErr != nil {
+		t.Fatal(restErr)
+	}
+
+	server := &ChatServer{
+		Bus:               NewMemoryBus(),
+		chatRepo:          repos.Chat,
+		MessageEditWindow: defaultMessageEditWindow,
+	}
+	room := NewChatRoom(roomInfo.ID, roomInfo.RoomName, server)
+	t.Cleanup(room.cancel)
+
+	return room, repos, roomInfo
+}
+
+// sendTestMessage 유저가 보낸 것처럼 메시지를 처리하고 ack를 반환
+func sendTestMessage(t *testing.T, room *ChatRoom, senderID int64, chatMessage Message) *Message {
+	fromClient(&chatMessage, senderID, "nickname")
+	chatMessage.ChatRoomID = room.ID
+	chatMessage.ChatRoomName = room.roomName
+
+	ack, restErr := room.processMessage(context.Background(), chatMessage)
+	if restErr != nil {
+		t.Fatal(restErr)
+	}
+	return ack
+}
+
+func unreadCount(t *testing.T, repos *memory.Repositories, roomID, userID int64) int64 {
+	unreadRooms, restErr := repos.Chat.GetUnreadChatRooms(context.Background(), userID)
+	if restErr != nil {
+		t.Fatal(restErr)
+	}
+
+	for _, unreadRoom := range unreadRooms {
+		if unreadRoom.ChatRoom.ID == roomID {
+			return unreadRoom.UnreadCount
+		}
+	}
+	t.Fatalf("chat room %d is not in unread chat rooms", roomID)
+	return 0
+}
+
+func TestChatRoom_ReadMarker(t *testing.T) {
+	room, repos, roomInfo := newTestRoom(t)
+
+	first := sendTestMessage(t, room, roomInfo.HostID, Message{MessageType: "message", Message: "first"})
+	last := sendTestMessage(t, room, roomInfo.HostID, Message{MessageType: "message", Message: "second"})
+	sendTestMessage(t, room, roomInfo.ClientID, Message{MessageType: "message", Message: "mine"})
+
+	if count := unreadCount(t, repos, roomInfo.ID, roomInfo.ClientID); count != 2 {
+		t.Fatalf("unread count before read = %d, want 2", count)
+	}
+
+	// client가 보낸 chat_room_id는 무시하고 이 채팅룸에 저장함
+	readEvent := Message{MessageType: MessageTypeRead, SenderID: roomInfo.ClientID, ChatRoomID: roomInfo.ID + 100, ReadMessageID: first.ID}
+	if _, restErr := room.processMessage(context.Background(), readEvent); restErr != nil {
+		t.Fatal(restErr)
+	}
+	if count := unreadCount(t, repos, roomInfo.ID, roomInfo.ClientID); count != 1 {
+		t.Errorf("unread count after reading first message = %d, want 1", count)
+	}
+
+	readEvent.ReadMessageID = last.ID
+	if _, restErr := room.processMessage(context.Background(), readEvent); restErr != nil {
+		t.Fatal(restErr)
+	}
+	if count := unreadCount(t, repos, roomInfo.ID, roomInfo.ClientID); count != 0 {
+		t.Errorf("unread count after reading last message = %d, want 0", count)
+	}
+	// 읽음 표시는 다른 유저의 안 읽은 개수에 영향을 주지 않음
+	if count := unreadCount(t, repos, roomInfo.ID, roomInfo.HostID); count != 1 {
+		t.Errorf("host unread count = %d, want 1", count)
+	}
+}
+
+func TestChatRoom_EditWindow(t *testing.T) {
+	room, repos, roomInfo := newTestRoom(t)
+	room.messageEditWindow = 100 * time.Millisecond
+
+	ack := sendTestMessage(t, room, roomInfo.ClientID, Message{MessageType: "message", Message: "before"})
+
+	editEvent := Message{ID: ack.ID, SenderID: roomInfo.ClientID, MessageType: MessageTypeEdit, Message: "edited"}
+	if _, restErr := room.processMessage(context.Background(), editEvent); restErr != nil {
+		t.Fatalf("edit in edit window: %v", restErr)
+	}
+
+	time.Sleep(room.messageEditWindow)
+
+	editEvent.Message = "too late"
+	if _, restErr := room.processMessage(context.Background(), editEvent); restErr == nil || restErr.Status != http.StatusForbidden {
+		t.Errorf("edit after edit window = %v, want forbidden", restErr)
+	}
+	deleteEvent := Message{ID: ack.ID, SenderID: roomInfo.ClientID, MessageType: MessageTypeDelete}
+	if _, restErr := room.processMessage(context.Background(), deleteEvent); restErr == nil || restErr.Status != http.StatusForbidden {
+		t.Errorf("delete after edit window = %v, want forbidden", restErr)
+	}
+
+	saved, restErr := repos.Chat.GetChatMessage(context.Background(), ack.ID)
+	if restErr != nil {
+		t.Fatal(restErr)
+	}
+	if saved.Message != "edited" || saved.DeletedAt.Valid {
+		t.Errorf("message after rejected edit and delete = %+v", saved)
+	}
+}
+
+func TestChatRoom_DedupeClientMessageID(t *testing.T) {
+	room, repos, roomInfo := newTestRoom(t)
+
+	chatMessage := Message{MessageType: "message", Message: "hello", ClientMessageID: "client-message-1"}
+	ack := sendTestMessage(t, room, roomInfo.ClientID, chatMessage)
+	// ack를 받지 못한 client가 같은 client_message_id로 다시 보냄
+	retryAck := sendTestMessage(t, room, roomInfo.ClientID, chatMessage)
+	if retryAck.ID != ack.ID || retryAck.ClientMessageID != chatMessage.ClientMessageID {
+		t.Errorf("retry ack = %+v, want message %d", retryAck, ack.ID)
+	}
+
+	// 다른 유저는 같은 client_message_id를 써도 새로 저장됨
+	otherAck := sendTestMessage(t, room, roomInfo.HostID, chatMessage)
+	if otherAck.ID == ack.ID {
+		t.Error("message of another sender was deduplicated")
+	}
+
+	messages, restErr := repos.Chat.GetChatMessages(context.Background(), roomInfo.ID)
+	if restErr != nil {
+		t.Fatal(restErr)
+	}
+	if len(messages) != 2 {
+		t.Errorf("saved %d messages, want 2", len(messages))
+	}
+
+	chatMessage.ClientMessageID = strings.Repeat("a", maxClientMessageIDLength+1)
+	fromClient(&chatMessage, roomInfo.ClientID, "nickname")
+	if _, restErr := room.processMessage(context.Background(), chatMessage); restErr == nil || restErr.Status != http.StatusBadRequest {
+		t.Errorf("too long client_message_id = %v, want bad request", restErr)
+	}
+}
+
+func TestChatRoom_EphemeralBus(t *testing.T) {
+	room, _, roomInfo := newTestRoom(t)
+	bus, ephemeralBus := NewMemoryBus(), NewMemoryBus()
+	room.bus, room.ephemeralBus = bus, ephemeralBus
+
+	user := NewChatStreamUser(context.Background(), roomInfo.ClientID, "name", "nickname", &ChatServer{InstanceID: "test", SendBufferSize: 10})
+	go room.RunRoom()
+	room.register <- user
+
+	published := func(b *MemoryBus) int64 {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		return b.room(roomInfo.RoomName).lastSeq
+	}
+
+	// 구독이 시작되기 전에 publish한 메시지는 받지 않으므로 받을 때까지 보냄
+	receive := func(chatMessage Message) Message {
+		t.Helper()
+		for i := 0; i < 50; i++ {
+			sendTestMessage(t, room, roomInfo.HostID, chatMessage)
+			select {
+			case data := <-user.Send:
+				var received Message
+				if err := json.Unmarshal(data, &received); err != nil {
+					t.Fatal(err)
+				}
+				return received
+			case <-time.After(100 * time.Millisecond):
+			}
+		}
+		t.Fatalf("%s event wasn't delivered", chatMessage.MessageType)
+		return Message{}
+	}
+
+	if received := receive(Message{MessageType: MessageTypeTyping}); received.MessageType != MessageTypeTyping {
+		t.Errorf("received %+v, want typing event", received)
+	}
+	if published(bus) != 0 || published(ephemeralBus) == 0 {
+		t.Errorf("typing event published to bus %d times and to ephemeral bus %d times, want only ephemeral bus", published(bus), published(ephemeralBus))
+	}
+
+	ephemeralCount := published(ephemeralBus)
+	if received := receive(Message{MessageType: "message", Message: "hello"}); received.Message != "hello" {
+		t.Errorf("received %+v, want hello", received)
+	}
+	if published(bus) == 0 || published(ephemeralBus) != ephemeralCount {
+		t.Error("saved message should be published only to bus")
+	}
+}
@@ -1,73 +1,498 @@
 package chat
 
 import (
-	"log"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/code-wave/go-wave/domain/entity"
 	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/helpers"
+	"github.com/code-wave/go-wave/infrastructure/logger"
+	"github.com/code-wave/go-wave/infrastructure/metrics"
 	"github.com/code-wave/go-wave/infrastructure/persistence"
+	"github.com/code-wave/go-wave/infrastructure/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const (
+	defaultMessageEditWindow = 15 * time.Minute
+	defaultMaxMessageLength  = 2000
+)
+
+// routedMessage 다른 인스턴스에 연결된 유저에게 보내는 메시지 (chat:instance:{instanceID} 채널로 전달)
+type routedMessage struct {
+	UserID  int64           `json:"user_id"`
+	Message json.RawMessage `json:"message"`
+}
+
+// ChatServer 인스턴스 하나의 채팅 서버
+// 연결된 유저와 채팅룸은 인스턴스마다 따로 관리하고, 채팅룸 메시지는 Bus로, 특정 유저에게 보내는 메시지는 인스턴스 채널로 다른 인스턴스에 전달함
+// 메시지는 유저가 연결된 인스턴스에서만 저장하고 다른 인스턴스는 전달만 하므로 한 번만 저장됨 (client_message_id로 재전송도 한 번만 저장)
 type ChatServer struct {
 	//users      entity.Users
-	chatUsers    map[int64]*ChatUser // key=userID
+	chatUsers    map[int64]map[*ChatUser]bool // key=userID, 이 인스턴스에 연결된 유저의 연결들 (Run에서만 접근)
 	Register     chan ChatServerRequest
 	Unregister   chan ChatServerRequest
+	route        chan routedMessage
 	rooms        map[string]*ChatRoom // key=ChatRoomName
+	roomsMu      sync.Mutex
 	redisService *persistence.RedisService
 	chatRepo     repository.ChatRepository
+
+	// InstanceID: 이 인스턴스를 구분하는 ID, 유저의 연결 ID(presence)에 포함됨
+	InstanceID string
+
+	// Bus: 인스턴스 사이에 채팅룸 메시지를 전달 (default: Redis Streams)
+	Bus RoomBus
+	// EphemeralBus: 저장하지 않고 놓쳐도 되는 이벤트(typing, presence)를 전달, stream에 남기지 않음 (default: Redis Pub/Sub, nil이면 Bus로 보냄)
+	EphemeralBus RoomBus
+
+	// SlowConsumerPolicy: send queue가 가득 찬 유저를 어떻게 처리할지 (default: 연결 끊기)
+	SlowConsumerPolicy SlowConsumerPolicy
+	// MessageEditWindow: 메시지를 보낸 후 수정/삭제할 수 있는 시간
+	MessageEditWindow time.Duration
+	// Notifier: 새 메시지가 저장되면 호출됨 (nil이면 알림을 보내지 않음)
+	Notifier MessageNotifier
+
+	// MaxFrameSize: websocket frame 최대 크기 (bytes)
+	MaxFrameSize int64
+	// SendBufferSize: 유저 한 명의 send queue 크기, 가득 차면 SlowConsumerPolicy를 적용
+	SendBufferSize int
+	// MaxMessageLength: 메시지 최대 글자 수
+	MaxMessageLength int
+	// RateLimiter: 유저가 보내는 메시지 수 제한 (nil이면 제한하지 않음)
+	RateLimiter repository.MessageRateLimiter
+	// Filter: 금지어, 링크 필터 (nil이면 거르지 않음)
+	Filter *ContentFilter
+	// BlockRepo: 1:1 채팅룸에서 차단한 유저의 메시지를 막음 (nil이면 확인하지 않음)
+	BlockRepo repository.UserBlockRepository
+
+	closing      chan struct{} // Shutdown을 시작하면 닫힘
+	kill         chan struct{} // Shutdown이 timeout되면 닫힘, 남은 연결을 바로 끊음
+	drained      chan struct{} // Shutdown 중 이 인스턴스의 연결이 모두 끊기면 닫힘
+	closeOnce    sync.Once
+	killOnce     sync.Once
+	drainOnce    sync.Once
+	runningRooms int             // RunRoom 중인 채팅룸 수 (roomsMu로 보호)
+	roomsStopped chan struct{}   // Shutdown 중 runningRooms가 0이 되면 닫힘 (roomsMu로 보호)
+	ctx          context.Context // Shutdown이 끝나면 취소됨 (인스턴스 채널 구독)
+	cancel       context.CancelFunc
+}
+
+// MessageNotifier 저장된 새 메시지를 받아서 offline 참여자에게 알림을 보냄 (호출한 채팅룸을 막지 않아야 함)
+type MessageNotifier interface {
+	NotifyMessage(ctx context.Context, message Message)
 }
 
 func NewChatServer(redis *persistence.RedisService, chatRepo repository.ChatRepository) *ChatServer {
+	ctx, cancel := context.WithCancel(context.Background())
+
 	return &ChatServer{
-		chatUsers:    make(map[int64]*ChatUser),
-		Register:     make(chan ChatServerRequest),
-		Unregister:   make(chan ChatServerRequest),
-		rooms:        make(map[string]*ChatRoom),
-		redisService: redis,
-		chatRepo:     chatRepo,
+		chatUsers:          make(map[int64]map[*ChatUser]bool),
+		Register:           make(chan ChatServerRequest),
+		Unregister:         make(chan ChatServerRequest),
+		route:              make(chan routedMessage),
+		rooms:              make(map[string]*ChatRoom),
+		redisService:       redis,
+		chatRepo:           chatRepo,
+		InstanceID:         uuid.New().String(),
+		Bus:                NewRedisStreamBus(redis.RClient),
+		EphemeralBus:       NewRedisPubSubBus(redis.RClient),
+		SlowConsumerPolicy: DisconnectSlowConsumer,
+		MessageEditWindow:  defaultMessageEditWindow,
+		MaxFrameSize:       defaultMaxFrameSize,
+		SendBufferSize:     defaultSendBufferSize,
+		MaxMessageLength:   defaultMaxMessageLength,
+		closing:            make(chan struct{}),
+		kill:               make(chan struct{}),
+		drained:            make(chan struct{}),
+		ctx:                ctx,
+		cancel:             cancel,
 	}
 }
 
+// Run: 유저 등록, 해제와 다른 인스턴스에서 온 메시지를 처리, Shutdown 후에도 남은 연결이 끊길 때까지 처리해야 하므로 반환하지 않음
 func (c *ChatServer) Run() {
+	go c.subscribeInstance()
+
+	closing, kill := c.closing, c.kill
 	for {
 		select {
 
 		case req := <-c.Register:
 			c.registerUser(req.User, req.ChatRoomName)
+			// Shutdown 중에 연결한 유저도 바로 끊음
+			if kill == nil {
+				req.User.close()
+			} else if closing == nil {
+				req.User.shutdown()
+			}
 
 		case req := <-c.Unregister:
 			c.unregisterUser(req.User, req.ChatRoomName)
+			if closing == nil {
+				c.checkDrained()
+			}
+
+		case msg := <-c.route:
+			c.sendToLocalUser(msg.UserID, msg.Message)
+
+		case <-closing:
+			closing = nil
+			c.forEachLocalUser((*ChatUser).shutdown)
+			c.checkDrained()
+
+		case <-kill:
+			kill = nil
+			c.forEachLocalUser((*ChatUser).close)
 		}
 	}
 }
 
+func (c *ChatServer) forEachLocalUser(fn func(user *ChatUser)) {
+	for _, users := range c.chatUsers {
+		for user := range users {
+			fn(user)
+		}
+	}
+}
+
+// checkDrained: Shutdown 중 이 인스턴스의 연결이 모두 끊겼으면 Shutdown에 알림
+func (c *ChatServer) checkDrained() {
+	if len(c.chatUsers) == 0 {
+		c.drainOnce.Do(func() {
+			close(c.drained)
+		})
+	}
+}
+
+// Shutdown: 새 메시지를 받지 않도록 연결된 모든 유저에게 close frame을 보내고 (SSE는 밀린 메시지를 보낸 후 끊음)
+// 연결이 모두 끊기고 채팅룸들이 처리 중인 메시지를 마저 처리할 때까지 기다린 후 인스턴스 채널 구독을 닫음
+// ctx가 끝나면 남은 연결을 바로 끊고 ctx.Err()를 반환
+func (c *ChatServer) Shutdown(ctx context.Context) error {
+	defer c.cancel()
+
+	c.closeOnce.Do(func() {
+		close(c.closing)
+	})
+
+	select {
+	case <-c.drained:
+	case <-ctx.Done():
+		c.killOnce.Do(func() {
+			close(c.kill)
+		})
+		return ctx.Err()
+	}
+
+	select {
+	case <-c.roomsIdle():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// roomsIdle: 실행 중인 채팅룸이 모두 닫히면 닫히는 채널을 반환
+func (c *ChatServer) roomsIdle() <-chan struct{} {
+	c.roomsMu.Lock()
+	defer c.roomsMu.Unlock()
+
+	stopped := make(chan struct{})
+	if c.runningRooms == 0 {
+		close(stopped)
+	} else {
+		c.roomsStopped = stopped
+	}
+
+	return stopped
+}
+
 func (c *ChatServer) registerUser(user *ChatUser, roomName string) {
-	c.chatUsers[user.ID] = user
-	c.rooms[roomName].register <- user
+	if _, ok := c.chatUsers[user.ID]; !ok {
+		c.chatUsers[user.ID] = make(map[*ChatUser]bool)
+	}
+	c.chatUsers[user.ID][user] = true
+	user.ChatRooms[roomName].register <- user
 }
 
 func (c *ChatServer) unregisterUser(user *ChatUser, roomName string) {
-	if _, ok := c.chatUsers[user.ID]; ok {
-		delete(c.chatUsers, user.ID)
-		c.rooms[roomName].unregister <- user
+	if _, ok := c.chatUsers[user.ID][user]; ok {
+		delete(c.chatUsers[user.ID], user)
+		if len(c.chatUsers[user.ID]) == 0 {
+			delete(c.chatUsers, user.ID)
+		}
 	}
+	user.ChatRooms[roomName].unregister <- user
 }
 
-//CreateRoom: 메모리상에 채팅룸 생성
-func (c *ChatServer) CreateRoom(roomName string) *ChatRoom {
-	room := NewChatRoom(roomName, c.redisService, c.chatRepo)
+//CreateRoom: 메모리상에 채팅룸 생성 (이미 있으면 기존 채팅룸을 반환), roomID는 참여자를 확인한 채팅룸의 ID
+// 채팅룸을 쓰는 연결이 끊기면 releaseRoom을 호출해야 하고, 쓰는 연결이 없으면 채팅룸을 닫음
+func (c *ChatServer) CreateRoom(roomID int64, roomName string) *ChatRoom {
+	c.roomsMu.Lock()
+	defer c.roomsMu.Unlock()
 
-	c.rooms[roomName] = room
-	log.Println(c.rooms)
-
-	go room.RunRoom()
+	room, ok := c.rooms[roomName]
+	if !ok {
+		room = NewChatRoom(roomID, roomName, c)
+		c.rooms[roomName] = room
+		c.runningRooms++
+		metrics.ChatRooms.Inc()
+		go c.runRoom(room)
+	}
+	room.refs++
 
 	return room
 }
 
+// runRoom: 채팅룸이 닫히면 Shutdown에 알림
+func (c *ChatServer) runRoom(room *ChatRoom) {
+	room.RunRoom()
+
+	c.roomsMu.Lock()
+	defer c.roomsMu.Unlock()
+
+	c.runningRooms--
+	metrics.ChatRooms.Dec()
+	if c.runningRooms == 0 && c.roomsStopped != nil {
+		close(c.roomsStopped)
+		c.roomsStopped = nil
+	}
+}
+
+// releaseRoom: 채팅룸을 쓰는 연결이 모두 끊기면 채팅룸을 닫고 bus 구독도 끝냄
+func (c *ChatServer) releaseRoom(room *ChatRoom) {
+	c.roomsMu.Lock()
+	defer c.roomsMu.Unlock()
+
+	room.refs--
+	if room.refs > 0 {
+		return
+	}
+
+	if c.rooms[room.roomName] == room {
+		delete(c.rooms, room.roomName)
+	}
+	room.cancel()
+}
+
 func (c *ChatServer) GetRoomByName(roomName string) *ChatRoom {
+	c.roomsMu.Lock()
+	defer c.roomsMu.Unlock()
+
 	if room, ok := c.rooms[roomName]; ok {
 		return room
 	}
 	return nil
 }
+
+// SendMessage: websocket 없이 HTTP로 보낸 메시지를 처리 (websocket으로 보낸 것과 같이 저장하고 bus로 publish)
+// chatMessage.ChatRoomID는 참여자를 확인한 채팅룸의 ID여야 함, 새 메시지면 ack를 반환
+func (c *ChatServer) SendMessage(ctx context.Context, senderID int64, senderName string, chatMessage Message) (*Message, *errors.RestErr) {
+	if !fromClient(&chatMessage, senderID, senderName) {
+		return nil, errors.NewBadRequestError("message_type can't be sent by client")
+	}
+	if restErr := c.allowMessage(ctx, chatMessage); restErr != nil {
+		return nil, restErr
+	}
+
+	// 유저를 등록하지 않고 메시지 처리에만 쓰는 채팅룸 (RunRoom을 실행하지 않음)
+	room := NewChatRoom(chatMessage.ChatRoomID, chatMessage.ChatRoomName, c)
+	defer room.cancel()
+
+	ctx, span := tracing.Start(ctx, "chat.receive", trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attribute.String("chat.room", chatMessage.ChatRoomName), attribute.String("chat.message_type", chatMessage.MessageType)))
+	defer span.End()
+
+	return room.processMessage(ctx, chatMessage)
+}
+
+// allowMessage: 저장되는 이벤트(새 메시지, 수정, 삭제)를 유저마다 RateLimiter로 제한
+// redis 에러로 확인하지 못하면 메시지를 막지 않음
+func (c *ChatServer) allowMessage(ctx context.Context, chatMessage Message) *errors.RestErr {
+	if c.RateLimiter == nil {
+		return nil
+	}
+
+	switch chatMessage.MessageType {
+	case MessageTypeRead, MessageTypeTyping:
+		return nil
+	}
+
+	allowed, restErr := c.RateLimiter.Allow(ctx, chatMessage.SenderID)
+	if restErr != nil {
+		logger.FromContext(ctx).Error("chat rate limit error", logger.F("error", restErr.Message), logger.F("user_id", chatMessage.SenderID))
+		return nil
+	}
+	if !allowed {
+		return errors.NewTooManyRequestsError("too many messages, slow down")
+	}
+
+	return nil
+}
+
+func instanceChannel(instanceID string) string {
+	return "chat:instance:" + instanceID
+}
+
+// SendToUser: 유저가 연결된 모든 인스턴스로 메시지를 보냄 (유저의 연결 ID로 인스턴스를 찾음)
+func (c *ChatServer) SendToUser(ctx context.Context, userID int64, message []byte) {
+	connIDs, restErr := c.redisService.Presence.GetConnections(ctx, userID)
+	if restErr != nil {
+		logger.Error("get connections error", logger.F("error", restErr.Message), logger.F("user_id", userID))
+		return
+	}
+
+	routedJSON, err := json.Marshal(routedMessage{UserID: userID, Message: message})
+	if err != nil {
+		logger.Error("marshal error", logger.Err(err))
+		return
+	}
+
+	instances := make(map[string]bool)
+	for _, connID := range connIDs {
+		instanceID := strings.SplitN(connID, ":", 2)[0]
+		if instances[instanceID] {
+			continue
+		}
+		instances[instanceID] = true
+
+		if err := c.redisService.RClient.Publish(logger.Detach(ctx), instanceChannel(instanceID), routedJSON).Err(); err != nil {
+			logger.Error("redis publish error", logger.Err(err), logger.F("instance_id", instanceID))
+		}
+	}
+}
+
+// subscribeInstance: 다른 인스턴스가 이 인스턴스의 유저에게 보낸 메시지를 받아서 Run으로 넘김 (Shutdown이 끝나면 구독을 닫음)
+func (c *ChatServer) subscribeInstance() {
+	pubsub := c.redisService.RClient.Subscribe(c.ctx, instanceChannel(c.InstanceID))
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			var routed routedMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &routed); err != nil {
+				logger.Error("unmarshal error", logger.Err(err))
+				continue
+			}
+			c.route <- routed
+
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// sendToLocalUser: 이 인스턴스에 있는 유저의 모든 연결에 메시지를 보냄
+func (c *ChatServer) sendToLocalUser(userID int64, message []byte) {
+	for user := range c.chatUsers[userID] {
+		if !user.enqueue(message, c.SlowConsumerPolicy) && c.SlowConsumerPolicy == DisconnectSlowConsumer {
+			delete(c.chatUsers[userID], user)
+		}
+	}
+
+	if len(c.chatUsers[userID]) == 0 {
+		delete(c.chatUsers, userID)
+	}
+}
+
+// Announce: 서버가 만든 메시지를 채팅룸 bus에 publish (모든 인스턴스의 채팅룸이 받음)
+// SystemStatusLeft면 채팅룸이 대상 유저의 연결을 끊음
+func (c *ChatServer) Announce(ctx context.Context, chatMessage *entity.ChatMessage, status string) {
+	announcement := NewMessage(*chatMessage)
+	announcement.Status = status
+
+	announcementJSON, err := json.Marshal(announcement)
+	if err != nil {
+		logger.Error("marshal error", logger.Err(err))
+		return
+	}
+
+	if err := c.Bus.Publish(logger.Detach(ctx), chatMessage.ChatRoomName, announcementJSON); err != nil {
+		logger.Error("room bus publish error", logger.Err(err), logger.F("room", chatMessage.ChatRoomName))
+	}
+}
+
+// RemoveMessage: 모더레이터가 삭제한 메시지를 delete 이벤트로 publish (모든 인스턴스의 채팅룸이 받음)
+func (c *ChatServer) RemoveMessage(ctx context.Context, chatMessage *entity.ChatMessage) *errors.RestErr {
+	event := NewMessage(*chatMessage)
+	event.MessageType = MessageTypeDelete
+
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return errors.NewInternalServerError("marshal error " + err.Error())
+	}
+
+	if err := c.Bus.Publish(logger.Detach(ctx), chatMessage.ChatRoomName, eventJSON); err != nil {
+		logger.Error("room bus publish error", logger.Err(err), logger.F("room", chatMessage.ChatRoomName))
+		return errors.NewInternalServerError("failed to deliver message")
+	}
+
+	return nil
+}
+
+// connectPresence: 유저를 online으로 표시하고 offline이었으면 유저가 속한 방에 알림
+func (c *ChatServer) connectPresence(user *ChatUser) {
+	changed, err := c.redisService.Presence.Connect(user.ctx, user.ID, user.connID, user.roomIDs(), presenceTTL)
+	if err != nil {
+		user.log.Error("connect presence error", logger.F("error", err.Message))
+		return
+	}
+
+	if changed {
+		c.broadcastPresence(user, PresenceOnline)
+	}
+}
+
+// refreshPresence: heartbeat를 받을 때마다 연결의 만료시간을 갱신
+func (c *ChatServer) refreshPresence(user *ChatUser) {
+	if err := c.redisService.Presence.Refresh(user.ctx, user.ID, user.connID, user.roomIDs(), presenceTTL); err != nil {
+		user.log.Error("refresh presence error", logger.F("error", err.Message))
+	}
+}
+
+// disconnectPresence: 유저의 마지막 연결이 끊기면 유저가 속한 방에 offline을 알림
+func (c *ChatServer) disconnectPresence(user *ChatUser) {
+	changed, err := c.redisService.Presence.Disconnect(user.ctx, user.ID, user.connID, user.roomIDs())
+	if err != nil {
+		user.log.Error("disconnect presence error", logger.F("error", err.Message))
+		return
+	}
+
+	if changed {
+		c.broadcastPresence(user, PresenceOffline)
+	}
+}
+
+func (c *ChatServer) broadcastPresence(user *ChatUser, status string) {
+	for _, room := range user.ChatRooms {
+		event := Message{
+			ChatRoomName: room.roomName,
+			SenderID:     user.ID,
+			SenderName:   user.Nickname,
+			MessageType:  MessageTypePresence,
+			Status:       status,
+			CreatedAt:    helpers.GetDateString(time.Now()),
+		}
+
+		eventJSON, err := json.Marshal(event)
+		if err != nil {
+			logger.Error("marshal error", logger.Err(err))
+			continue
+		}
+
+		room.broadcast <- roomMessage{ctx: user.ctx, data: eventJSON}
+	}
+}
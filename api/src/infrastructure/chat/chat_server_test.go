@@ -0,0 +1,235 @@
+package chat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/persistence"
+	"github.com/code-wave/go-wave/utils/config"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// sharedChatRepo 두 인스턴스가 같이 쓰는 DB 역할 (메시지 저장만 구현)
+type sharedChatRepo struct {
+	repository.ChatRepository
+
+	mu       sync.Mutex
+	messages []entity.ChatMessage
+}
+
+func (r *sharedChatRepo) SaveChatMessage(ctx context.Context, msg *entity.ChatMessage) (*entity.ChatMessage, *errors.RestErr) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, saved := range r.messages {
+		if msg.ClientMessageID.Valid && saved.SenderID == msg.SenderID && saved.ClientMessageID == msg.ClientMessageID {
+			return &saved, nil
+		}
+	}
+
+	newMsg := *msg
+	newMsg.ID = int64(len(r.messages) + 1)
+	r.messages = append(r.messages, newMsg)
+
+	return &newMsg, nil
+}
+
+func (r *sharedChatRepo) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.messages)
+}
+
+// newTestInstance ServeChatWs처럼 유저를 채팅룸에 연결해주는 API 인스턴스
+func newTestInstance(t *testing.T, redisService *persistence.RedisService, chatRepo repository.ChatRepository) (*ChatServer, *httptest.Server) {
+	chatServer := NewChatServer(redisService, chatRepo)
+	go chatServer.Run()
+
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := strconv.ParseInt(r.URL.Query().Get("user_id"), 10, 64)
+		roomName := r.URL.Query().Get("room")
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+
+		chatUser := NewChatUser(context.Background(), userID, "name", "nickname"+strconv.FormatInt(userID, 10), conn, chatServer)
+		chatUser.ChatRooms[roomName] = chatServer.CreateRoom(1, roomName)
+		chatServer.Register <- ChatServerRequest{User: chatUser, ChatRoomName: roomName}
+
+		go chatUser.ReadPump()
+		go chatUser.WritePump()
+	}))
+	t.Cleanup(srv.Close)
+
+	return chatServer, srv
+}
+
+func dialTestInstance(t *testing.T, srv *httptest.Server, userID int64, roomName string) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "?user_id=" + strconv.FormatInt(userID, 10) + "&room=" + roomName
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+// readUntil 원하는 메시지가 올 때까지 읽음 (한 frame에 여러 메시지가 newline으로 붙어서 올 수 있음)
+func readUntil(t *testing.T, conn *websocket.Conn, match func(Message) bool) Message {
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, line := range bytes.Split(data, newline) {
+			var msg Message
+			if err := json.Unmarshal(line, &msg); err != nil {
+				t.Fatal(err)
+			}
+			if match(msg) {
+				return msg
+			}
+		}
+	}
+}
+
+func TestChatServer_TwoInstances(t *testing.T) {
+	cfg, _, err := config.Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redisService, err := persistence.NewRedisDB(cfg.Redis.Host, cfg.Redis.Port, cfg.Redis.Password)
+	if err != nil {
+		t.Skip("redis is not available: ", err.Error())
+	}
+
+	chatRepo := &sharedChatRepo{}
+	server1, srv1 := newTestInstance(t, redisService, chatRepo)
+	_, srv2 := newTestInstance(t, redisService, chatRepo)
+
+	roomName := uuid.New().String()
+	conn1 := dialTestInstance(t, srv1, 1, roomName)
+	conn2 := dialTestInstance(t, srv2, 2, roomName)
+
+	// 두 인스턴스의 채팅룸이 bus 구독을 시작할 때까지 기다림
+	time.Sleep(500 * time.Millisecond)
+
+	chatMessage := Message{
+		ChatRoomName:    roomName,
+		SenderID:        1,
+		SenderName:      "nickname1",
+		Message:         "hello",
+		MessageType:     "message",
+		ClientMessageID: "client-message-1",
+	}
+	messageJSON, _ := json.Marshal(chatMessage)
+
+	// ack를 받지 못한 client가 같은 메시지를 다시 보내는 경우
+	for i := 0; i < 2; i++ {
+		if err := conn1.WriteMessage(websocket.TextMessage, messageJSON); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ack := readUntil(t, conn1, func(m Message) bool { return m.MessageType == MessageTypeAck })
+	if ack.ClientMessageID != chatMessage.ClientMessageID || ack.ID == 0 {
+		t.Errorf("unexpected ack %+v", ack)
+	}
+
+	received := readUntil(t, conn2, func(m Message) bool { return m.MessageType == chatMessage.MessageType })
+	if received.ID != ack.ID || received.Message != chatMessage.Message {
+		t.Errorf("unexpected message on other instance %+v", received)
+	}
+
+	retryAck := readUntil(t, conn1, func(m Message) bool { return m.MessageType == MessageTypeAck })
+	if retryAck.ID != ack.ID {
+		t.Errorf("retried message was saved again, ack %+v", retryAck)
+	}
+	if count := chatRepo.count(); count != 1 {
+		t.Errorf("message saved %d times, want 1", count)
+	}
+
+	// 다른 인스턴스에 연결된 유저에게 직접 보내기
+	notice, _ := json.Marshal(Message{ChatRoomName: roomName, MessageType: MessageTypeSystem, Message: "notice"})
+	server1.SendToUser(context.Background(), 2, notice)
+
+	routed := readUntil(t, conn2, func(m Message) bool { return m.MessageType == MessageTypeSystem })
+	if routed.Message != "notice" {
+		t.Errorf("unexpected routed message %+v", routed)
+	}
+}
+
+func TestChatServer_Shutdown(t *testing.T) {
+	cfg, _, err := config.Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redisService, err := persistence.NewRedisDB(cfg.Redis.Host, cfg.Redis.Port, cfg.Redis.Password)
+	if err != nil {
+		t.Skip("redis is not available: ", err.Error())
+	}
+
+	chatRepo := &sharedChatRepo{}
+	server, srv := newTestInstance(t, redisService, chatRepo)
+
+	roomName := uuid.New().String()
+	conn := dialTestInstance(t, srv, 1, roomName)
+	time.Sleep(300 * time.Millisecond)
+
+	messageJSON, _ := json.Marshal(Message{ChatRoomName: roomName, MessageType: "message", Message: "bye"})
+	if err := conn.WriteMessage(websocket.TextMessage, messageJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	shutdownErr := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdownErr <- server.Shutdown(ctx)
+	}()
+
+	// 밀린 메시지를 받은 후 close frame을 받음 (client가 close frame으로 답해야 Shutdown이 끝남)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		if _, _, err = conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+	if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Errorf("expected going away close frame, got %v", err)
+	}
+
+	if err := <-shutdownErr; err != nil {
+		t.Fatal(err)
+	}
+	if count := chatRepo.count(); count != 1 {
+		t.Errorf("message sent before shutdown saved %d times, want 1", count)
+	}
+
+	// Shutdown 후에 연결한 유저도 바로 끊음
+	lateConn := dialTestInstance(t, srv, 2, roomName)
+	lateConn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, _, err := lateConn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Errorf("expected going away close frame for late connection, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/code-wave/go-wave/infrastructure/logger"
+	"github.com/code-wave/go-wave/infrastructure/metrics"
+)
+
+// isStreamEventID: SSE의 id(Last-Event-ID)로 쓸 수 있는 메시지인지 (DB에 새로 저장된 메시지만 순서대로 ID가 증가함)
+func isStreamEventID(message Message) bool {
+	switch message.MessageType {
+	case MessageTypeRead, MessageTypeTyping, MessageTypePresence, MessageTypeEdit, MessageTypeDelete, MessageTypeError, MessageTypeAck:
+		return false
+	}
+	return message.ID > 0
+}
+
+func writeStreamEvent(w http.ResponseWriter, message []byte) error {
+	var chatMessage Message
+	if err := json.Unmarshal(message, &chatMessage); err == nil && isStreamEventID(chatMessage) {
+		if _, err := fmt.Fprintf(w, "id: %d\n", chatMessage.ID); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "data: %s\n\n", message)
+	return err
+}
+
+// StreamPump: SSE로 연결한 유저에게 send queue의 메시지를 event로 보냄 (websocket의 ReadPump + WritePump 역할)
+// ctx가 끝나거나(client가 연결을 끊음) 서버가 연결을 끊을 때까지 반환하지 않음
+func (c *ChatUser) StreamPump(ctx context.Context, w http.ResponseWriter, flusher http.Flusher) {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.disconnect()
+	}()
+
+	metrics.ChatConnections.Inc()
+	c.WsServer.connectPresence(c)
+
+	for {
+		select {
+		case message, ok := <-c.Send:
+			if !ok {
+				return
+			}
+
+			if err := writeStreamEvent(w, message); err != nil {
+				c.log.Warn("stream write error", logger.Err(err))
+				return
+			}
+
+			// 밀린 메시지를 한 번에 보냄
+			n := len(c.Send)
+			for i := 0; i < n; i++ {
+				if err := writeStreamEvent(w, <-c.Send); err != nil {
+					c.log.Warn("stream write error", logger.Err(err))
+					return
+				}
+			}
+			flusher.Flush()
+
+		case <-ticker.C:
+			// proxy가 idle 연결을 끊지 않도록 comment를 보냄
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+			c.WsServer.refreshPresence(c)
+
+		case <-c.goingAway:
+			// 서버 종료: 밀린 메시지를 보낸 후 연결을 끊음, client는 Last-Event-ID로 다시 연결함
+			for n := len(c.Send); n > 0; n-- {
+				if err := writeStreamEvent(w, <-c.Send); err != nil {
+					c.log.Warn("stream write error", logger.Err(err))
+					return
+				}
+			}
+			flusher.Flush()
+			return
+
+		case <-c.done:
+			return
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -1,11 +1,15 @@
 package chat
 
-import "C"
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"sync"
 	"time"
 
+	"github.com/code-wave/go-wave/infrastructure/helpers"
+	"github.com/code-wave/go-wave/infrastructure/logger"
+	"github.com/code-wave/go-wave/infrastructure/metrics"
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
 
@@ -19,35 +23,107 @@ const (
 	// Send ping interval, must be less then pong wait time
 	pingPeriod = (pongWait * 9) / 10
 
-	// Maximum message size allowed from peer.
-	maxMessageSize = 10000
+	// Default maximum message size allowed from peer.
+	defaultMaxFrameSize = 10000
+
+	// Default maximum number of messages queued for a peer before the slow consumer policy applies
+	defaultSendBufferSize = 256
+
+	// MaxReplayMessages: 재접속시 한번에 다시 보내주는 메시지의 최대 개수
+	MaxReplayMessages = 100
+
+	// Time until the presence of a connection expires unless refreshed by a pong
+	presenceTTL = pongWait + writeWait
 )
 
-//var (
-//	newline = []byte{'\n'}
-//	space = []byte{' '}
-//)
+var newline = []byte{'\n'}
+
+// SlowConsumerPolicy: send queue가 가득 찬 유저에게 메시지를 보낼 때의 처리 방식
+type SlowConsumerPolicy int
+
+const (
+	// DisconnectSlowConsumer: 연결을 끊어서 클라이언트가 재접속하도록 함
+	DisconnectSlowConsumer SlowConsumerPolicy = iota
+	// DropMessage: 새 메시지를 버리고 연결은 유지함
+	DropMessage
+)
 
 type ChatUser struct {
-	ID        int64  `json:"id"`
-	Name      string `json:"name"`
-	Nickname  string `json:"nickname"`
-	conn      *websocket.Conn
+	ID        int64           `json:"id"`
+	Name      string          `json:"name"`
+	Nickname  string          `json:"nickname"`
+	conn      *websocket.Conn // websocket으로 연결한 경우
+	done      chan struct{}   // SSE로 연결한 경우, 서버가 연결을 끊을 때 닫힘
+	doneOnce  sync.Once
+	connID    string // 같은 유저의 여러 연결을 구분하기 위함 (presence), "{instanceID}:{uuid}"
 	Send      chan []byte
 	ChatRooms map[string]*ChatRoom
 	WsServer  *ChatServer
+	closeOnce sync.Once
+	// goingAway: 서버가 종료될 때 닫힘, 밀린 메시지를 보낸 후 연결을 끊음
+	goingAway     chan struct{}
+	goingAwayOnce sync.Once
+	// ctx: 연결을 연 요청의 request id만 가짐 (요청이 끝나도 취소되지 않음), 유저가 보낸 메시지를 처리할 때 씀
+	ctx context.Context
+	log *logger.Logger
 }
 
-func NewChatUser(id int64, name, nickname string, conn *websocket.Conn, wsServer *ChatServer) *ChatUser {
-	return &ChatUser{
+func NewChatUser(ctx context.Context, id int64, name, nickname string, conn *websocket.Conn, wsServer *ChatServer) *ChatUser {
+	c := &ChatUser{
 		ID:        id,
 		Name:      name,
 		Nickname:  nickname,
 		conn:      conn,
-		Send:      make(chan []byte),
+		connID:    wsServer.InstanceID + ":" + uuid.New().String(),
+		Send:      make(chan []byte, wsServer.SendBufferSize),
 		ChatRooms: make(map[string]*ChatRoom),
 		WsServer:  wsServer,
+		goingAway: make(chan struct{}),
 	}
+	c.setContext(ctx)
+	return c
+}
+
+// NewChatStreamUser: websocket을 쓸 수 없는 client를 위해 SSE로 메시지를 받는 유저 (StreamPump로 보냄)
+func NewChatStreamUser(ctx context.Context, id int64, name, nickname string, wsServer *ChatServer) *ChatUser {
+	c := &ChatUser{
+		ID:        id,
+		Name:      name,
+		Nickname:  nickname,
+		done:      make(chan struct{}),
+		connID:    wsServer.InstanceID + ":" + uuid.New().String(),
+		Send:      make(chan []byte, wsServer.SendBufferSize),
+		ChatRooms: make(map[string]*ChatRoom),
+		WsServer:  wsServer,
+		goingAway: make(chan struct{}),
+	}
+	c.setContext(ctx)
+	return c
+}
+
+// setContext: 연결의 로그에 request_id, user_id, conn_id가 남도록 함
+func (c *ChatUser) setContext(ctx context.Context) {
+	c.ctx = logger.Detach(ctx)
+	c.log = logger.FromContext(c.ctx).With(logger.F("user_id", c.ID), logger.F("conn_id", c.connID))
+}
+
+// shutdown: 서버 종료를 알림, WritePump(StreamPump)가 밀린 메시지를 보내고 연결을 끊음
+func (c *ChatUser) shutdown() {
+	c.goingAwayOnce.Do(func() {
+		close(c.goingAway)
+	})
+}
+
+// close: 연결을 끊음, ReadPump(StreamPump)가 끝나면서 disconnect를 호출함
+func (c *ChatUser) close() {
+	if c.conn != nil {
+		c.conn.Close()
+		return
+	}
+
+	c.doneOnce.Do(func() {
+		close(c.done)
+	})
 }
 
 func (c *ChatUser) ReadPump() {
@@ -55,20 +131,26 @@ func (c *ChatUser) ReadPump() {
 		c.disconnect()
 	}()
 
-	c.conn.SetReadLimit(maxMessageSize)
-	//c.conn.SetReadDeadline(time.Now().Add(pongWait))
-	//c.conn.SetPongHandler(func(string) error { c.conn.SetReadDeadline(time.Now().Add(pongWait)); return nil })
+	c.conn.SetReadLimit(c.WsServer.MaxFrameSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		c.WsServer.refreshPresence(c)
+		return nil
+	})
+
+	metrics.ChatConnections.Inc()
+	c.WsServer.connectPresence(c)
 
 	for {
 		_, jsonMessage, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				log.Printf("unexpected close error: %v", err)
+				c.log.Warn("unexpected close error", logger.Err(err))
 			}
 			break
 		}
 
-		log.Printf("%s", jsonMessage)
 		c.handleNewMessage(jsonMessage)
 	}
 
@@ -81,10 +163,11 @@ func (c *ChatUser) WritePump() {
 		c.conn.Close()
 	}()
 
+	goingAway := c.goingAway
 	for {
 		select {
 		case message, ok := <-c.Send: // ok는 채널이 닫혔는지 열렸는지 여부
-			c.conn.SetWriteDeadline(time.Now().Add(writeWait)) // TODO: 없앨지 말지 테스트
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if !ok {
 				// The WsServer closed the channel.
 				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
@@ -95,27 +178,93 @@ func (c *ChatUser) WritePump() {
 			if err != nil {
 				return
 			}
+			w.Write(message) // message = json type
+
+			// Attach queued chat messages to the current websocket message.
+			n := len(c.Send)
+			for i := 0; i < n; i++ {
+				w.Write(newline)
+				w.Write(<-c.Send)
+			}
 
-			if err != nil {
-				log.Fatal("marshal error: ", err.Error())
+			if err := w.Close(); err != nil {
+				return
 			}
-			w.Write(message) // message = json type
 
-			//// Attach queued chat messages to the current websocket message.
-			//n := len(c.Send)
-			//for i := 0; i < n; i++ {
-			//	w.Write(newline)
-			//	w.Write(<-c.Send)
-			//}
-			//
-			//if err := w.Close(); err != nil {
-			//	return
-			//}
-			//case <-ticker.C:
-			//	c.conn.SetWriteDeadline(time.Now().Add(writeWait))
-			//	if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
-			//		return
-			//	}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+
+		case <-goingAway:
+			// 서버 종료: 밀린 메시지를 보낸 후 close frame을 보냄, client가 close frame으로 답하면 ReadPump가 끝남
+			goingAway = nil
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.writeQueued(); err != nil {
+				return
+			}
+			closeMessage := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server is shutting down")
+			if err := c.conn.WriteMessage(websocket.CloseMessage, closeMessage); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeQueued: send queue에 쌓인 메시지를 websocket 메시지 하나로 보냄
+func (c *ChatUser) writeQueued() error {
+	n := len(c.Send)
+	if n == 0 {
+		return nil
+	}
+
+	w, err := c.conn.NextWriter(websocket.TextMessage)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			w.Write(newline)
+		}
+		w.Write(<-c.Send)
+	}
+
+	return w.Close()
+}
+
+// enqueue: 유저의 send queue에 메시지를 넣음. queue가 가득 차면 policy에 따라 처리하고 false 반환
+func (c *ChatUser) enqueue(message []byte, policy SlowConsumerPolicy) bool {
+	select {
+	case c.Send <- message:
+		return true
+	default:
+	}
+
+	switch policy {
+	case DropMessage:
+		c.log.Warn("send queue is full, message dropped")
+		metrics.ChatSendQueueDrops.WithLabelValues("drop").Inc()
+	default:
+		c.log.Warn("send queue is full, disconnecting")
+		metrics.ChatSendQueueDrops.WithLabelValues("disconnect").Inc()
+		c.close()
+	}
+	return false
+}
+
+// Replay: 재접속한 유저가 놓친 메시지를 send queue에 넣음
+// 룸에 등록된 후에 호출하기 때문에 실시간 메시지와 중복될 수 있으니 클라이언트는 메시지 ID로 중복을 거름
+func (c *ChatUser) Replay(messages Messages) {
+	for _, msg := range messages {
+		messageJSON, err := json.Marshal(msg)
+		if err != nil {
+			c.log.Error("marshal error", logger.Err(err))
+			continue
+		}
+
+		if !c.enqueue(messageJSON, c.WsServer.SlowConsumerPolicy) {
+			return
 		}
 	}
 }
@@ -133,24 +282,88 @@ func (c *ChatUser) disconnect() {
 	for _, room := range c.ChatRooms {
 		room.unregister <- c
 	}
-	close(c.Send)
-	c.conn.Close()
+
+	c.WsServer.disconnectPresence(c)
+
+	for _, room := range c.ChatRooms {
+		c.WsServer.releaseRoom(room)
+	}
+
+	// 모든 방에서 빠진 뒤에 닫아야 broadcast 중 닫힌 채널에 보내는 일이 없음
+	c.closeOnce.Do(func() {
+		close(c.Send)
+	})
+	c.close()
+	metrics.ChatConnections.Dec()
+}
+
+// roomIDs: 연결이 접속한 채팅룸들의 ID (presence에 같이 저장함)
+func (c *ChatUser) roomIDs() []int64 {
+	roomIDs := make([]int64, 0, len(c.ChatRooms))
+	for _, room := range c.ChatRooms {
+		roomIDs = append(roomIDs, room.ID)
+	}
+	return roomIDs
 }
 
 func (c *ChatUser) handleNewMessage(jsonMessage []byte) {
 	var chatMessage Message
 
-	err := json.Unmarshal(jsonMessage, &chatMessage)
-	if err != nil { // TODO: 나중에 고루틴 종료 관련 처리
-		log.Fatal("unmarshal error: ", err.Error())
+	if err := json.Unmarshal(jsonMessage, &chatMessage); err != nil {
+		c.sendError(chatMessage, "invalid message format")
+		return
 	}
 
 	roomName := chatMessage.ChatRoomName
 
 	chatRoom, ok := c.ChatRooms[roomName]
 	if !ok {
-		log.Fatal("chatroom doesn't exist")
+		c.sendError(chatMessage, "chatroom doesn't exist")
+		return
+	}
+
+	if !fromClient(&chatMessage, c.ID, c.Nickname) {
+		return
+	}
+	// client가 보낸 chat_room_id가 아니라 접속할 때 참여자를 확인한 채팅룸의 ID로 저장함
+	chatMessage.ChatRoomID = chatRoom.ID
+
+	if restErr := c.WsServer.allowMessage(c.ctx, chatMessage); restErr != nil {
+		c.sendError(chatMessage, restErr.Message)
+		return
+	}
+
+	jsonMessage, err := json.Marshal(chatMessage)
+	if err != nil {
+		c.log.Error("marshal error", logger.Err(err))
+		return
+	}
+
+	chatRoom.broadcast <- roomMessage{ctx: c.ctx, data: jsonMessage}
+}
+
+// sendError: 채팅룸을 거치지 않고 이 연결에만 에러를 보냄 (ReadPump에서만 호출, Send는 ReadPump가 끝날 때 닫힘)
+func (c *ChatUser) sendError(event Message, errMessage string) {
+	errJSON, err := json.Marshal(newError(event, errMessage))
+	if err != nil {
+		c.log.Error("marshal error", logger.Err(err))
+		return
 	}
 
-	chatRoom.broadcast <- jsonMessage
+	c.enqueue(errJSON, c.WsServer.SlowConsumerPolicy)
+}
+
+// fromClient: client가 보낸 메시지를 접속한 유저가 보낸 것으로 바꿈 (다른 유저 대신 보낼 수 없음)
+// 서버만 보낼 수 있는 message_type이면 false
+func fromClient(chatMessage *Message, senderID int64, senderName string) bool {
+	switch chatMessage.MessageType {
+	case MessageTypePresence, MessageTypeSystem, MessageTypeError, MessageTypeAck:
+		return false
+	}
+
+	chatMessage.SenderID = senderID
+	chatMessage.SenderName = senderName
+	chatMessage.CreatedAt = helpers.GetDateString(time.Now())
+
+	return true
 }
@@ -0,0 +1,119 @@
+package chat
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newSlowConsumerServer Run 없이 채팅룸과 유저 생성에만 쓰는 ChatServer (send queue 크기 1)
+func newSlowConsumerServer(policy SlowConsumerPolicy) *ChatServer {
+	return &ChatServer{
+		InstanceID:         "test",
+		Bus:                NewMemoryBus(),
+		SlowConsumerPolicy: policy,
+		SendBufferSize:     1,
+	}
+}
+
+func isClosed(user *ChatUser) bool {
+	select {
+	case <-user.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestChatUser_Enqueue(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     SlowConsumerPolicy
+		wantClosed bool
+	}{
+		{name: "drop message", policy: DropMessage, wantClosed: false},
+		{name: "disconnect", policy: DisconnectSlowConsumer, wantClosed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := NewChatStreamUser(context.Background(), 1, "name", "nickname", newSlowConsumerServer(tt.policy))
+
+			if !user.enqueue([]byte("first"), tt.policy) {
+				t.Fatal("enqueue to empty send queue failed")
+			}
+			if isClosed(user) {
+				t.Fatal("user closed before send queue was full")
+			}
+
+			if user.enqueue([]byte("second"), tt.policy) {
+				t.Fatal("enqueue to full send queue succeeded")
+			}
+			if closed := isClosed(user); closed != tt.wantClosed {
+				t.Errorf("closed = %v, want %v", closed, tt.wantClosed)
+			}
+
+			// 가득 찬 queue의 메시지는 그대로 남음
+			if len(user.Send) != 1 || string(<-user.Send) != "first" {
+				t.Error("queued message was replaced")
+			}
+		})
+	}
+}
+
+// TestChatRoom_SlowConsumer 느린 유저 때문에 채팅룸이 막히지 않고, 끊긴 유저가 unregister할 때도 막히지 않음
+func TestChatRoom_SlowConsumer(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       SlowConsumerPolicy
+		wantClosed   bool
+		wantReceived int
+	}{
+		{name: "drop message", policy: DropMessage, wantClosed: false, wantReceived: 1},
+		{name: "disconnect", policy: DisconnectSlowConsumer, wantClosed: true, wantReceived: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newSlowConsumerServer(tt.policy)
+			room := NewChatRoom(1, "slow-consumer", server)
+			defer room.cancel()
+			go room.RunRoom()
+
+			slowUser := NewChatStreamUser(context.Background(), 1, "name", "slow", server)
+			server.SendBufferSize = 10
+			fastUser := NewChatStreamUser(context.Background(), 2, "name", "fast", server)
+
+			room.register <- slowUser
+			room.register <- fastUser
+
+			messages := []string{`{"message_type":"message","message":"1"}`, `{"message_type":"message","message":"2"}`}
+			for _, message := range messages {
+				select {
+				case room.deliver <- roomMessage{ctx: context.Background(), data: []byte(message)}:
+				case <-time.After(time.Second):
+					t.Fatal("room is blocked by slow consumer")
+				}
+			}
+
+			// 끊긴 유저의 disconnect처럼 unregister (이미 채팅룸에서 빠졌어도 막히지 않아야 함)
+			for _, user := range []*ChatUser{slowUser, fastUser} {
+				select {
+				case room.unregister <- user:
+				case <-time.After(time.Second):
+					t.Fatal("unregister is blocked")
+				}
+			}
+
+			if closed := isClosed(slowUser); closed != tt.wantClosed {
+				t.Errorf("slow user closed = %v, want %v", closed, tt.wantClosed)
+			}
+			if received := len(slowUser.Send); received != tt.wantReceived {
+				t.Errorf("slow user received %d messages, want %d", received, tt.wantReceived)
+			}
+			if isClosed(fastUser) || len(fastUser.Send) != len(messages) {
+				t.Errorf("fast user received %d messages, want %d", len(fastUser.Send), len(messages))
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package chat
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/code-wave/go-wave/infrastructure/tracing"
+)
+
+// MemoryBus 한 프로세스 안에서만 채팅룸 메시지를 전달 (테스트, 인스턴스가 하나일 때)
+// RedisStreamBus처럼 채팅룸마다 최근 streamMaxLen개의 메시지를 남겨서 offset 이후의 메시지부터 받을 수 있음
+type MemoryBus struct {
+	mu    sync.Mutex
+	rooms map[string]*memoryRoomLog
+}
+
+var _ RoomBus = &MemoryBus{}
+
+type memoryRoomLog struct {
+	lastSeq int64
+	entries []memoryBusEntry
+	// published: 새 메시지가 publish되면 close하고 새 채널로 바꿈
+	published chan struct{}
+}
+
+type memoryBusEntry struct {
+	seq     int64
+	message []byte
+	fields  map[string]string // trace context
+}
+
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{rooms: make(map[string]*memoryRoomLog)}
+}
+
+// room mu를 잡은 상태에서 호출
+func (b *MemoryBus) room(roomName string) *memoryRoomLog {
+	r, ok := b.rooms[roomName]
+	if !ok {
+		r = &memoryRoomLog{published: make(chan struct{})}
+		b.rooms[roomName] = r
+	}
+	return r
+}
+
+func (b *MemoryBus) Publish(ctx context.Context, roomName string, message []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	r := b.room(roomName)
+	r.lastSeq++
+	r.entries = append(r.entries, memoryBusEntry{
+		seq:     r.lastSeq,
+		message: append([]byte(nil), message...),
+		fields:  tracing.Inject(ctx),
+	})
+	if len(r.entries) > streamMaxLen {
+		r.entries = append([]memoryBusEntry(nil), r.entries[len(r.entries)-streamMaxLen:]...)
+	}
+
+	close(r.published)
+	r.published = make(chan struct{})
+
+	return nil
+}
+
+func (b *MemoryBus) Subscribe(ctx context.Context, roomName, offset string, handler BusHandler) error {
+	var lastSeq int64
+	if offset == "" {
+		b.mu.Lock()
+		lastSeq = b.room(roomName).lastSeq
+		b.mu.Unlock()
+	} else {
+		seq, err := strconv.ParseInt(offset, 10, 64)
+		if err != nil {
+			return err
+		}
+		lastSeq = seq
+	}
+
+	for {
+		b.mu.Lock()
+		r := b.room(roomName)
+		var entries []memoryBusEntry
+		for _, e := range r.entries {
+			if e.seq > lastSeq {
+				entries = append(entries, e)
+			}
+		}
+		published := r.published
+		b.mu.Unlock()
+
+		for _, e := range entries {
+			lastSeq = e.seq
+			handler(tracing.Extract(ctx, e.fields), strconv.FormatInt(e.seq, 10), e.message)
+		}
+
+		// entries를 가져온 뒤에 publish된 메시지가 있으면 published가 이미 닫혀 있음
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-published:
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package chat
+
+import (
+	"context"
+	"time"
+
+	"github.com/code-wave/go-wave/infrastructure/tracing"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// streamMaxLen: 채팅룸 stream에 남겨두는 메시지 수 (대략적으로 자름)
+	streamMaxLen = 1000
+	// streamTTL: publish가 없으면 채팅룸 stream을 지우는 시간, 지워진 뒤에 놓친 메시지는 DB에서 다시 보냄 (Replay)
+	streamTTL = 24 * time.Hour
+	// streamBlockTimeout: XREAD가 새 메시지를 기다리는 시간, 끝나면 ctx를 확인하고 다시 기다림
+	streamBlockTimeout = 5 * time.Second
+	streamReadCount    = 100
+	streamMessageField = "message"
+	// busRetryInterval: bus 구독이 끊겼을 때 다시 구독하기 전에 기다리는 시간
+	busRetryInterval = time.Second
+)
+
+// BusHandler 구독한 메시지를 받음, msgCtx에는 publish한 쪽의 trace context가 들어 있음 (bus가 지원하면)
+type BusHandler func(msgCtx context.Context, offset string, message []byte)
+
+// RoomBus 채팅룸 메시지를 모든 인스턴스의 채팅룸에 전달
+type RoomBus interface {
+	Publish(ctx context.Context, roomName string, message []byte) error
+	// Subscribe ctx가 끝날 때까지 roomName에 publish된 메시지를 handler에 넘김
+	// offset 이후의 메시지부터 받음 (빈 문자열이면 구독한 이후의 메시지부터), offset을 지원하지 않는 bus는 무시함
+	// 연결이 끊기면 error를 반환하고, 마지막으로 받은 offset으로 다시 구독하면 놓친 메시지부터 받을 수 있음
+	Subscribe(ctx context.Context, roomName, offset string, handler BusHandler) error
+}
+
+// RedisPubSubBus Redis Pub/Sub을 사용 (구독이 끊긴 동안 publish된 메시지는 받지 못함)
+// payload만 보낼 수 있어서 trace context는 넘기지 않음 (받는 쪽에서 새 trace로 시작함)
+type RedisPubSubBus struct {
+	rClient *redis.Client
+}
+
+var _ RoomBus = &RedisPubSubBus{}
+
+func NewRedisPubSubBus(rClient *redis.Client) *RedisPubSubBus {
+	return &RedisPubSubBus{rClient: rClient}
+}
+
+func (b *RedisPubSubBus) Publish(ctx context.Context, roomName string, message []byte) error {
+	return b.rClient.Publish(ctx, roomName, message).Err()
+}
+
+func (b *RedisPubSubBus) Subscribe(ctx context.Context, roomName, offset string, handler BusHandler) error {
+	pubsub := b.rClient.Subscribe(ctx, roomName)
+	defer pubsub.Close()
+
+	// 구독이 확인된 후부터 메시지를 받음
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			handler(ctx, "", []byte(msg.Payload))
+		}
+	}
+}
+
+// RedisStreamBus Redis Streams를 사용, 메시지 ID를 offset으로 써서 연결이 끊겼다가 다시 구독해도 놓친 메시지를 받을 수 있음
+// trace context(traceparent 등)는 message 옆의 field로 같이 보냄, stream은 마지막 publish 후 streamTTL이 지나면 지워짐
+type RedisStreamBus struct {
+	rClient *redis.Client
+}
+
+var _ RoomBus = &RedisStreamBus{}
+
+func NewRedisStreamBus(rClient *redis.Client) *RedisStreamBus {
+	return &RedisStreamBus{rClient: rClient}
+}
+
+func streamKey(roomName string) string {
+	return "chat:stream:" + roomName
+}
+
+func (b *RedisStreamBus) Publish(ctx context.Context, roomName string, message []byte) error {
+	values := map[string]interface{}{streamMessageField: message}
+	for k, v := range tracing.Inject(ctx) {
+		values[k] = v
+	}
+
+	key := streamKey(roomName)
+	_, err := b.rClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream:       key,
+			MaxLenApprox: streamMaxLen,
+			Values:       values,
+		})
+		pipe.Expire(ctx, key, streamTTL)
+		return nil
+	})
+	return err
+}
+
+func (b *RedisStreamBus) Subscribe(ctx context.Context, roomName, offset string, handler BusHandler) error {
+	key := streamKey(roomName)
+
+	if offset == "" {
+		// "$"로 읽으면 XREAD 사이에 들어온 메시지를 놓칠 수 있어서 지금 마지막 ID부터 읽음
+		latest, err := b.latestOffset(ctx, key)
+		if err != nil {
+			return err
+		}
+		offset = latest
+	}
+
+	for {
+		streams, err := b.rClient.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{key, offset},
+			Count:   streamReadCount,
+			Block:   streamBlockTimeout,
+		}).Result()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err == redis.Nil { // 기다리는 동안 새 메시지가 없음
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				offset = msg.ID
+
+				payload, ok := msg.Values[streamMessageField].(string)
+				if !ok {
+					continue
+				}
+				handler(tracing.Extract(ctx, traceFields(msg.Values)), msg.ID, []byte(payload))
+			}
+		}
+	}
+}
+
+func (b *RedisStreamBus) latestOffset(ctx context.Context, key string) (string, error) {
+	messages, err := b.rClient.XRevRangeN(ctx, key, "+", "-", 1).Result()
+	if err != nil {
+		return "", err
+	}
+	if len(messages) == 0 {
+		return "0-0", nil
+	}
+	return messages[0].ID, nil
+}
+
+// traceFields stream 메시지에서 message를 뺀 나머지 field (Publish에서 넣은 trace context)
+func traceFields(values map[string]interface{}) map[string]string {
+	fields := make(map[string]string, len(values))
+	for k, v := range values {
+		if s, ok := v.(string); ok && k != streamMessageField {
+			fields[k] = s
+		}
+	}
+	return fields
+}
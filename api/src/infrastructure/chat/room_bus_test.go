@@ -0,0 +1,121 @@
+package chat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/code-wave/go-wave/infrastructure/persistence"
+	"github.com/code-wave/go-wave/utils/config"
+	"github.com/google/uuid"
+)
+
+type busMessage struct {
+	offset  string
+	message string
+}
+
+// testRoomBus offset을 지원하는 bus(MemoryBus, RedisStreamBus)가 같은 동작을 하는지 확인
+func testRoomBus(t *testing.T, bus RoomBus) {
+	roomName := "room-bus-test-" + uuid.New().String()
+
+	subscribe := func(offset string) <-chan busMessage {
+		ctx, cancel := context.WithCancel(context.Background())
+		received := make(chan busMessage, 100)
+		done := make(chan struct{})
+		t.Cleanup(func() {
+			cancel()
+			<-done
+		})
+
+		go func() {
+			defer close(done)
+			bus.Subscribe(ctx, roomName, offset, func(msgCtx context.Context, offset string, message []byte) {
+				received <- busMessage{offset: offset, message: string(message)}
+			})
+		}()
+		return received
+	}
+	publish := func(message string) {
+		if err := bus.Publish(context.Background(), roomName, []byte(message)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	next := func(received <-chan busMessage) busMessage {
+		t.Helper()
+		select {
+		case m := <-received:
+			return m
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a message")
+			return busMessage{}
+		}
+	}
+
+	// 구독이 시작되기 전에 publish한 메시지는 받지 않으므로 ping을 받을 때까지 보냄
+	received := subscribe("")
+	ready := false
+	for i := 0; i < 50 && !ready; i++ {
+		publish("ping")
+		select {
+		case <-received:
+			ready = true
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	if !ready {
+		t.Fatal("subscriber didn't receive any message")
+	}
+	// 늦게 받은 ping은 건너뜀
+	publish("a")
+	a := next(received)
+	for a.message == "ping" {
+		a = next(received)
+	}
+	publish("b")
+	b := next(received)
+	if a.message != "a" || b.message != "b" || a.offset == "" || a.offset == b.offset {
+		t.Fatalf("received %+v and %+v, want a and b with different offsets", a, b)
+	}
+
+	// 마지막으로 받은 offset부터 다시 구독하면 그 이후의 메시지를 받음
+	resumed := next(subscribe(a.offset))
+	if resumed != b {
+		t.Errorf("resubscribe from %s received %+v, want %+v", a.offset, resumed, b)
+	}
+}
+
+func TestMemoryBus(t *testing.T) {
+	testRoomBus(t, NewMemoryBus())
+}
+
+func TestRedisStreamBus(t *testing.T) {
+	cfg, _, err := config.Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redisService, err := persistence.NewRedisDB(cfg.Redis.Host, cfg.Redis.Port, cfg.Redis.Password)
+	if err != nil {
+		t.Skip("redis is not available: ", err.Error())
+	}
+	t.Cleanup(func() { redisService.RClient.Close() })
+
+	bus := NewRedisStreamBus(redisService.RClient)
+	testRoomBus(t, bus)
+
+	// publish할 때마다 stream의 만료시간을 갱신함
+	roomName := "room-bus-ttl-test-" + uuid.New().String()
+	key := streamKey(roomName)
+	t.Cleanup(func() { redisService.RClient.Del(context.Background(), key) })
+	if err := bus.Publish(context.Background(), roomName, []byte("message")); err != nil {
+		t.Fatal(err)
+	}
+	ttl, err := redisService.RClient.TTL(context.Background(), key).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ttl <= 0 || ttl > streamTTL {
+		t.Errorf("stream ttl = %v, want between 0 and %v", ttl, streamTTL)
+	}
+}
@@ -0,0 +1,18 @@
+package encryption
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Sign payload를 key로 HMAC-SHA256 서명한 hex 문자열
+func Sign(key, payload string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func VerifySignature(key, payload, signature string) bool {
+	return hmac.Equal([]byte(Sign(key, payload)), []byte(signature))
+}
@@ -72,6 +72,15 @@ func NewForbiddenError(message string) *RestErr {
 	}
 }
 
+//429
+func NewTooManyRequestsError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Status:  http.StatusTooManyRequests,
+		Error:   "too_many_requests",
+	}
+}
+
 //401
 func NewUnauthorizedError(message string) *RestErr {
 	return &RestErr{
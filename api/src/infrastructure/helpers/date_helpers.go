@@ -10,8 +10,11 @@ const (
 )
 
 func GetCurrentTimeForDB() string {
-	now := time.Now()
-	res := now.Format("2006-01-02 3:4:5 pm")
+	return GetTimeForDB(time.Now())
+}
+
+func GetTimeForDB(t time.Time) string {
+	res := t.Format("2006-01-02 3:4:5 pm")
 	return res
 }
 
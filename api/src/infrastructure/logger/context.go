@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID RequestIDMiddleware가 요청마다 넣음, 이 ctx를 받은 곳의 로그에 request_id가 남음
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// FromContext ctx에 request id나 trace span이 있으면 request_id, trace_id field를 붙인 기본 Logger
+func FromContext(ctx context.Context) *Logger {
+	if ctx == nil {
+		return std
+	}
+
+	var fields []Field
+	if requestID := RequestID(ctx); requestID != "" {
+		fields = append(fields, F("request_id", requestID))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields = append(fields, F("trace_id", sc.TraceID().String()), F("span_id", sc.SpanID().String()))
+	}
+	if len(fields) == 0 {
+		return std
+	}
+	return std.With(fields...)
+}
+
+// Detach ctx의 값(request id, trace span)은 그대로 두고 취소와 deadline만 뗀 context
+// 요청이 끝나도 계속 쓰는 곳에서 씀 (요청으로 시작한 websocket 연결, 요청이 끊겨도 끝까지 보내야 하는 publish)
+func Detach(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return detached{ctx}
+}
+
+type detached struct{ parent context.Context }
+
+func (detached) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detached) Done() <-chan struct{}               { return nil }
+func (detached) Err() error                          { return nil }
+func (d detached) Value(key interface{}) interface{} { return d.parent.Value(key) }
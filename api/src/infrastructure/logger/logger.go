@@ -0,0 +1,255 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	default:
+		return "error"
+	}
+}
+
+// ParseLevel "debug" | "info" | "warn" | "error"
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", s)
+}
+
+// Field 로그에 함께 남기는 key, value
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err error를 "error" field로 남김
+func Err(err error) Field {
+	if err == nil {
+		return Field{Key: "error", Value: nil}
+	}
+	return Field{Key: "error", Value: err.Error()}
+}
+
+// output 같은 writer에 쓰는 Logger들이 공유 (With로 만든 Logger 포함)
+type output struct {
+	mu     sync.Mutex
+	w      io.Writer
+	level  Level
+	format string
+}
+
+type Logger struct {
+	out    *output
+	fields []Field
+}
+
+// New format은 FormatText 또는 FormatJSON
+func New(w io.Writer, level Level, format string) *Logger {
+	return &Logger{
+		out: &output{w: w, level: level, format: format},
+	}
+}
+
+var std = New(os.Stderr, LevelInfo, FormatText)
+
+// SetDefault 패키지 함수와 FromContext가 쓰는 Logger를 바꿈 (main에서 설정을 읽은 후 한 번 호출)
+func SetDefault(l *Logger) {
+	std = l
+}
+
+func Default() *Logger {
+	return std
+}
+
+// With fields를 모든 로그에 함께 남기는 Logger를 반환
+func (l *Logger) With(fields ...Field) *Logger {
+	merged := make([]Field, 0, len(l.fields)+len(fields))
+	merged = append(merged, l.fields...)
+	merged = append(merged, fields...)
+	return &Logger{out: l.out, fields: merged}
+}
+
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.out.level
+}
+
+func (l *Logger) Debug(msg string, fields ...Field) { l.log(LevelDebug, msg, fields) }
+func (l *Logger) Info(msg string, fields ...Field)  { l.log(LevelInfo, msg, fields) }
+func (l *Logger) Warn(msg string, fields ...Field)  { l.log(LevelWarn, msg, fields) }
+func (l *Logger) Error(msg string, fields ...Field) { l.log(LevelError, msg, fields) }
+
+// Fatal error 로그를 남기고 종료
+func (l *Logger) Fatal(msg string, fields ...Field) {
+	l.log(LevelError, msg, fields)
+	os.Exit(1)
+}
+
+func Debug(msg string, fields ...Field) { std.log(LevelDebug, msg, fields) }
+func Info(msg string, fields ...Field)  { std.log(LevelInfo, msg, fields) }
+func Warn(msg string, fields ...Field)  { std.log(LevelWarn, msg, fields) }
+func Error(msg string, fields ...Field) { std.log(LevelError, msg, fields) }
+
+func Fatal(msg string, fields ...Field) {
+	std.log(LevelError, msg, fields)
+	os.Exit(1)
+}
+
+func (l *Logger) log(level Level, msg string, fields []Field) {
+	if !l.Enabled(level) {
+		return
+	}
+
+	all := make([]Field, 0, len(l.fields)+len(fields))
+	all = append(all, l.fields...)
+	all = append(all, fields...)
+	for i := range all {
+		all[i].Value = redactField(all[i].Key, all[i].Value)
+	}
+	msg = redactString(msg)
+
+	var buf bytes.Buffer
+	now := time.Now().UTC()
+	if l.out.format == FormatJSON {
+		writeJSON(&buf, now, level, msg, all)
+	} else {
+		writeText(&buf, now, level, msg, all)
+	}
+
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	l.out.w.Write(buf.Bytes())
+}
+
+func writeJSON(buf *bytes.Buffer, t time.Time, level Level, msg string, fields []Field) {
+	buf.WriteString(`{"time":`)
+	writeJSONValue(buf, t.Format(time.RFC3339Nano))
+	buf.WriteString(`,"level":`)
+	writeJSONValue(buf, level.String())
+	buf.WriteString(`,"msg":`)
+	writeJSONValue(buf, msg)
+	for _, f := range fields {
+		buf.WriteByte(',')
+		writeJSONValue(buf, f.Key)
+		buf.WriteByte(':')
+		writeJSONValue(buf, f.Value)
+	}
+	buf.WriteString("}\n")
+}
+
+func writeJSONValue(buf *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case error:
+		value = v.Error()
+	case time.Time:
+	case time.Duration:
+		value = v.String()
+	case fmt.Stringer:
+		value = v.String()
+	}
+
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		encoder.Encode(fmt.Sprint(value))
+	}
+	// Encode가 붙인 줄바꿈 제거
+	buf.Truncate(buf.Len() - 1)
+}
+
+// writeText ex) 2021-06-01T12:00:00.000Z INFO db connected host=127.0.0.1
+func writeText(buf *bytes.Buffer, t time.Time, level Level, msg string, fields []Field) {
+	buf.WriteString(t.Format("2006-01-02T15:04:05.000Z07:00"))
+	buf.WriteByte(' ')
+	buf.WriteString(strings.ToUpper(level.String()))
+	buf.WriteByte(' ')
+	buf.WriteString(msg)
+	for _, f := range fields {
+		buf.WriteByte(' ')
+		buf.WriteString(f.Key)
+		buf.WriteByte('=')
+		buf.WriteString(textValue(f.Value))
+	}
+	buf.WriteByte('\n')
+}
+
+func textValue(value interface{}) string {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		return "<nil>"
+	case string:
+		s = v
+	case error:
+		s = v.Error()
+	case time.Time:
+		s = v.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		s = v.String()
+	case []string:
+		s = strings.Join(v, ",")
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// Writer level로 한 줄씩 로그를 남기는 io.Writer (log 패키지, http.Server.ErrorLog 연결용)
+func (l *Logger) Writer(level Level) io.Writer {
+	return &lineWriter{l: l, level: level}
+}
+
+type lineWriter struct {
+	l     *Logger
+	level Level
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.l.log(w.level, strings.TrimRight(string(p), "\n"), nil)
+	return len(p), nil
+}
@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/helpers"
+)
+
+var _ repository.AuthRepository = &AuthRepo{}
+
+// AuthRepo refresh token을 uuid로 저장, ExpiresAt이 지나면 없는 것으로 봄
+type AuthRepo struct {
+	mu     sync.Mutex
+	tokens map[string]entity.RefreshToken
+}
+
+func NewAuthRepository() *AuthRepo {
+	return &AuthRepo{tokens: make(map[string]entity.RefreshToken)}
+}
+
+func (ar *AuthRepo) Create(ctx context.Context, rt *entity.RefreshToken) *errors.RestErr {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	ar.tokens[rt.Uuid] = *rt
+
+	return nil
+}
+
+func (ar *AuthRepo) Delete(ctx context.Context, uuid string) *errors.RestErr {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if _, ok := ar.token(uuid); !ok {
+		return errors.NewUnauthorizedError("unauthorized, refresh token is not valid")
+	}
+	delete(ar.tokens, uuid)
+
+	return nil
+}
+
+func (ar *AuthRepo) Fetch(ctx context.Context, uuid string) (int64, *errors.RestErr) {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	rt, ok := ar.token(uuid)
+	if !ok {
+		return 0, errors.NewUnauthorizedError("unauthorized, refresh token is expired please relogin")
+	}
+
+	return rt.UserID, nil
+}
+
+// token 만료된 token은 지우고 없는 것으로 반환
+func (ar *AuthRepo) token(uuid string) (entity.RefreshToken, bool) {
+	rt, ok := ar.tokens[uuid]
+	if ok && !time.Now().Before(time.Unix(rt.ExpiresAt, 0)) {
+		delete(ar.tokens, uuid)
+		return rt, false
+	}
+	return rt, ok
+}
+
+var _ repository.PresenceRepository = &PresenceRepo{}
+
+// PresenceRepo 유저의 websocket 연결들의 만료시간과 접속한 채팅룸을 저장, 연결이 하나라도 만료되지 않았으면 online
+type PresenceRepo struct {
+	mu        sync.Mutex
+	conns     map[int64]map[string]time.Time
+	connRooms map[string][]int64
+	lastSeen  map[int64]string
+}
+
+func NewPresenceRepository() *PresenceRepo {
+	return &PresenceRepo{
+		conns:     make(map[int64]map[string]time.Time),
+		connRooms: make(map[string][]int64),
+		lastSeen:  make(map[int64]string),
+	}
+}
+
+// Connect 연결을 추가하고 이전에 offline이었는지(= 상태가 바뀌었는지) 반환
+func (pr *PresenceRepo) Connect(ctx context.Context, userID int64, connID string, roomIDs []int64, ttl time.Duration) (bool, *errors.RestErr) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	wasOnline := pr.isOnline(userID)
+	pr.refresh(userID, connID, roomIDs, ttl)
+
+	return !wasOnline, nil
+}
+
+// Refresh 연결의 만료시간을 now + ttl로 갱신
+func (pr *PresenceRepo) Refresh(ctx context.Context, userID int64, connID string, roomIDs []int64, ttl time.Duration) *errors.RestErr {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	pr.refresh(userID, connID, roomIDs, ttl)
+
+	return nil
+}
+
+func (pr *PresenceRepo) refresh(userID int64, connID string, roomIDs []int64, ttl time.Duration) {
+	if pr.conns[userID] == nil {
+		pr.conns[userID] = make(map[string]time.Time)
+	}
+	pr.conns[userID][connID] = time.Now().Add(ttl)
+	pr.connRooms[connID] = roomIDs
+}
+
+// Disconnect 연결을 제거하고 마지막 연결이었으면 last_seen을 저장, offline이 되었는지 반환
+func (pr *PresenceRepo) Disconnect(ctx context.Context, userID int64, connID string, roomIDs []int64) (bool, *errors.RestErr) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	delete(pr.conns[userID], connID)
+	delete(pr.connRooms, connID)
+	if pr.isOnline(userID) { // 다른 연결이 남아있음
+		return false, nil
+	}
+	pr.lastSeen[userID] = helpers.GetDateString(time.Now())
+
+	return true, nil
+}
+
+func (pr *PresenceRepo) GetPresence(ctx context.Context, userID int64) (*entity.Presence, *errors.RestErr) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	presence := &entity.Presence{
+		UserID: userID,
+		Online: pr.isOnline(userID),
+	}
+	if !presence.Online {
+		presence.LastSeen = pr.lastSeen[userID]
+	}
+
+	return presence, nil
+}
+
+// GetConnections 만료되지 않은 연결 ID들
+func (pr *PresenceRepo) GetConnections(ctx context.Context, userID int64) ([]string, *errors.RestErr) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	pr.isOnline(userID)
+
+	var connIDs []string
+	for connID := range pr.conns[userID] {
+		connIDs = append(connIDs, connID)
+	}
+
+	return connIDs, nil
+}
+
+// IsConnectedToRoom 유저가 만료되지 않은 연결로 채팅룸에 접속해 있는지 확인
+func (pr *PresenceRepo) IsConnectedToRoom(ctx context.Context, userID, roomID int64) (bool, *errors.RestErr) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	pr.isOnline(userID)
+
+	for connID := range pr.conns[userID] {
+		for _, connRoomID := range pr.connRooms[connID] {
+			if connRoomID == roomID {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// isOnline 만료되지 않은 연결이 있는지 확인 (만료된 연결은 같이 정리함)
+func (pr *PresenceRepo) isOnline(userID int64) bool {
+	now := time.Now()
+	for connID, expiresAt := range pr.conns[userID] {
+		if expiresAt.Before(now) {
+			delete(pr.conns[userID], connID)
+			delete(pr.connRooms, connID)
+		}
+	}
+	if len(pr.conns[userID]) == 0 {
+		delete(pr.conns, userID)
+		return false
+	}
+	return true
+}
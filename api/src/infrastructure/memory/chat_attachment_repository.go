@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+)
+
+type chatAttachmentRepo struct {
+	s *store
+}
+
+var _ repository.ChatAttachmentRepository = &chatAttachmentRepo{}
+
+func (c *chatAttachmentRepo) SaveAttachment(ctx context.Context, attachment *entity.ChatAttachment) (*entity.ChatAttachment, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	for _, a := range c.s.data.attachments {
+		if a.StorageKey == attachment.StorageKey {
+			return nil, errors.NewInternalServerError("queryrow error duplicate key value violates unique constraint on storage_key")
+		}
+	}
+
+	newAttachment := *attachment
+	newAttachment.ID = c.s.data.nextID("chat_attachment")
+	newAttachment.CreatedAt = now()
+	c.s.data.attachments[newAttachment.ID] = newAttachment
+
+	return &newAttachment, nil
+}
+
+func (c *chatAttachmentRepo) GetAttachment(ctx context.Context, id int64) (*entity.ChatAttachment, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	a, ok := c.s.data.attachments[id]
+	if !ok {
+		return nil, errors.NewNotFoundError("attachment not found")
+	}
+
+	return &a, nil
+}
+
+func (c *chatAttachmentRepo) DeleteAttachment(ctx context.Context, id int64) *errors.RestErr {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	if _, used := c.s.findChatMessage(func(m entity.ChatMessage) bool { return m.AttachmentID.Valid && m.AttachmentID.Int64 == id }); used {
+		return errors.NewInternalServerError("execute error chat_attachment is still referenced from chat_message")
+	}
+
+	delete(c.s.data.attachments, id)
+
+	return nil
+}
+
+var _ repository.BlobStore = &BlobStore{}
+
+// BlobStore 첨부파일 원본을 메모리에 저장 (LocalBlobStore와 같은 key 규칙)
+type BlobStore struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+}
+
+func NewBlobStore() *BlobStore {
+	return &BlobStore{blobs: make(map[string][]byte)}
+}
+
+// path LocalBlobStore처럼 root 밖으로 벗어나는 key는 거부
+func (b *BlobStore) path(key string) (string, *errors.RestErr) {
+	p := path.Join("/blob", key)
+	if !strings.HasPrefix(p, "/blob/") {
+		return "", errors.NewBadRequestError("invalid storage key")
+	}
+	return p, nil
+}
+
+func (b *BlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) *errors.RestErr {
+	p, restErr := b.path(key)
+	if restErr != nil {
+		return restErr
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(r, size+1))
+	if err != nil {
+		return errors.NewInternalServerError("blob store error " + err.Error())
+	}
+	if int64(len(data)) != size {
+		return errors.NewInternalServerError("blob store error size mismatch")
+	}
+	if err := ctx.Err(); err != nil {
+		return errors.NewInternalServerError("blob store error " + err.Error())
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.blobs[p]; ok {
+		return errors.NewInternalServerError("blob store error file exists")
+	}
+	b.blobs[p] = data
+
+	return nil
+}
+
+func (b *BlobStore) Get(ctx context.Context, key string) (io.ReadCloser, *errors.RestErr) {
+	p, restErr := b.path(key)
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	data, ok := b.blobs[p]
+	if !ok {
+		return nil, errors.NewNotFoundError("attachment file not found")
+	}
+
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+// Delete 없는 파일이면 아무것도 하지 않음
+func (b *BlobStore) Delete(ctx context.Context, key string) *errors.RestErr {
+	p, restErr := b.path(key)
+	if restErr != nil {
+		return restErr
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.blobs, p)
+
+	return nil
+}
@@ -0,0 +1,583 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/pborman/uuid"
+)
+
+type chatRepo struct {
+	s *store
+}
+
+var _ repository.ChatRepository = &chatRepo{}
+
+func (c *chatRepo) GetChatRoom(ctx context.Context, clientID, hostID, studyPostID int64) (*entity.ChatRoom, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	room, ok := c.s.findChatRoom(func(r entity.ChatRoom) bool {
+		return r.ClientID == clientID && r.HostID == hostID && r.StudyPostID == studyPostID && r.RoomType == entity.ChatRoomTypeDirect
+	})
+	if !ok {
+		return nil, errors.NewNoRowsError()
+	}
+
+	return &room, nil
+}
+
+func (c *chatRepo) SaveChatRoom(ctx context.Context, clientID, hostID, studyPostID int64) (*entity.ChatRoom, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	room := entity.ChatRoom{
+		ID:          c.s.data.nextID("chat_room"),
+		RoomName:    uuid.New(),
+		ClientID:    clientID,
+		HostID:      hostID,
+		StudyPostID: studyPostID,
+		RoomType:    entity.ChatRoomTypeDirect,
+	}
+	c.s.data.chatRooms[room.ID] = room
+
+	return &room, nil
+}
+
+func (c *chatRepo) GetGroupChatRoom(ctx context.Context, studyPostID int64) (*entity.ChatRoom, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	room, ok := c.s.findChatRoom(func(r entity.ChatRoom) bool {
+		return r.StudyPostID == studyPostID && r.RoomType == entity.ChatRoomTypeGroup
+	})
+	if !ok {
+		return nil, errors.NewNoRowsError()
+	}
+
+	return &room, nil
+}
+
+// SaveGroupChatRoom 게시글의 팀 채팅룸을 만들고 host를 참여자로 추가
+func (c *chatRepo) SaveGroupChatRoom(ctx context.Context, hostID, studyPostID int64) (*entity.ChatRoom, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	// chat_room_group_study_post_id_idx
+	if _, ok := c.s.findChatRoom(func(r entity.ChatRoom) bool {
+		return r.StudyPostID == studyPostID && r.RoomType == entity.ChatRoomTypeGroup
+	}); ok {
+		return nil, errors.NewInternalServerError("query row error duplicate key value violates unique constraint \"chat_room_group_study_post_id_idx\"")
+	}
+
+	room := entity.ChatRoom{
+		ID:          c.s.data.nextID("chat_room"),
+		RoomName:    uuid.New(),
+		ClientID:    hostID,
+		HostID:      hostID,
+		StudyPostID: studyPostID,
+		RoomType:    entity.ChatRoomTypeGroup,
+	}
+	c.s.data.chatRooms[room.ID] = room
+	c.s.data.participants[roomUserKey{room.ID, hostID}] = true
+
+	return &room, nil
+}
+
+func (c *chatRepo) AddChatRoomParticipant(ctx context.Context, roomID, userID int64) *errors.RestErr {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	c.s.data.participants[roomUserKey{roomID, userID}] = true
+
+	return nil
+}
+
+func (c *chatRepo) RemoveChatRoomParticipant(ctx context.Context, roomID, userID int64) *errors.RestErr {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	delete(c.s.data.participants, roomUserKey{roomID, userID})
+
+	return nil
+}
+
+func (c *chatRepo) IsChatRoomParticipant(ctx context.Context, roomID, userID int64) (bool, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	room, ok := c.s.data.chatRooms[roomID]
+	if !ok {
+		return false, nil
+	}
+
+	return c.s.isParticipant(room, userID), nil
+}
+
+func (c *chatRepo) SharesChatRoom(ctx context.Context, userID, otherUserID int64) (bool, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	for _, room := range c.s.data.chatRooms {
+		if c.s.isParticipant(room, userID) && c.s.isParticipant(room, otherUserID) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// GetChatRoomParticipantIDs 채팅룸 참여자들의 user id (1:1이면 client/host, 팀 채팅이면 팀원)
+func (c *chatRepo) GetChatRoomParticipantIDs(ctx context.Context, roomID int64) ([]int64, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	ids := make(map[int64]bool)
+	if room, ok := c.s.data.chatRooms[roomID]; ok && room.RoomType == entity.ChatRoomTypeDirect {
+		ids[room.ClientID] = true
+		ids[room.HostID] = true
+	}
+	for key := range c.s.data.participants {
+		if key.roomID == roomID {
+			ids[key.userID] = true
+		}
+	}
+
+	var userIDs []int64
+	for id := range ids {
+		userIDs = append(userIDs, id)
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+
+	return userIDs, nil
+}
+
+func (c *chatRepo) GetChatRoomByRoomName(ctx context.Context, roomName string) (*entity.ChatRoom, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	room, ok := c.s.findChatRoom(func(r entity.ChatRoom) bool { return r.RoomName == roomName })
+	if !ok {
+		return nil, errors.NewNotFoundError("chat room not found")
+	}
+
+	return &room, nil
+}
+
+func (c *chatRepo) GetChatRoomByID(ctx context.Context, id int64) (*entity.ChatRoom, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	room, ok := c.s.data.chatRooms[id]
+	if !ok {
+		return nil, errors.NewNotFoundError("chat room not found")
+	}
+
+	return &room, nil
+}
+
+func (c *chatRepo) SaveChatMessage(ctx context.Context, msg *entity.ChatMessage) (*entity.ChatMessage, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	if msg.ClientMessageID.Valid {
+		if saved, ok := c.s.findChatMessage(func(m entity.ChatMessage) bool {
+			return m.ChatRoomID == msg.ChatRoomID && m.SenderID == msg.SenderID && m.ClientMessageID == msg.ClientMessageID
+		}); ok {
+			return &saved, nil
+		}
+	}
+
+	// 첨부파일은 같은 채팅룸에 본인이 올린, 아직 다른 메시지에 쓰이지 않은 것만 붙일 수 있음
+	if msg.AttachmentID.Valid {
+		a, ok := c.s.data.attachments[msg.AttachmentID.Int64]
+		if !ok || a.ChatRoomID != msg.ChatRoomID || a.UploaderID != msg.SenderID {
+			return nil, errors.NewBadRequestError("invalid attachment")
+		}
+		if _, used := c.s.findChatMessage(func(m entity.ChatMessage) bool { return m.AttachmentID == msg.AttachmentID }); used {
+			return nil, errors.NewBadRequestError("invalid attachment")
+		}
+	}
+
+	createdAt := time.Now()
+	newMsg := entity.ChatMessage{
+		ID:              c.s.data.nextID("chat_message"),
+		ChatRoomID:      msg.ChatRoomID,
+		ChatRoomName:    msg.ChatRoomName,
+		SenderID:        msg.SenderID,
+		Sender:          msg.Sender,
+		MessageType:     msg.MessageType,
+		Message:         msg.Message,
+		CreatedAt:       now(),
+		AttachmentID:    msg.AttachmentID,
+		ClientMessageID: msg.ClientMessageID,
+	}
+	c.s.data.chatMessages[newMsg.ID] = chatMessageRow{ChatMessage: newMsg, createdAt: createdAt}
+
+	return &newMsg, nil
+}
+
+func (c *chatRepo) GetChatMessageByClientMessageID(ctx context.Context, roomID, senderID int64, clientMessageID string) (*entity.ChatMessage, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	msg, ok := c.s.findChatMessage(func(m entity.ChatMessage) bool {
+		return m.ChatRoomID == roomID && m.SenderID == senderID && m.ClientMessageID.Valid && m.ClientMessageID.String == clientMessageID
+	})
+	if !ok {
+		return nil, errors.NewNotFoundError("chat message not found")
+	}
+
+	return &msg, nil
+}
+
+func (c *chatRepo) GetChatMessage(ctx context.Context, messageID int64) (*entity.ChatMessage, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	row, ok := c.s.data.chatMessages[messageID]
+	if !ok {
+		return nil, errors.NewNotFoundError("chat message doesn't exist")
+	}
+
+	return &row.ChatMessage, nil
+}
+
+func (c *chatRepo) GetChatMessages(ctx context.Context, roomID int64) ([]entity.ChatMessage, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	msgs := c.s.roomMessages(roomID)
+	reverse(msgs)
+
+	return msgs, nil
+}
+
+// GetChatMessagesBefore messageID가 0이면 가장 최근 메시지부터, 최신순으로 반환
+func (c *chatRepo) GetChatMessagesBefore(ctx context.Context, roomID, messageID, limit int64) ([]entity.ChatMessage, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	var msgs []entity.ChatMessage
+	all := c.s.roomMessages(roomID)
+	for i := len(all) - 1; i >= 0 && int64(len(msgs)) < limit; i-- {
+		if messageID == 0 || all[i].ID < messageID {
+			msgs = append(msgs, all[i])
+		}
+	}
+
+	return msgs, nil
+}
+
+// GetChatMessagesAfter messageID 다음 메시지부터 오래된 순으로 반환
+func (c *chatRepo) GetChatMessagesAfter(ctx context.Context, roomID, messageID, limit int64) ([]entity.ChatMessage, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	var msgs []entity.ChatMessage
+	for _, m := range c.s.roomMessages(roomID) {
+		if int64(len(msgs)) >= limit {
+			break
+		}
+		if m.ID > messageID {
+			msgs = append(msgs, m)
+		}
+	}
+
+	return msgs, nil
+}
+
+// ForEachChatMessage fn을 호출하는 동안에는 lock을 잡지 않음
+func (c *chatRepo) ForEachChatMessage(ctx context.Context, roomID int64, fn func(entity.ChatMessage) error) *errors.RestErr {
+	c.s.mu.Lock()
+	msgs := c.s.roomMessages(roomID)
+	c.s.mu.Unlock()
+
+	for _, m := range msgs {
+		if err := fn(m); err != nil {
+			return errors.NewInternalServerError("chat message iteration stopped " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (c *chatRepo) SaveReadMarker(ctx context.Context, marker *entity.ChatReadMarker) *errors.RestErr {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	key := roomUserKey{marker.ChatRoomID, marker.UserID}
+	lastReadMessageID := marker.LastReadMessageID
+	if saved, ok := c.s.data.readMarkers[key]; ok && saved.LastReadMessageID > lastReadMessageID {
+		lastReadMessageID = saved.LastReadMessageID
+	}
+
+	marker.UpdatedAt = now()
+	c.s.data.readMarkers[key] = entity.ChatReadMarker{
+		ChatRoomID:        marker.ChatRoomID,
+		UserID:            marker.UserID,
+		LastReadMessageID: lastReadMessageID,
+		UpdatedAt:         marker.UpdatedAt,
+	}
+
+	return nil
+}
+
+// GetUnreadChatRooms 유저가 속한 채팅룸들을 안 읽은 메시지 개수, 마지막 메시지와 함께 최근 메시지 순으로 반환
+// 자기가 보낸 메시지는 안 읽은 메시지로 세지 않음
+func (c *chatRepo) GetUnreadChatRooms(ctx context.Context, userID int64) ([]entity.UnreadChatRoom, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	var unreadRooms []entity.UnreadChatRoom
+	for _, room := range c.s.userChatRooms(userID) {
+		lastReadMessageID := c.s.data.readMarkers[roomUserKey{room.ID, userID}].LastReadMessageID
+		unreadCount, lastMessage := c.s.unread(room, userID, lastReadMessageID)
+
+		unreadRooms = append(unreadRooms, entity.UnreadChatRoom{
+			ChatRoom:          room,
+			LastReadMessageID: lastReadMessageID,
+			UnreadCount:       unreadCount,
+			LastMessage:       lastMessage,
+		})
+	}
+
+	sort.SliceStable(unreadRooms, func(i, j int) bool {
+		return lastMessageID(unreadRooms[i].LastMessage) > lastMessageID(unreadRooms[j].LastMessage)
+	})
+
+	return unreadRooms, nil
+}
+
+// GetChatInbox 유저가 참여중인 채팅룸들을 상대방(팀 채팅이면 다른 참여자들) 정보, 게시글 제목, 마지막 메시지와 함께 최근 활동순으로 반환
+func (c *chatRepo) GetChatInbox(ctx context.Context, userID int64) ([]entity.ChatInboxItem, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	var inbox []entity.ChatInboxItem
+	for _, room := range c.s.userChatRooms(userID) {
+		studyPost, ok := c.s.data.studyPosts[room.StudyPostID]
+		if !ok {
+			continue
+		}
+		item := entity.ChatInboxItem{ChatRoom: room, StudyPostTitle: studyPost.Title}
+
+		if room.RoomType == entity.ChatRoomTypeDirect {
+			counterpartID := room.HostID
+			if room.HostID == userID {
+				counterpartID = room.ClientID
+			}
+			counterpart, ok := c.s.data.users[counterpartID]
+			if !ok {
+				continue
+			}
+			item.Counterpart = &entity.PublicUser{ID: counterpart.ID, Email: counterpart.Email, Nickname: counterpart.Nickname}
+		} else {
+			for key := range c.s.data.participants {
+				if key.roomID != room.ID || key.userID == userID {
+					continue
+				}
+				if participant, ok := c.s.data.users[key.userID]; ok {
+					item.Participants = append(item.Participants, entity.PublicUser{ID: participant.ID, Email: participant.Email, Nickname: participant.Nickname})
+				}
+			}
+			sort.Slice(item.Participants, func(i, j int) bool { return item.Participants[i].ID < item.Participants[j].ID })
+		}
+
+		lastReadMessageID := c.s.data.readMarkers[roomUserKey{room.ID, userID}].LastReadMessageID
+		item.UnreadCount, item.LastMessage = c.s.unread(room, userID, lastReadMessageID)
+
+		inbox = append(inbox, item)
+	}
+
+	// ORDER BY lm.created_at DESC NULLS LAST, r.id DESC
+	createdAt := func(item entity.ChatInboxItem) time.Time {
+		if item.LastMessage == nil {
+			return time.Time{}
+		}
+		return c.s.data.chatMessages[item.LastMessage.ID].createdAt
+	}
+	sort.Slice(inbox, func(i, j int) bool {
+		ti, tj := createdAt(inbox[i]), createdAt(inbox[j])
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return inbox[i].ChatRoom.ID > inbox[j].ChatRoom.ID
+	})
+
+	return inbox, nil
+}
+
+// EditChatMessage 보낸 사람이 editWindow 안에 roomID 채팅룸의 메시지를 수정, 수정 전 내용은 chat_message_edit에 남김
+func (c *chatRepo) EditChatMessage(ctx context.Context, roomID, messageID, senderID int64, message string, editWindow time.Duration) (*entity.ChatMessage, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	row, restErr := c.s.modifiableChatMessage(roomID, messageID, senderID, editWindow)
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	editedAt := now()
+	c.s.data.chatMessageEdits = append(c.s.data.chatMessageEdits, entity.ChatMessageEdit{
+		ID:            c.s.data.nextID("chat_message_edit"),
+		ChatMessageID: messageID,
+		Message:       row.Message,
+		EditedAt:      editedAt,
+	})
+
+	row.Message = message
+	row.EditedAt = sql.NullString{String: editedAt, Valid: true}
+	c.s.data.chatMessages[messageID] = row
+
+	return &row.ChatMessage, nil
+}
+
+// DeleteChatMessage 보낸 사람이 editWindow 안에 roomID 채팅룸의 메시지를 삭제 (deleted_at만 표시하고 내용은 남겨둠)
+func (c *chatRepo) DeleteChatMessage(ctx context.Context, roomID, messageID, senderID int64, editWindow time.Duration) (*entity.ChatMessage, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	row, restErr := c.s.modifiableChatMessage(roomID, messageID, senderID, editWindow)
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	row.DeletedAt = sql.NullString{String: now(), Valid: true}
+	c.s.data.chatMessages[messageID] = row
+
+	return &row.ChatMessage, nil
+}
+
+// RemoveChatMessage 모더레이터가 메시지를 삭제 (보낸 사람, 수정 가능 시간을 확인하지 않음)
+func (c *chatRepo) RemoveChatMessage(ctx context.Context, messageID int64) (*entity.ChatMessage, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	row, ok := c.s.data.chatMessages[messageID]
+	if !ok {
+		return nil, errors.NewNotFoundError("chat message doesn't exist")
+	}
+	if !row.DeletedAt.Valid {
+		row.DeletedAt = sql.NullString{String: now(), Valid: true}
+		c.s.data.chatMessages[messageID] = row
+	}
+
+	return &row.ChatMessage, nil
+}
+
+func (c *chatRepo) GetChatMessageEdits(ctx context.Context, messageID int64) ([]entity.ChatMessageEdit, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	edits := make([]entity.ChatMessageEdit, 0)
+	for _, edit := range c.s.data.chatMessageEdits {
+		if edit.ChatMessageID == messageID {
+			edits = append(edits, edit)
+		}
+	}
+
+	return edits, nil
+}
+
+// modifiableChatMessage roomID 채팅룸의 메시지인지, 보낸 사람인지, 삭제되지 않았는지, editWindow가 지나지 않았는지 확인
+func (s *store) modifiableChatMessage(roomID, messageID, senderID int64, editWindow time.Duration) (chatMessageRow, *errors.RestErr) {
+	row, ok := s.data.chatMessages[messageID]
+	if !ok || row.ChatRoomID != roomID {
+		return row, errors.NewNotFoundError("chat message doesn't exist")
+	}
+	if row.SenderID != senderID {
+		return row, errors.NewForbiddenError("only the sender can modify the message")
+	}
+	if row.DeletedAt.Valid {
+		return row, errors.NewBadRequestError("chat message is already deleted")
+	}
+	if row.createdAt.Before(time.Now().Add(-editWindow)) {
+		return row, errors.NewForbiddenError("chat message can't be modified anymore")
+	}
+	return row, nil
+}
+
+func (s *store) findChatRoom(match func(entity.ChatRoom) bool) (entity.ChatRoom, bool) {
+	for _, r := range s.data.chatRooms {
+		if match(r) {
+			return r, true
+		}
+	}
+	return entity.ChatRoom{}, false
+}
+
+func (s *store) findChatMessage(match func(entity.ChatMessage) bool) (entity.ChatMessage, bool) {
+	for _, row := range s.data.chatMessages {
+		if match(row.ChatMessage) {
+			return row.ChatMessage, true
+		}
+	}
+	return entity.ChatMessage{}, false
+}
+
+// isParticipant 1:1이면 client/host, 팀 채팅이면 chat_room_participant에 있는 유저
+func (s *store) isParticipant(room entity.ChatRoom, userID int64) bool {
+	if room.RoomType == entity.ChatRoomTypeDirect && (room.ClientID == userID || room.HostID == userID) {
+		return true
+	}
+	return s.data.participants[roomUserKey{room.ID, userID}]
+}
+
+// userChatRooms 유저가 client, host 또는 참여자인 채팅룸들
+func (s *store) userChatRooms(userID int64) []entity.ChatRoom {
+	var rooms []entity.ChatRoom
+	for _, r := range s.data.chatRooms {
+		if r.ClientID == userID || r.HostID == userID || s.data.participants[roomUserKey{r.ID, userID}] {
+			rooms = append(rooms, r)
+		}
+	}
+	sort.Slice(rooms, func(i, j int) bool { return rooms[i].ID > rooms[j].ID })
+	return rooms
+}
+
+// roomMessages 채팅룸의 메시지들을 id 순서로 반환
+func (s *store) roomMessages(roomID int64) []entity.ChatMessage {
+	var msgs []entity.ChatMessage
+	for _, row := range s.data.chatMessages {
+		if row.ChatRoomID == roomID {
+			msgs = append(msgs, row.ChatMessage)
+		}
+	}
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].ID < msgs[j].ID })
+	return msgs
+}
+
+// unread lastReadMessageID 이후에 다른 사람이 보낸 메시지 개수와 마지막 메시지
+func (s *store) unread(room entity.ChatRoom, userID, lastReadMessageID int64) (int64, *entity.ChatMessage) {
+	var unreadCount int64
+	var lastMessage *entity.ChatMessage
+	for _, m := range s.roomMessages(room.ID) {
+		if m.ID > lastReadMessageID && m.SenderID != userID {
+			unreadCount++
+		}
+		m := m
+		m.ChatRoomName = room.RoomName
+		m.ClientMessageID = sql.NullString{}
+		lastMessage = &m
+	}
+	return unreadCount, lastMessage
+}
+
+func lastMessageID(m *entity.ChatMessage) int64 {
+	if m == nil {
+		return 0
+	}
+	return m.ID
+}
+
+func reverse(msgs []entity.ChatMessage) {
+	for i, j := 0, len(msgs)-1; i < j; i, j = i+1, j-1 {
+		msgs[i], msgs[j] = msgs[j], msgs[i]
+	}
+}
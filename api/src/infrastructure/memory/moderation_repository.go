@@ -0,0 +1,221 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+)
+
+type userBlockRepo struct {
+	s *store
+}
+
+var _ repository.UserBlockRepository = &userBlockRepo{}
+
+// BlockUser 이미 차단한 유저면 아무것도 하지 않음
+func (u *userBlockRepo) BlockUser(ctx context.Context, blockerID, blockedID int64) *errors.RestErr {
+	u.s.mu.Lock()
+	defer u.s.mu.Unlock()
+
+	if u.s.isBlocking(blockerID, blockedID) {
+		return nil
+	}
+	u.s.data.userBlocks = append(u.s.data.userBlocks, entity.UserBlock{
+		BlockerID: blockerID,
+		BlockedID: blockedID,
+		CreatedAt: now(),
+	})
+
+	return nil
+}
+
+func (u *userBlockRepo) UnblockUser(ctx context.Context, blockerID, blockedID int64) *errors.RestErr {
+	u.s.mu.Lock()
+	defer u.s.mu.Unlock()
+
+	for i, b := range u.s.data.userBlocks {
+		if b.BlockerID == blockerID && b.BlockedID == blockedID {
+			u.s.data.userBlocks = append(u.s.data.userBlocks[:i:i], u.s.data.userBlocks[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// GetBlockedUsers 최근에 차단한 유저부터 반환
+func (u *userBlockRepo) GetBlockedUsers(ctx context.Context, blockerID int64) ([]entity.UserBlock, *errors.RestErr) {
+	u.s.mu.Lock()
+	defer u.s.mu.Unlock()
+
+	var blocks []entity.UserBlock
+	for i := len(u.s.data.userBlocks) - 1; i >= 0; i-- {
+		b := u.s.data.userBlocks[i]
+		blocked, ok := u.s.data.users[b.BlockedID]
+		if b.BlockerID != blockerID || !ok {
+			continue
+		}
+		b.Nickname = blocked.Nickname
+		blocks = append(blocks, b)
+	}
+
+	return blocks, nil
+}
+
+func (u *userBlockRepo) IsBlocked(ctx context.Context, userID, otherUserID int64) (bool, *errors.RestErr) {
+	u.s.mu.Lock()
+	defer u.s.mu.Unlock()
+
+	return u.s.isBlocking(userID, otherUserID) || u.s.isBlocking(otherUserID, userID), nil
+}
+
+func (u *userBlockRepo) IsDirectChatRoomBlocked(ctx context.Context, roomName string) (bool, *errors.RestErr) {
+	u.s.mu.Lock()
+	defer u.s.mu.Unlock()
+
+	room, ok := u.s.findChatRoom(func(r entity.ChatRoom) bool {
+		return r.RoomName == roomName && r.RoomType == entity.ChatRoomTypeDirect
+	})
+	if !ok {
+		return false, nil
+	}
+
+	return u.s.isBlocking(room.ClientID, room.HostID) || u.s.isBlocking(room.HostID, room.ClientID), nil
+}
+
+func (s *store) isBlocking(blockerID, blockedID int64) bool {
+	for _, b := range s.data.userBlocks {
+		if b.BlockerID == blockerID && b.BlockedID == blockedID {
+			return true
+		}
+	}
+	return false
+}
+
+type chatReportRepo struct {
+	s *store
+}
+
+var _ repository.ChatReportRepository = &chatReportRepo{}
+
+// SaveReport 같은 유저가 같은 메시지를 다시 신고하면 기존 신고를 반환
+func (c *chatReportRepo) SaveReport(ctx context.Context, report *entity.ChatMessageReport) (*entity.ChatMessageReport, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	// postgres에서는 chat_message foreign key 위반
+	if _, ok := c.s.data.chatMessages[report.ChatMessageID]; !ok {
+		return nil, errors.NewInternalServerError("database insert error chat_message doesn't exist")
+	}
+
+	for _, r := range c.s.data.reports {
+		if r.ChatMessageID == report.ChatMessageID && r.ReporterID == report.ReporterID {
+			return c.s.report(r), nil
+		}
+	}
+
+	newReport := entity.ChatMessageReport{
+		ID:            c.s.data.nextID("chat_message_report"),
+		ChatMessageID: report.ChatMessageID,
+		ReporterID:    report.ReporterID,
+		Reason:        report.Reason,
+		Status:        entity.ReportStatusOpen,
+		CreatedAt:     now(),
+	}
+	c.s.data.reports[newReport.ID] = newReport
+
+	return c.s.report(newReport), nil
+}
+
+func (c *chatReportRepo) GetReport(ctx context.Context, reportID int64) (*entity.ChatMessageReport, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	r, ok := c.s.data.reports[reportID]
+	if !ok {
+		return nil, errors.NewNotFoundError("report doesn't exist")
+	}
+
+	return c.s.report(r), nil
+}
+
+// GetReports 오래된 신고부터 limit개
+func (c *chatReportRepo) GetReports(ctx context.Context, status string, limit int64) ([]entity.ChatMessageReport, *errors.RestErr) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	var reports []entity.ChatMessageReport
+	for _, r := range c.s.data.reports {
+		if r.Status == status {
+			reports = append(reports, *c.s.report(r))
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].ID < reports[j].ID })
+
+	return reports[:clamp(limit, len(reports))], nil
+}
+
+func (c *chatReportRepo) ResolveReports(ctx context.Context, chatMessageID, moderatorID int64, status string) *errors.RestErr {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+
+	reviewedAt := now()
+	for id, r := range c.s.data.reports {
+		if r.ChatMessageID == chatMessageID && r.Status == entity.ReportStatusOpen {
+			r.Status, r.ReviewedBy, r.ReviewedAt = status, moderatorID, reviewedAt
+			c.s.data.reports[id] = r
+		}
+	}
+
+	return nil
+}
+
+// report 신고된 메시지의 내용을 채워서 반환
+func (s *store) report(r entity.ChatMessageReport) *entity.ChatMessageReport {
+	m := s.data.chatMessages[r.ChatMessageID]
+	r.ChatRoomID, r.SenderID, r.Sender, r.Message = m.ChatRoomID, m.SenderID, m.Sender, m.Message
+	return &r
+}
+
+var _ repository.MessageRateLimiter = &MessageRateLimiter{}
+
+// MessageRateLimiter 유저마다 window 동안 limit개의 메시지만 허용 (fixed window)
+type MessageRateLimiter struct {
+	mu     sync.Mutex
+	limit  int64
+	window time.Duration
+	counts map[int64]windowCount
+}
+
+type windowCount struct {
+	windowNumber int64
+	count        int64
+}
+
+func NewMessageRateLimiter(limit int64, window time.Duration) *MessageRateLimiter {
+	return &MessageRateLimiter{
+		limit:  limit,
+		window: window,
+		counts: make(map[int64]windowCount),
+	}
+}
+
+func (ml *MessageRateLimiter) Allow(ctx context.Context, userID int64) (bool, *errors.RestErr) {
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+
+	windowNumber := time.Now().UnixNano() / int64(ml.window)
+	c := ml.counts[userID]
+	if c.windowNumber != windowNumber {
+		c = windowCount{windowNumber: windowNumber}
+	}
+	c.count++
+	ml.counts[userID] = c
+
+	return c.count <= ml.limit, nil
+}
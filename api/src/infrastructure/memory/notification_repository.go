@@ -0,0 +1,309 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+)
+
+// 알림에 남기는 메시지 미리보기 길이
+const notificationPreviewLength = 200
+
+type notificationRepo struct {
+	s *store
+}
+
+var _ repository.NotificationRepository = &notificationRepo{}
+
+func (n *notificationRepo) SaveNotification(ctx context.Context, notification *entity.Notification) (*entity.Notification, *errors.RestErr) {
+	n.s.mu.Lock()
+	defer n.s.mu.Unlock()
+
+	preview := []rune(notification.Message)
+	if len(preview) > notificationPreviewLength {
+		preview = preview[:notificationPreviewLength]
+	}
+
+	newNotification := *notification
+	newNotification.ID = n.s.data.nextID("notification")
+	newNotification.Message = string(preview)
+	newNotification.Read = false
+	newNotification.CreatedAt = now()
+	n.s.data.notifications[newNotification.ID] = notificationRow{Notification: newNotification}
+
+	return &newNotification, nil
+}
+
+// GetNotifications 최근 알림부터 limit개
+func (n *notificationRepo) GetNotifications(ctx context.Context, userID, limit int64) ([]entity.Notification, *errors.RestErr) {
+	n.s.mu.Lock()
+	defer n.s.mu.Unlock()
+
+	var notifications []entity.Notification
+	for _, row := range n.s.sortedNotifications() {
+		if row.UserID == userID {
+			notifications = append(notifications, row.Notification)
+		}
+	}
+	sort.Slice(notifications, func(i, j int) bool { return notifications[i].ID > notifications[j].ID })
+
+	return notifications[:clamp(limit, len(notifications))], nil
+}
+
+// MarkNotificationsRead lastNotificationID까지의 알림을 읽음으로 표시
+func (n *notificationRepo) MarkNotificationsRead(ctx context.Context, userID, lastNotificationID int64) *errors.RestErr {
+	n.s.mu.Lock()
+	defer n.s.mu.Unlock()
+
+	for id, row := range n.s.data.notifications {
+		if row.UserID == userID && id <= lastNotificationID {
+			row.Read = true
+			n.s.data.notifications[id] = row
+		}
+	}
+
+	return nil
+}
+
+// GetPendingDigests 이메일 요약을 받는 유저들의 읽지 않았고 아직 이메일로 보내지 않은 알림
+func (n *notificationRepo) GetPendingDigests(ctx context.Context) ([]entity.NotificationDigest, *errors.RestErr) {
+	n.s.mu.Lock()
+	defer n.s.mu.Unlock()
+
+	var digests []entity.NotificationDigest
+	for _, row := range n.s.sortedNotifications() {
+		u, ok := n.s.data.users[row.UserID]
+		if !ok || row.Read || row.emailed || !n.s.data.preferences[row.UserID].EmailDigest {
+			continue
+		}
+
+		if len(digests) == 0 || digests[len(digests)-1].UserID != row.UserID {
+			digests = append(digests, entity.NotificationDigest{
+				UserID:   row.UserID,
+				Email:    u.Email,
+				Nickname: u.Nickname,
+			})
+		}
+		last := &digests[len(digests)-1]
+		last.Notifications = append(last.Notifications, row.Notification)
+	}
+
+	return digests, nil
+}
+
+func (n *notificationRepo) MarkNotificationsEmailed(ctx context.Context, userID, lastNotificationID int64) *errors.RestErr {
+	n.s.mu.Lock()
+	defer n.s.mu.Unlock()
+
+	for id, row := range n.s.data.notifications {
+		if row.UserID == userID && id <= lastNotificationID {
+			row.emailed = true
+			n.s.data.notifications[id] = row
+		}
+	}
+
+	return nil
+}
+
+// GetPreference 저장된 설정이 없으면 기본 설정
+func (n *notificationRepo) GetPreference(ctx context.Context, userID int64) (*entity.NotificationPreference, *errors.RestErr) {
+	n.s.mu.Lock()
+	defer n.s.mu.Unlock()
+
+	preference, ok := n.s.data.preferences[userID]
+	if !ok {
+		return entity.DefaultNotificationPreference(userID), nil
+	}
+
+	return &preference, nil
+}
+
+func (n *notificationRepo) SavePreference(ctx context.Context, preference *entity.NotificationPreference) *errors.RestErr {
+	n.s.mu.Lock()
+	defer n.s.mu.Unlock()
+
+	n.s.data.preferences[preference.UserID] = *preference
+
+	return nil
+}
+
+// SavePushSubscription 같은 endpoint면 구독 정보를 갱신 (브라우저가 다른 유저로 로그인한 경우 포함)
+func (n *notificationRepo) SavePushSubscription(ctx context.Context, subscription *entity.PushSubscription) (*entity.PushSubscription, *errors.RestErr) {
+	n.s.mu.Lock()
+	defer n.s.mu.Unlock()
+
+	newSubscription := entity.PushSubscription{
+		UserID:   subscription.UserID,
+		Endpoint: subscription.Endpoint,
+		P256dh:   subscription.P256dh,
+		Auth:     subscription.Auth,
+	}
+	for _, s := range n.s.data.pushSubscriptions {
+		if s.Endpoint == subscription.Endpoint {
+			newSubscription.ID, newSubscription.CreatedAt = s.ID, s.CreatedAt
+		}
+	}
+	if newSubscription.ID == 0 {
+		newSubscription.ID = n.s.data.nextID("push_subscription")
+		newSubscription.CreatedAt = now()
+	}
+	n.s.data.pushSubscriptions[newSubscription.ID] = newSubscription
+
+	return &newSubscription, nil
+}
+
+func (n *notificationRepo) GetPushSubscriptions(ctx context.Context, userID int64) ([]entity.PushSubscription, *errors.RestErr) {
+	n.s.mu.Lock()
+	defer n.s.mu.Unlock()
+
+	var subscriptions []entity.PushSubscription
+	for _, s := range n.s.data.pushSubscriptions {
+		if s.UserID == userID {
+			subscriptions = append(subscriptions, s)
+		}
+	}
+	sort.Slice(subscriptions, func(i, j int) bool { return subscriptions[i].ID < subscriptions[j].ID })
+
+	return subscriptions, nil
+}
+
+func (n *notificationRepo) DeletePushSubscription(ctx context.Context, userID int64, endpoint string) *errors.RestErr {
+	n.s.mu.Lock()
+	defer n.s.mu.Unlock()
+
+	for id, s := range n.s.data.pushSubscriptions {
+		if s.UserID == userID && s.Endpoint == endpoint {
+			delete(n.s.data.pushSubscriptions, id)
+		}
+	}
+
+	return nil
+}
+
+// sortedNotifications ORDER BY user_id, id
+func (s *store) sortedNotifications() []notificationRow {
+	rows := make([]notificationRow, 0, len(s.data.notifications))
+	for _, row := range s.data.notifications {
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].UserID != rows[j].UserID {
+			return rows[i].UserID < rows[j].UserID
+		}
+		return rows[i].ID < rows[j].ID
+	})
+	return rows
+}
+
+var _ repository.NotificationRateLimiter = &NotificationLimiter{}
+
+// NotificationLimiter 유저마다 채팅룸의 알림을 window에 한 번만 허용
+type NotificationLimiter struct {
+	mu      sync.Mutex
+	window  time.Duration
+	allowed map[roomUserKey]time.Time // 다음 알림을 허용할 시간
+}
+
+func NewNotificationLimiter(window time.Duration) *NotificationLimiter {
+	return &NotificationLimiter{
+		window:  window,
+		allowed: make(map[roomUserKey]time.Time),
+	}
+}
+
+func (nl *NotificationLimiter) Allow(ctx context.Context, userID, chatRoomID int64) (bool, *errors.RestErr) {
+	nl.mu.Lock()
+	defer nl.mu.Unlock()
+
+	key := roomUserKey{roomID: chatRoomID, userID: userID}
+	now := time.Now()
+	if now.Before(nl.allowed[key]) {
+		return false, nil
+	}
+	nl.allowed[key] = now.Add(nl.window)
+
+	return true, nil
+}
+
+var _ repository.Mailer = &Mailer{}
+
+// Mail Mailer로 보낸 이메일
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer 이메일을 보내지 않고 기록만 함
+type Mailer struct {
+	mu   sync.Mutex
+	sent []Mail
+}
+
+func (m *Mailer) Send(to, subject, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sent = append(m.sent, Mail{To: to, Subject: subject, Body: body})
+
+	return nil
+}
+
+// Sent 지금까지 보낸 이메일들
+func (m *Mailer) Sent() []Mail {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Mail(nil), m.sent...)
+}
+
+var _ repository.WebPusher = &WebPusher{}
+
+// Push WebPusher로 보낸 web push
+type Push struct {
+	Subscription entity.PushSubscription
+	Payload      []byte
+}
+
+// WebPusher web push를 보내지 않고 기록만 함, Expire한 endpoint는 ErrPushSubscriptionGone을 반환
+type WebPusher struct {
+	mu     sync.Mutex
+	pushed []Push
+	gone   map[string]bool
+}
+
+func (w *WebPusher) Push(subscription entity.PushSubscription, payload []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.gone[subscription.Endpoint] {
+		return repository.ErrPushSubscriptionGone
+	}
+	w.pushed = append(w.pushed, Push{Subscription: subscription, Payload: payload})
+
+	return nil
+}
+
+// Expire 구독이 만료된 endpoint로 표시
+func (w *WebPusher) Expire(endpoint string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.gone == nil {
+		w.gone = make(map[string]bool)
+	}
+	w.gone[endpoint] = true
+}
+
+// Pushed 지금까지 보낸 web push들
+func (w *WebPusher) Pushed() []Push {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return append([]Push(nil), w.pushed...)
+}
@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/domain/repository/repositorytest"
+)
+
+func TestRepositories(t *testing.T) {
+	repos := NewRepositories()
+
+	repositorytest.Run(t, repositorytest.Repositories{
+		Tx:                 repos.Tx,
+		User:               repos.User,
+		StudyPost:          repos.StudyPost,
+		TechStack:          repos.TechStack,
+		StudyPostTechStack: repos.StudyPostTechStack,
+		StudyPostMember:    repos.StudyPostMember,
+		Chat:               repos.Chat,
+		ChatAttachment:     repos.ChatAttachment,
+		Notification:       repos.Notification,
+		UserBlock:          repos.UserBlock,
+		ChatReport:         repos.ChatReport,
+		Auth:               repos.Auth,
+		Presence:           repos.Presence,
+		BlobStore:          NewBlobStore(),
+		NewMessageRateLimiter: func(limit int64, window time.Duration) repository.MessageRateLimiter {
+			return NewMessageRateLimiter(limit, window)
+		},
+		NewNotificationLimiter: func(window time.Duration) repository.NotificationRateLimiter {
+			return NewNotificationLimiter(window)
+		},
+	})
+}
@@ -0,0 +1,226 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/helpers"
+)
+
+// tables postgres의 테이블들을 메모리에 둠 (foreign key는 확인하지 않음)
+type tables struct {
+	users               map[int64]entity.User
+	studyPosts          map[int64]studyPostRow
+	techStacks          map[int64]entity.TechStack
+	studyPostTechStacks []entity.StudyPostTechStack
+	members             []entity.StudyPostMember // 수락된 순서
+	chatRooms           map[int64]entity.ChatRoom
+	participants        map[roomUserKey]bool
+	chatMessages        map[int64]chatMessageRow
+	chatMessageEdits    []entity.ChatMessageEdit
+	readMarkers         map[roomUserKey]entity.ChatReadMarker
+	attachments         map[int64]entity.ChatAttachment
+	notifications       map[int64]notificationRow
+	preferences         map[int64]entity.NotificationPreference
+	pushSubscriptions   map[int64]entity.PushSubscription
+	userBlocks          []entity.UserBlock // 차단한 순서
+	reports             map[int64]entity.ChatMessageReport
+	lastID              map[string]int64 // 테이블마다 serial
+}
+
+type roomUserKey struct {
+	roomID int64
+	userID int64
+}
+
+type studyPostRow struct {
+	entity.StudyPost
+	createdAt time.Time
+}
+
+type chatMessageRow struct {
+	entity.ChatMessage
+	createdAt time.Time
+}
+
+type notificationRow struct {
+	entity.Notification
+	emailed bool
+}
+
+func newTables() *tables {
+	return &tables{
+		users:             make(map[int64]entity.User),
+		studyPosts:        make(map[int64]studyPostRow),
+		techStacks:        make(map[int64]entity.TechStack),
+		chatRooms:         make(map[int64]entity.ChatRoom),
+		participants:      make(map[roomUserKey]bool),
+		chatMessages:      make(map[int64]chatMessageRow),
+		readMarkers:       make(map[roomUserKey]entity.ChatReadMarker),
+		attachments:       make(map[int64]entity.ChatAttachment),
+		notifications:     make(map[int64]notificationRow),
+		preferences:       make(map[int64]entity.NotificationPreference),
+		pushSubscriptions: make(map[int64]entity.PushSubscription),
+		reports:           make(map[int64]entity.ChatMessageReport),
+		lastID:            make(map[string]int64),
+	}
+}
+
+// clone transaction을 rollback할 때 되돌릴 snapshot
+func (t *tables) clone() *tables {
+	c := newTables()
+	for k, v := range t.users {
+		c.users[k] = v
+	}
+	for k, v := range t.studyPosts {
+		v.TechStack = append([]string(nil), v.TechStack...)
+		c.studyPosts[k] = v
+	}
+	for k, v := range t.techStacks {
+		c.techStacks[k] = v
+	}
+	c.studyPostTechStacks = append(c.studyPostTechStacks, t.studyPostTechStacks...)
+	c.members = append(c.members, t.members...)
+	for k, v := range t.chatRooms {
+		c.chatRooms[k] = v
+	}
+	for k, v := range t.participants {
+		c.participants[k] = v
+	}
+	for k, v := range t.chatMessages {
+		c.chatMessages[k] = v
+	}
+	c.chatMessageEdits = append(c.chatMessageEdits, t.chatMessageEdits...)
+	for k, v := range t.readMarkers {
+		c.readMarkers[k] = v
+	}
+	for k, v := range t.attachments {
+		c.attachments[k] = v
+	}
+	for k, v := range t.notifications {
+		c.notifications[k] = v
+	}
+	for k, v := range t.preferences {
+		c.preferences[k] = v
+	}
+	for k, v := range t.pushSubscriptions {
+		c.pushSubscriptions[k] = v
+	}
+	c.userBlocks = append(c.userBlocks, t.userBlocks...)
+	for k, v := range t.reports {
+		c.reports[k] = v
+	}
+	for k, v := range t.lastID {
+		c.lastID[k] = v
+	}
+	return c
+}
+
+func (t *tables) nextID(table string) int64 {
+	t.lastID[table]++
+	return t.lastID[table]
+}
+
+// store repository들이 같이 쓰는 저장소, 모든 접근은 mu로 보호
+type store struct {
+	mu   sync.Mutex
+	data *tables
+	txMu sync.Mutex // WithinTx는 한 번에 하나씩 실행
+}
+
+func newStore() *store {
+	s := &store{data: newTables()}
+
+	// 0001 migration에서 넣는 기술 스택
+	for _, techName := range []string{"go", "react"} {
+		id := s.data.nextID("tech_stack")
+		s.data.techStacks[id] = entity.TechStack{ID: id, TechName: techName}
+	}
+
+	return s
+}
+
+func now() string {
+	return helpers.GetDateString(time.Now())
+}
+
+// Repositories persistence.Repositories, persistence.RedisService와 같은 repository들을 메모리에 구현 (테스트, 로컬 실행용)
+type Repositories struct {
+	Tx                 repository.TxManager
+	StudyPost          repository.StudyPostRepository
+	User               repository.UserRepository
+	TechStack          repository.TechStackRepository
+	StudyPostTechStack repository.StudyPostTechStackRepository
+	StudyPostMember    repository.StudyPostMemberRepository
+	Chat               repository.ChatRepository
+	ChatAttachment     repository.ChatAttachmentRepository
+	Notification       repository.NotificationRepository
+	UserBlock          repository.UserBlockRepository
+	ChatReport         repository.ChatReportRepository
+	Auth               repository.AuthRepository
+	Presence           repository.PresenceRepository
+}
+
+func NewRepositories() *Repositories {
+	s := newStore()
+
+	return &Repositories{
+		Tx:                 &TxManager{s},
+		StudyPost:          &studyPostRepo{s},
+		User:               &userRepo{s},
+		TechStack:          &techStackRepo{s},
+		StudyPostTechStack: &studyPostTechStackRepo{s},
+		StudyPostMember:    &studyPostMemberRepo{s},
+		Chat:               &chatRepo{s},
+		ChatAttachment:     &chatAttachmentRepo{s},
+		Notification:       &notificationRepo{s},
+		UserBlock:          &userBlockRepo{s},
+		ChatReport:         &chatReportRepo{s},
+		Auth:               NewAuthRepository(),
+		Presence:           NewPresenceRepository(),
+	}
+}
+
+var _ repository.TxManager = &TxManager{}
+
+// TxManager fn이 error를 반환하면 시작할 때의 snapshot으로 되돌림
+// 테스트용이라 transaction 중에 다른 goroutine이 쓴 값도 같이 되돌아감
+type TxManager struct {
+	s *store
+}
+
+type txKey struct{}
+
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) *errors.RestErr) (restErr *errors.RestErr) {
+	if ctx.Value(txKey{}) != nil {
+		return fn(ctx)
+	}
+
+	m.s.txMu.Lock()
+	defer m.s.txMu.Unlock()
+
+	m.s.mu.Lock()
+	snapshot := m.s.data.clone()
+	m.s.mu.Unlock()
+
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		m.s.mu.Lock()
+		m.s.data = snapshot
+		m.s.mu.Unlock()
+	}()
+
+	if restErr = fn(context.WithValue(ctx, txKey{}, true)); restErr != nil {
+		return restErr
+	}
+	committed = true
+
+	return nil
+}
@@ -0,0 +1,363 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+)
+
+type studyPostRepo struct {
+	s *store
+}
+
+var _ repository.StudyPostRepository = &studyPostRepo{}
+
+func (r *studyPostRepo) SavePost(ctx context.Context, studyPost *entity.StudyPost) (*entity.StudyPost, *errors.RestErr) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	studyPost.ID = r.s.data.nextID("study_post")
+
+	row := studyPostRow{StudyPost: *studyPost, createdAt: time.Now()}
+	row.TechStack = append([]string(nil), studyPost.TechStack...)
+	row.CreatedAt = now()
+	row.UpdatedAt = row.CreatedAt
+	r.s.data.studyPosts[studyPost.ID] = row
+
+	return studyPost, nil
+}
+
+func (r *studyPostRepo) GetPost(ctx context.Context, id int64) (*entity.StudyPost, *errors.RestErr) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	row, ok := r.s.data.studyPosts[id]
+	if !ok {
+		return nil, errors.NewBadRequestError("row doesn't exist " + sql.ErrNoRows.Error())
+	}
+	studyPost := row.post()
+
+	return &studyPost, nil
+}
+
+func (r *studyPostRepo) GetPostsInLatestOrder(ctx context.Context, limit, offset int64) (entity.StudyPosts, *errors.RestErr) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.s.latestPosts(func(entity.StudyPost) bool { return true }, limit, offset), nil
+}
+
+// GetPostsByUserID 특정 user가 쓴 게시글들을 최신순으로 return
+func (r *studyPostRepo) GetPostsByUserID(ctx context.Context, userID, limit, offset int64) (entity.StudyPosts, *errors.RestErr) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.s.latestPosts(func(p entity.StudyPost) bool { return p.UserID == userID }, limit, offset), nil
+}
+
+func (r *studyPostRepo) UpdatePost(ctx context.Context, studyPost *entity.StudyPost) (*entity.StudyPost, *errors.RestErr) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	row, ok := r.s.data.studyPosts[studyPost.ID]
+	if !ok {
+		return nil, errors.NewInternalServerError("database update error " + sql.ErrNoRows.Error())
+	}
+	row.Title, row.Topic, row.Content = studyPost.Title, studyPost.Topic, studyPost.Content
+	row.NumOfMembers, row.IsMentor, row.Price = studyPost.NumOfMembers, studyPost.IsMentor, studyPost.Price
+	row.StartDate, row.EndDate, row.IsOnline = studyPost.StartDate, studyPost.EndDate, studyPost.IsOnline
+	row.TechStack = append([]string(nil), studyPost.TechStack...)
+	row.UpdatedAt = now()
+	r.s.data.studyPosts[studyPost.ID] = row
+
+	*studyPost = row.post()
+
+	return studyPost, nil
+}
+
+func (r *studyPostRepo) DeletePost(ctx context.Context, studyPostID int64) *errors.RestErr {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.data.studyPosts[studyPostID]; !ok {
+		return errors.NewBadRequestError("no rows to be deleted")
+	}
+	for _, room := range r.s.data.chatRooms {
+		if room.StudyPostID == studyPostID {
+			return errors.NewInternalServerError("database error study_post is still referenced from chat_room")
+		}
+	}
+
+	delete(r.s.data.studyPosts, studyPostID)
+
+	// ON DELETE CASCADE
+	techStacks := r.s.data.studyPostTechStacks[:0]
+	for _, t := range r.s.data.studyPostTechStacks {
+		if t.StudyPostID != studyPostID {
+			techStacks = append(techStacks, t)
+		}
+	}
+	r.s.data.studyPostTechStacks = techStacks
+
+	members := r.s.data.members[:0]
+	for _, m := range r.s.data.members {
+		if m.StudyPostID != studyPostID {
+			members = append(members, m)
+		}
+	}
+	r.s.data.members = members
+
+	return nil
+}
+
+func (row studyPostRow) post() entity.StudyPost {
+	studyPost := row.StudyPost
+	studyPost.TechStack = append([]string(nil), row.TechStack...)
+	return studyPost
+}
+
+// latestPosts ORDER BY created_at DESC LIMIT OFFSET, mu를 잡은 상태에서 호출
+func (s *store) latestPosts(match func(entity.StudyPost) bool, limit, offset int64) entity.StudyPosts {
+	var rows []studyPostRow
+	for _, row := range s.data.studyPosts {
+		if match(row.StudyPost) {
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].createdAt.Equal(rows[j].createdAt) {
+			return rows[i].createdAt.After(rows[j].createdAt)
+		}
+		return rows[i].ID > rows[j].ID
+	})
+
+	var studyPosts entity.StudyPosts
+	for _, row := range rows[clamp(offset, len(rows)):clamp(offset+limit, len(rows))] {
+		studyPosts = append(studyPosts, row.post())
+	}
+
+	return studyPosts
+}
+
+type techStackRepo struct {
+	s *store
+}
+
+var _ repository.TechStackRepository = &techStackRepo{}
+
+func (r *techStackRepo) SaveTechStack(ctx context.Context, techName string) *errors.RestErr {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.techStackByName(techName); ok {
+		return errors.NewInternalServerError("duplicate key value violates unique constraint on tech_name")
+	}
+
+	id := r.s.data.nextID("tech_stack")
+	r.s.data.techStacks[id] = entity.TechStack{ID: id, TechName: techName}
+
+	return nil
+}
+
+func (r *techStackRepo) GetTechStack(ctx context.Context, id int64) (*entity.TechStack, *errors.RestErr) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	t, ok := r.s.data.techStacks[id]
+	if !ok {
+		return nil, errors.NewBadRequestError(sql.ErrNoRows.Error())
+	}
+
+	return &entity.TechStack{TechName: t.TechName}, nil
+}
+
+func (r *techStackRepo) GetAllTechStack(ctx context.Context) (entity.TechStacks, *errors.RestErr) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.s.techStacks(func(entity.TechStack) bool { return true }), nil
+}
+
+func (r *techStackRepo) GetAllTechStackByStudyPostID(ctx context.Context, studyPostID int64) (entity.TechStacks, *errors.RestErr) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ids := make(map[int64]bool)
+	for _, t := range r.s.data.studyPostTechStacks {
+		if t.StudyPostID == studyPostID {
+			ids[t.TechStackID] = true
+		}
+	}
+
+	return r.s.techStacks(func(t entity.TechStack) bool { return ids[t.ID] }), nil
+}
+
+func (r *techStackRepo) DeleteTechStack(ctx context.Context, techName string) *errors.RestErr {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	t, ok := r.s.techStackByName(techName)
+	if !ok {
+		return errors.NewBadRequestError("no rows to be deleted")
+	}
+	for _, st := range r.s.data.studyPostTechStacks {
+		if st.TechStackID == t.ID {
+			return errors.NewInternalServerError("execute error tech_stack is still referenced from study_post_tech_stack")
+		}
+	}
+
+	delete(r.s.data.techStacks, t.ID)
+
+	return nil
+}
+
+func (r *techStackRepo) CheckTechStack(ctx context.Context, techStack []string) *errors.RestErr {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	names := make(map[string]bool)
+	for _, techName := range techStack {
+		names[techName] = true
+	}
+
+	// 요청한 이름 수보다 저장된 기술이 적다면 tech_stack에 저장되어있지 않은 tech를 요청한 것
+	if n := len(r.s.techStacks(func(t entity.TechStack) bool { return names[t.TechName] })); n < len(techStack) {
+		return errors.NewBadRequestError("some tech name is not stored in the table")
+	}
+
+	return nil
+}
+
+// techStacks id 순서로 반환, mu를 잡은 상태에서 호출
+func (s *store) techStacks(match func(entity.TechStack) bool) entity.TechStacks {
+	var techStacks entity.TechStacks
+	for _, t := range s.data.techStacks {
+		if match(t) {
+			techStacks = append(techStacks, t)
+		}
+	}
+	sort.Slice(techStacks, func(i, j int) bool { return techStacks[i].ID < techStacks[j].ID })
+
+	// postgres 구현처럼 tech_name만 채움
+	for i := range techStacks {
+		techStacks[i].ID = 0
+	}
+
+	return techStacks
+}
+
+func (s *store) techStackByName(techName string) (entity.TechStack, bool) {
+	for _, t := range s.data.techStacks {
+		if t.TechName == techName {
+			return t, true
+		}
+	}
+	return entity.TechStack{}, false
+}
+
+type studyPostTechStackRepo struct {
+	s *store
+}
+
+var _ repository.StudyPostTechStackRepository = &studyPostTechStackRepo{}
+
+// SaveStudyPostTechStack tech_stack에 있는 이름만 저장함
+func (r *studyPostTechStackRepo) SaveStudyPostTechStack(ctx context.Context, studyPostID int64, techStack []string) *errors.RestErr {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.s.saveStudyPostTechStack(studyPostID, techStack)
+}
+
+func (r *studyPostTechStackRepo) UpdateStudyPostTechStack(ctx context.Context, studyPostID int64, techStack []string) *errors.RestErr {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	techStacks := r.s.data.studyPostTechStacks[:0]
+	for _, t := range r.s.data.studyPostTechStacks {
+		if t.StudyPostID != studyPostID {
+			techStacks = append(techStacks, t)
+		}
+	}
+	r.s.data.studyPostTechStacks = techStacks
+
+	return r.s.saveStudyPostTechStack(studyPostID, techStack)
+}
+
+func (s *store) saveStudyPostTechStack(studyPostID int64, techStack []string) *errors.RestErr {
+	// postgres에서는 IN ()이 syntax error
+	if len(techStack) == 0 {
+		return errors.NewInternalServerError("execute error empty tech stack")
+	}
+
+	for _, techName := range techStack {
+		if t, ok := s.techStackByName(techName); ok {
+			s.data.studyPostTechStacks = append(s.data.studyPostTechStacks, entity.StudyPostTechStack{StudyPostID: studyPostID, TechStackID: t.ID})
+		}
+	}
+
+	return nil
+}
+
+type studyPostMemberRepo struct {
+	s *store
+}
+
+var _ repository.StudyPostMemberRepository = &studyPostMemberRepo{}
+
+func (r *studyPostMemberRepo) SaveMember(ctx context.Context, member *entity.StudyPostMember) *errors.RestErr {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, m := range r.s.data.members {
+		if m.StudyPostID == member.StudyPostID && m.UserID == member.UserID {
+			return errors.NewBadRequestError("user is already a member of the study post")
+		}
+	}
+
+	member.JoinedAt = now()
+	r.s.data.members = append(r.s.data.members, entity.StudyPostMember{
+		StudyPostID: member.StudyPostID,
+		UserID:      member.UserID,
+		JoinedAt:    member.JoinedAt,
+	})
+
+	return nil
+}
+
+func (r *studyPostMemberRepo) DeleteMember(ctx context.Context, studyPostID, userID int64) *errors.RestErr {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for i, m := range r.s.data.members {
+		if m.StudyPostID == studyPostID && m.UserID == userID {
+			r.s.data.members = append(r.s.data.members[:i:i], r.s.data.members[i+1:]...)
+			return nil
+		}
+	}
+
+	return errors.NewBadRequestError("no rows to be deleted")
+}
+
+// GetMembers 게시글의 팀원들을 수락된 순서대로 반환
+func (r *studyPostMemberRepo) GetMembers(ctx context.Context, studyPostID int64) (entity.StudyPostMembers, *errors.RestErr) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	members := make(entity.StudyPostMembers, 0)
+	for _, m := range r.s.data.members {
+		u, ok := r.s.data.users[m.UserID]
+		if m.StudyPostID != studyPostID || !ok {
+			continue
+		}
+		m.Nickname = u.Nickname
+		members = append(members, m)
+	}
+
+	return members, nil
+}
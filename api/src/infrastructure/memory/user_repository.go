@@ -0,0 +1,162 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/encryption"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type userRepo struct {
+	s *store
+}
+
+var _ repository.UserRepository = &userRepo{}
+
+func (r *userRepo) Save(ctx context.Context, user *entity.User) *errors.RestErr {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, u := range r.s.data.users {
+		if u.Email == user.Email {
+			return errors.NewBadRequestError("email is duplicated, already taken")
+		}
+	}
+
+	user.ID = r.s.data.nextID("users")
+	saved := *user
+	if saved.CreatedAt == "" {
+		saved.CreatedAt = now()
+	}
+	r.s.data.users[user.ID] = saved
+
+	return nil
+}
+
+func (r *userRepo) GetUserByID(ctx context.Context, userID int64) (*entity.User, *errors.RestErr) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.data.users[userID]
+	if !ok {
+		return nil, errors.NewInternalServerError("database error")
+	}
+	u.Password = ""
+
+	return &u, nil
+}
+
+func (r *userRepo) Get(ctx context.Context, user *entity.User) *errors.RestErr {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.data.users[user.ID]
+	if !ok {
+		return errors.NewInternalServerError("database error")
+	}
+	user.Email, user.Name, user.Nickname, user.CreatedAt, user.UpdatedAt = u.Email, u.Name, u.Nickname, u.CreatedAt, u.UpdatedAt
+
+	return nil
+}
+
+func (r *userRepo) GetAll(ctx context.Context, limit, offset int64) (entity.Users, *errors.RestErr) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	users := make(entity.Users, 0)
+	for _, u := range r.s.data.users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+
+	return users[clamp(offset, len(users)):clamp(offset+limit, len(users))], nil
+}
+
+func (r *userRepo) Update(ctx context.Context, user *entity.User) *errors.RestErr {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.data.users[user.ID]
+	if !ok {
+		return nil
+	}
+	u.Password, u.Name, u.Nickname, u.UpdatedAt = user.Password, user.Name, user.Nickname, user.UpdatedAt
+	r.s.data.users[user.ID] = u
+
+	return nil
+}
+
+func (r *userRepo) Delete(ctx context.Context, userID int64) *errors.RestErr {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.data.users, userID)
+
+	return nil
+}
+
+func (r *userRepo) FindByEmailAndPassword(ctx context.Context, lu *entity.User) (*entity.User, *errors.RestErr) {
+	r.s.mu.Lock()
+	user, ok := r.s.findUser(func(u entity.User) bool { return u.Email == lu.Email })
+	r.s.mu.Unlock()
+	if !ok {
+		return nil, errors.NewNotFoundError("wrong, email does not matched")
+	}
+
+	if err := encryption.VerifyPassword(user.Password, lu.Password); err != nil {
+		if err == bcrypt.ErrMismatchedHashAndPassword {
+			return nil, errors.NewNotFoundError("wrong, password does not matched")
+		}
+		return nil, errors.NewInternalServerError("hashing password error")
+	}
+
+	return &user, nil
+}
+
+func (r *userRepo) FindByEmail(ctx context.Context, email string) *errors.RestErr {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.findUser(func(u entity.User) bool { return u.Email == email }); !ok {
+		return errors.NewNotFoundError("email doesn't exits " + sql.ErrNoRows.Error())
+	}
+
+	return nil
+}
+
+func (r *userRepo) FindByNickname(ctx context.Context, nickname string) *errors.RestErr {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.findUser(func(u entity.User) bool { return u.Nickname == nickname }); !ok {
+		return errors.NewNotFoundError("nickname doesn't exits " + sql.ErrNoRows.Error())
+	}
+
+	return nil
+}
+
+// findUser mu를 잡은 상태에서 호출
+func (s *store) findUser(match func(entity.User) bool) (entity.User, bool) {
+	for _, u := range s.data.users {
+		if match(u) {
+			return u, true
+		}
+	}
+	return entity.User{}, false
+}
+
+// clamp LIMIT, OFFSET을 slice 범위 안으로 맞춤
+func clamp(n int64, length int) int {
+	if n < 0 {
+		return 0
+	}
+	if n > int64(length) {
+		return length
+	}
+	return int(n)
+}
@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "gowave"
+
+// http
+var (
+	// HTTPRequests route는 chi route pattern (ex. /users/{user_id}), 매칭되지 않은 요청은 "unmatched"
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of HTTP requests by method, route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method and route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+)
+
+// redis
+var (
+	RedisCommandDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "redis",
+		Name:      "command_duration_seconds",
+		Help:      "Redis command latency by command name (pipelines as \"pipeline\").",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 5},
+	}, []string{"command"})
+
+	// RedisCommandErrors redis.Nil(값 없음)은 에러로 세지 않음
+	RedisCommandErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "redis",
+		Name:      "command_errors_total",
+		Help:      "Number of failed Redis commands by command name.",
+	}, []string{"command"})
+)
+
+// chat
+var (
+	ChatConnections = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "chat",
+		Name:      "connections",
+		Help:      "Number of websocket and SSE chat connections on this instance.",
+	})
+
+	ChatRooms = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "chat",
+		Name:      "active_rooms",
+		Help:      "Number of chat rooms running on this instance.",
+	})
+
+	// ChatMessages 이 인스턴스에서 처리해서 bus로 publish한 이벤트 (rate()로 초당 메시지 수)
+	ChatMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "chat",
+		Name:      "messages_published_total",
+		Help:      "Number of chat events published by this instance by message type.",
+	}, []string{"type"})
+
+	ChatSendQueueDrops = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "chat",
+		Name:      "send_queue_drops_total",
+		Help:      "Number of messages not delivered because a connection's send queue was full, by slow consumer policy.",
+	}, []string{"policy"})
+)
+
+// RegisterDB sql.DB connection pool 통계를 수집
+func RegisterDB(db *sql.DB, dbName string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// Handler /metrics
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
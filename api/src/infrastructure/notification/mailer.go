@@ -0,0 +1,60 @@
+package notification
+
+import (
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/logger"
+)
+
+var _ repository.Mailer = &SMTPMailer{}
+var _ repository.Mailer = &LogMailer{}
+
+// SMTPMailer SMTP 서버로 text/plain 메일을 보냄
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPMailer{
+		addr: host + ":" + port,
+		auth: auth,
+		from: from,
+	}
+}
+
+func (m *SMTPMailer) Send(to, subject, body string) error {
+	if strings.ContainsAny(to, "\r\n") {
+		return fmt.Errorf("invalid recipient %q", to)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + m.from,
+		"To: " + to,
+		"Subject: " + mime.BEncoding.Encode("utf-8", subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"",
+		body,
+	}, "\r\n")
+
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{to}, []byte(msg))
+}
+
+// LogMailer SMTP 설정이 없을 때(개발 환경) 메일 대신 로그를 남김
+type LogMailer struct{}
+
+func (m *LogMailer) Send(to, subject, body string) error {
+	logger.Info("mail", logger.F("to", to), logger.F("subject", subject), logger.F("body", body))
+	return nil
+}
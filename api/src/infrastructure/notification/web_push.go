@@ -0,0 +1,227 @@
+package notification
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/logger"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	pushTTL          = 24 * time.Hour
+	vapidTokenExpiry = 12 * time.Hour
+	pushRecordSize   = 4096
+	pushTimeout      = 10 * time.Second
+)
+
+var _ repository.WebPusher = &VAPIDPusher{}
+var _ repository.WebPusher = &LogWebPusher{}
+
+var b64 = base64.RawURLEncoding
+
+// VAPIDPusher VAPID(RFC 8292)로 인증하고 payload를 aes128gcm(RFC 8291)로 암호화해서 push 서비스에 보냄
+type VAPIDPusher struct {
+	privateKey *ecdsa.PrivateKey
+	publicKey  string // base64url, 브라우저가 구독할 때 applicationServerKey로 씀
+	subject    string // mailto: 또는 https: 연락처
+	client     *http.Client
+}
+
+// NewVAPIDPusher privateKey는 P-256 개인키(32 bytes)를 base64url로 인코딩한 값
+func NewVAPIDPusher(privateKey, subject string) (*VAPIDPusher, error) {
+	d, err := b64.DecodeString(privateKey)
+	if err != nil || len(d) != 32 {
+		return nil, fmt.Errorf("invalid vapid private key")
+	}
+
+	curve := elliptic.P256()
+	key := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(d)}
+	key.PublicKey.Curve = curve
+	key.PublicKey.X, key.PublicKey.Y = curve.ScalarBaseMult(d)
+
+	return &VAPIDPusher{
+		privateKey: key,
+		publicKey:  b64.EncodeToString(elliptic.Marshal(curve, key.PublicKey.X, key.PublicKey.Y)),
+		subject:    subject,
+		client:     &http.Client{Timeout: pushTimeout},
+	}, nil
+}
+
+func (p *VAPIDPusher) PublicKey() string {
+	return p.publicKey
+}
+
+func (p *VAPIDPusher) Push(subscription entity.PushSubscription, payload []byte) error {
+	body, err := encryptPushPayload(subscription, payload)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := url.Parse(subscription.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	token, err := p.vapidToken(endpoint.Scheme + "://" + endpoint.Host)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, subscription.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", fmt.Sprint(int(pushTTL.Seconds())))
+	req.Header.Set("Authorization", "vapid t="+token+", k="+p.publicKey)
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+
+	switch {
+	case res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusGone:
+		return repository.ErrPushSubscriptionGone
+	case res.StatusCode >= 300:
+		return fmt.Errorf("push service responded %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+// vapidToken push 서비스(audience)에 보내는 ES256 JWT
+func (p *VAPIDPusher) vapidToken(audience string) (string, error) {
+	header := b64.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`))
+
+	claims, err := json.Marshal(map[string]interface{}{
+		"aud": audience,
+		"exp": time.Now().Add(vapidTokenExpiry).Unix(),
+		"sub": p.subject,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := header + "." + b64.EncodeToString(claims)
+	hash := sha256.Sum256([]byte(unsigned))
+
+	r, s, err := ecdsa.Sign(rand.Reader, p.privateKey, hash[:])
+	if err != nil {
+		return "", err
+	}
+
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	return unsigned + "." + b64.EncodeToString(signature), nil
+}
+
+// encryptPushPayload RFC 8291: 구독의 공개키(p256dh)와 auth secret으로 payload를 암호화 (record 하나)
+func encryptPushPayload(subscription entity.PushSubscription, payload []byte) ([]byte, error) {
+	curve := elliptic.P256()
+
+	uaPublic, err := b64.DecodeString(subscription.P256dh)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh: %v", err)
+	}
+	authSecret, err := b64.DecodeString(subscription.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth: %v", err)
+	}
+
+	uaX, uaY := elliptic.Unmarshal(curve, uaPublic)
+	if uaX == nil {
+		return nil, fmt.Errorf("invalid p256dh point")
+	}
+
+	asPrivate, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := elliptic.Marshal(curve, asPrivate.X, asPrivate.Y)
+
+	sharedX, _ := curve.ScalarMult(uaX, uaY, asPrivate.D.Bytes())
+	ecdhSecret := make([]byte, 32)
+	sharedX.FillBytes(ecdhSecret)
+
+	keyInfo := append(append([]byte("WebPush: info\x00"), uaPublic...), asPublic...)
+	ikm, err := hkdfRead(ecdhSecret, authSecret, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	cek, err := hkdfRead(ikm, salt, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdfRead(ikm, salt, []byte("Content-Encoding: nonce\x00"), 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(payload)+1+gcm.Overhead() > pushRecordSize {
+		return nil, fmt.Errorf("push payload is too large")
+	}
+	plaintext := append(append([]byte{}, payload...), 0x02) // 마지막 record 구분자
+
+	header := make([]byte, 0, 16+4+1+len(asPublic))
+	header = append(header, salt...)
+	header = append(header, make([]byte, 4)...)
+	binary.BigEndian.PutUint32(header[16:20], pushRecordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+func hkdfRead(secret, salt, info []byte, length int) ([]byte, error) {
+	out := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, info), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LogWebPusher VAPID 키가 없을 때(개발 환경) push 대신 로그를 남김
+type LogWebPusher struct{}
+
+func (p *LogWebPusher) Push(subscription entity.PushSubscription, payload []byte) error {
+	logger.Info("web push", logger.F("endpoint", subscription.Endpoint), logger.F("payload", string(payload)))
+	return nil
+}
@@ -2,7 +2,6 @@ package persistence
 
 import (
 	"context"
-	"log"
 	"strconv"
 	"strings"
 	"time"
@@ -10,11 +9,11 @@ import (
 	"github.com/code-wave/go-wave/domain/entity"
 	"github.com/code-wave/go-wave/domain/repository"
 	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/logger"
 	"github.com/go-redis/redis/v8"
 )
 
 var _ repository.AuthRepository = &AuthRepo{}
-var ctx = context.Background()
 
 type AuthRepo struct {
 	rClient *redis.Client
@@ -26,30 +25,39 @@ func NewAuthRepository(rClient *redis.Client) *AuthRepo {
 	}
 }
 
-func (ar *AuthRepo) Create(rt *entity.RefreshToken) *errors.RestErr {
+func (ar *AuthRepo) Create(ctx context.Context, rt *entity.RefreshToken) (restErr *errors.RestErr) {
+	ctx, span := startRedisSpan(ctx, "AuthRepo.Create")
+	defer endSpan(span, &restErr)
+
 	expUTC := time.Unix(rt.ExpiresAt, 0)
 
 	//save redis[rt.Uuid] = (userID-rt)
 	if err := ar.rClient.Set(ctx, rt.Uuid, strconv.FormatInt(rt.UserID, 10)+"-"+rt.RefreshToken, time.Until(expUTC)).Err(); err != nil {
-		log.Println("error when save refresh token in redis")
+		logger.Error("error when save refresh token in redis", logger.Err(err), logger.F("user_id", rt.UserID))
 		redisErr := errors.NewInternalServerError("redis error")
 		return redisErr
 	}
 	return nil
 }
 
-func (ar *AuthRepo) Delete(uuid string) *errors.RestErr {
+func (ar *AuthRepo) Delete(ctx context.Context, uuid string) (restErr *errors.RestErr) {
+	ctx, span := startRedisSpan(ctx, "AuthRepo.Delete")
+	defer endSpan(span, &restErr)
+
 	deleted, err := ar.rClient.Del(ctx, uuid).Result()
 	//del success, then return 1
 	if err != nil || deleted != 1 {
-		log.Println("error when delete refresh token in redis")
+		logger.Warn("error when delete refresh token in redis", logger.Err(err), logger.F("deleted", deleted))
 		redisErr := errors.NewUnauthorizedError("unauthorized, refresh token is not valid")
 		return redisErr
 	}
 	return nil
 }
 
-func (ar *AuthRepo) Fetch(uuid string) (int64, *errors.RestErr) {
+func (ar *AuthRepo) Fetch(ctx context.Context, uuid string) (_ int64, restErr *errors.RestErr) {
+	ctx, span := startRedisSpan(ctx, "AuthRepo.Fetch")
+	defer endSpan(span, &restErr)
+
 	userIDAndRt, err := ar.rClient.Get(ctx, uuid).Result()
 	if err != nil {
 		if err == redis.Nil {
@@ -57,15 +65,12 @@ func (ar *AuthRepo) Fetch(uuid string) (int64, *errors.RestErr) {
 			return 0, redisErr
 		}
 		redisErr := errors.NewUnauthorizedError("unauthorized, refresh token is expired please relogin")
-		log.Println("error when get refresh token in redis, ", err)
+		logger.Error("error when get refresh token in redis", logger.Err(err))
 		return 0, redisErr
 	}
 
 	//split userID + '-' + rt
 	userID := strings.Split(userIDAndRt, "-")[0]
 	uid, _ := strconv.ParseInt(userID, 10, 64)
-	log.Println(userIDAndRt)
-	log.Println(userID)
-	log.Println(uid)
 	return uid, nil
 }
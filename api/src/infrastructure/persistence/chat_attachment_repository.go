@@ -0,0 +1,95 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/helpers"
+)
+
+type chatAttachmentRepo struct {
+	db *sql.DB
+}
+
+func NewChatAttachmentRepo(db *sql.DB) *chatAttachmentRepo {
+	return &chatAttachmentRepo{db}
+}
+
+var _ repository.ChatAttachmentRepository = &chatAttachmentRepo{}
+
+func (c *chatAttachmentRepo) SaveAttachment(ctx context.Context, attachment *entity.ChatAttachment) (_ *entity.ChatAttachment, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatAttachmentRepo.SaveAttachment")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
+		INSERT INTO chat_attachment (chat_room_id, uploader_id, file_name, content_type, size, storage_key, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, chat_room_id, uploader_id, file_name, content_type, size, storage_key, created_at;
+	`)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	now := helpers.GetCurrentTimeForDB()
+
+	var newAttachment entity.ChatAttachment
+	err = stmt.QueryRowContext(ctx, attachment.ChatRoomID, attachment.UploaderID, attachment.FileName, attachment.ContentType, attachment.Size, attachment.StorageKey, now).
+		Scan(&newAttachment.ID, &newAttachment.ChatRoomID, &newAttachment.UploaderID, &newAttachment.FileName, &newAttachment.ContentType, &newAttachment.Size,
+			&newAttachment.StorageKey, &newAttachment.CreatedAt)
+	if err != nil {
+		return nil, errors.NewInternalServerError("queryrow error " + err.Error())
+	}
+
+	return &newAttachment, nil
+}
+
+func (c *chatAttachmentRepo) GetAttachment(ctx context.Context, id int64) (_ *entity.ChatAttachment, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatAttachmentRepo.GetAttachment")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
+		SELECT id, chat_room_id, uploader_id, file_name, content_type, size, storage_key, created_at
+		FROM chat_attachment
+		WHERE id=$1;
+	`)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	var attachment entity.ChatAttachment
+	err = stmt.QueryRowContext(ctx, id).Scan(&attachment.ID, &attachment.ChatRoomID, &attachment.UploaderID, &attachment.FileName, &attachment.ContentType, &attachment.Size,
+		&attachment.StorageKey, &attachment.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("attachment not found")
+		}
+		return nil, errors.NewInternalServerError("queryrow error " + err.Error())
+	}
+
+	return &attachment, nil
+}
+
+func (c *chatAttachmentRepo) DeleteAttachment(ctx context.Context, id int64) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatAttachmentRepo.DeleteAttachment")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
+		DELETE FROM chat_attachment
+		WHERE id=$1;
+	`)
+	if err != nil {
+		return errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	if _, err = stmt.ExecContext(ctx, id); err != nil {
+		return errors.NewInternalServerError("execute error " + err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/helpers"
+)
+
+type chatReportRepo struct {
+	db *sql.DB
+}
+
+func NewChatReportRepo(db *sql.DB) *chatReportRepo {
+	return &chatReportRepo{db}
+}
+
+var _ repository.ChatReportRepository = &chatReportRepo{}
+
+// chatReportColumns 신고와 신고된 메시지를 함께 조회 (chat_message_report r, chat_message m)
+const chatReportColumns = `
+	r.id, r.chat_message_id, m.chat_room_id, r.reporter_id, r.reason, r.status,
+	m.sender_id, m.sender, m.message, COALESCE(r.reviewed_by, 0), r.reviewed_at, r.created_at`
+
+type chatReportScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanChatReport(row chatReportScanner, r *entity.ChatMessageReport) error {
+	var reviewedAt sql.NullString
+
+	err := row.Scan(&r.ID, &r.ChatMessageID, &r.ChatRoomID, &r.ReporterID, &r.Reason, &r.Status,
+		&r.SenderID, &r.Sender, &r.Message, &r.ReviewedBy, &reviewedAt, &r.CreatedAt)
+	r.ReviewedAt = reviewedAt.String
+
+	return err
+}
+
+// SaveReport 같은 유저가 같은 메시지를 다시 신고하면 기존 신고를 반환
+func (c *chatReportRepo) SaveReport(ctx context.Context, report *entity.ChatMessageReport) (_ *entity.ChatMessageReport, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatReportRepo.SaveReport")
+	defer endSpan(span, &restErr)
+
+	var reportID int64
+
+	err := conn(ctx, c.db).QueryRowContext(ctx, `
+		INSERT INTO chat_message_report (chat_message_id, reporter_id, reason, status, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (chat_message_id, reporter_id) DO UPDATE SET chat_message_id=EXCLUDED.chat_message_id
+		RETURNING id;
+	`, report.ChatMessageID, report.ReporterID, report.Reason, entity.ReportStatusOpen, helpers.GetCurrentTimeForDB()).Scan(&reportID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database insert error " + err.Error())
+	}
+
+	return c.GetReport(ctx, reportID)
+}
+
+func (c *chatReportRepo) GetReport(ctx context.Context, reportID int64) (_ *entity.ChatMessageReport, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatReportRepo.GetReport")
+	defer endSpan(span, &restErr)
+
+	var report entity.ChatMessageReport
+
+	err := scanChatReport(conn(ctx, c.db).QueryRowContext(ctx, `
+		SELECT `+chatReportColumns+`
+		FROM chat_message_report r
+		JOIN chat_message m ON m.id=r.chat_message_id
+		WHERE r.id=$1;
+	`, reportID), &report)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("report doesn't exist")
+		}
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+
+	return &report, nil
+}
+
+// GetReports 오래된 신고부터 limit개
+func (c *chatReportRepo) GetReports(ctx context.Context, status string, limit int64) (_ []entity.ChatMessageReport, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatReportRepo.GetReports")
+	defer endSpan(span, &restErr)
+
+	rows, err := conn(ctx, c.db).QueryContext(ctx, `
+		SELECT `+chatReportColumns+`
+		FROM chat_message_report r
+		JOIN chat_message m ON m.id=r.chat_message_id
+		WHERE r.status=$1
+		ORDER BY r.id
+		LIMIT $2;
+	`, status, limit)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer rows.Close()
+
+	var reports []entity.ChatMessageReport
+	for rows.Next() {
+		var report entity.ChatMessageReport
+		if err := scanChatReport(rows, &report); err != nil {
+			return nil, errors.NewInternalServerError("database error " + err.Error())
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+func (c *chatReportRepo) ResolveReports(ctx context.Context, chatMessageID, moderatorID int64, status string) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatReportRepo.ResolveReports")
+	defer endSpan(span, &restErr)
+
+	_, err := conn(ctx, c.db).ExecContext(ctx, `
+		UPDATE chat_message_report
+		SET status=$1, reviewed_by=$2, reviewed_at=$3
+		WHERE chat_message_id=$4 AND status=$5;
+	`, status, moderatorID, helpers.GetCurrentTimeForDB(), chatMessageID, entity.ReportStatusOpen)
+	if err != nil {
+		return errors.NewInternalServerError("database update error " + err.Error())
+	}
+
+	return nil
+}
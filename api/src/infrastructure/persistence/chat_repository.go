@@ -1,7 +1,12 @@
 package persistence
 
 import (
+	"context"
 	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+
 	"github.com/code-wave/go-wave/domain/entity"
 	"github.com/code-wave/go-wave/infrastructure/errors"
 	"github.com/code-wave/go-wave/infrastructure/helpers"
@@ -17,19 +22,23 @@ func NewChatRepo(db *sql.DB) *chatRepo {
 }
 
 // GetChatRoom 채팅룸이 존재하지 않으면 새로 만들기도함
-func (c *chatRepo) GetChatRoom(clientID, hostID, studyPostID int64) (*entity.ChatRoom, *errors.RestErr) {
-	stmt, err := c.db.Prepare(`
+func (c *chatRepo) GetChatRoom(ctx context.Context, clientID, hostID, studyPostID int64) (_ *entity.ChatRoom, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.GetChatRoom")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
 		SELECT *
 		FROM chat_room
-		WHERE client_id=$1 AND host_id=$2 AND study_post_id=$3;
+		WHERE client_id=$1 AND host_id=$2 AND study_post_id=$3 AND room_type='direct';
 	`)
 	if err != nil {
 		return nil, errors.NewInternalServerError("database error " + err.Error())
 	}
+	defer stmt.Close()
 
 	var chatRoom entity.ChatRoom
 
-	err = stmt.QueryRow(clientID, hostID, studyPostID).Scan(&chatRoom.ID, &chatRoom.RoomName, &chatRoom.ClientID, &chatRoom.HostID, &chatRoom.StudyPostID)
+	err = stmt.QueryRowContext(ctx, clientID, hostID, studyPostID).Scan(&chatRoom.ID, &chatRoom.RoomName, &chatRoom.ClientID, &chatRoom.HostID, &chatRoom.StudyPostID, &chatRoom.RoomType)
 	if err != nil {
 		if err == sql.ErrNoRows { // 채팅룸이 존재하지 않으면 새로 만들고 반환
 			noRowsErr := errors.NewNoRowsError()
@@ -42,8 +51,11 @@ func (c *chatRepo) GetChatRoom(clientID, hostID, studyPostID int64) (*entity.Cha
 	return &chatRoom, nil
 }
 
-func (c *chatRepo) SaveChatRoom(clientID, hostID, studyPostID int64) (*entity.ChatRoom, *errors.RestErr) {
-	stmt, err := c.db.Prepare(`
+func (c *chatRepo) SaveChatRoom(ctx context.Context, clientID, hostID, studyPostID int64) (_ *entity.ChatRoom, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.SaveChatRoom")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
 		INSERT INTO chat_room (room_name, client_id, host_id, study_post_id)
 		VALUES ($1, $2, $3, $4)
 		RETURNING *;
@@ -51,12 +63,13 @@ func (c *chatRepo) SaveChatRoom(clientID, hostID, studyPostID int64) (*entity.Ch
 	if err != nil {
 		return nil, errors.NewInternalServerError("database error " + err.Error())
 	}
+	defer stmt.Close()
 
 	var newRoom entity.ChatRoom
 
 	roomName := uuid.New()
 
-	err = stmt.QueryRow(roomName, clientID, hostID, studyPostID).Scan(&newRoom.ID, &newRoom.RoomName, &newRoom.ClientID, &newRoom.HostID, &newRoom.StudyPostID)
+	err = stmt.QueryRowContext(ctx, roomName, clientID, hostID, studyPostID).Scan(&newRoom.ID, &newRoom.RoomName, &newRoom.ClientID, &newRoom.HostID, &newRoom.StudyPostID, &newRoom.RoomType)
 	if err != nil {
 		return nil, errors.NewInternalServerError("query row error " + err.Error())
 	}
@@ -64,8 +77,213 @@ func (c *chatRepo) SaveChatRoom(clientID, hostID, studyPostID int64) (*entity.Ch
 	return &newRoom, nil
 }
 
-func (c *chatRepo) GetChatRoomByRoomName(roomName string) (*entity.ChatRoom, *errors.RestErr) {
-	stmt, err := c.db.Prepare(`
+// GetGroupChatRoom 게시글의 팀 채팅룸을 반환 (없으면 NoRowsError)
+func (c *chatRepo) GetGroupChatRoom(ctx context.Context, studyPostID int64) (_ *entity.ChatRoom, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.GetGroupChatRoom")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
+		SELECT *
+		FROM chat_room
+		WHERE study_post_id=$1 AND room_type='group';
+	`)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	var chatRoom entity.ChatRoom
+
+	err = stmt.QueryRowContext(ctx, studyPostID).Scan(&chatRoom.ID, &chatRoom.RoomName, &chatRoom.ClientID, &chatRoom.HostID, &chatRoom.StudyPostID, &chatRoom.RoomType)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNoRowsError()
+		}
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+
+	return &chatRoom, nil
+}
+
+// SaveGroupChatRoom 게시글의 팀 채팅룸을 만들고 host를 참여자로 추가
+func (c *chatRepo) SaveGroupChatRoom(ctx context.Context, hostID, studyPostID int64) (_ *entity.ChatRoom, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.SaveGroupChatRoom")
+	defer endSpan(span, &restErr)
+
+	tx, err := beginTx(ctx, c.db)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer tx.Rollback() // commit된 후에는 아무것도 하지 않음
+
+	var newRoom entity.ChatRoom
+
+	roomName := uuid.New()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO chat_room (room_name, client_id, host_id, study_post_id, room_type)
+		VALUES ($1, $2, $2, $3, 'group')
+		RETURNING *;
+	`, roomName, hostID, studyPostID).Scan(&newRoom.ID, &newRoom.RoomName, &newRoom.ClientID, &newRoom.HostID, &newRoom.StudyPostID, &newRoom.RoomType)
+	if err != nil {
+		return nil, errors.NewInternalServerError("query row error " + err.Error())
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO chat_room_participant (chat_room_id, user_id, joined_at)
+		VALUES ($1, $2, $3);
+	`, newRoom.ID, hostID, helpers.GetCurrentTimeForDB())
+	if err != nil {
+		return nil, errors.NewInternalServerError("execute error " + err.Error())
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.NewInternalServerError("commit error " + err.Error())
+	}
+
+	return &newRoom, nil
+}
+
+func (c *chatRepo) AddChatRoomParticipant(ctx context.Context, roomID, userID int64) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.AddChatRoomParticipant")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
+		INSERT INTO chat_room_participant (chat_room_id, user_id, joined_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING;
+	`)
+	if err != nil {
+		return errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, roomID, userID, helpers.GetCurrentTimeForDB())
+	if err != nil {
+		return errors.NewInternalServerError("execute error " + err.Error())
+	}
+
+	return nil
+}
+
+func (c *chatRepo) RemoveChatRoomParticipant(ctx context.Context, roomID, userID int64) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.RemoveChatRoomParticipant")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
+		DELETE FROM chat_room_participant
+		WHERE chat_room_id=$1 AND user_id=$2;
+	`)
+	if err != nil {
+		return errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, roomID, userID)
+	if err != nil {
+		return errors.NewInternalServerError("execute error " + err.Error())
+	}
+
+	return nil
+}
+
+// SharesChatRoom 두 유저가 모두 참여자인 채팅룸이 있는지 확인 (참여자 조건은 IsChatRoomParticipant와 같음)
+func (c *chatRepo) SharesChatRoom(ctx context.Context, userID, otherUserID int64) (_ bool, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.SharesChatRoom")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM chat_room r
+			WHERE ((r.room_type='direct' AND (r.client_id=$1 OR r.host_id=$1))
+			   OR EXISTS (SELECT 1 FROM chat_room_participant cp WHERE cp.chat_room_id=r.id AND cp.user_id=$1))
+			  AND ((r.room_type='direct' AND (r.client_id=$2 OR r.host_id=$2))
+			   OR EXISTS (SELECT 1 FROM chat_room_participant cp WHERE cp.chat_room_id=r.id AND cp.user_id=$2))
+		);
+	`)
+	if err != nil {
+		return false, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	var shares bool
+
+	if err = stmt.QueryRowContext(ctx, userID, otherUserID).Scan(&shares); err != nil {
+		return false, errors.NewInternalServerError("database error " + err.Error())
+	}
+
+	return shares, nil
+}
+
+// IsChatRoomParticipant 1:1 채팅이면 client/host인지, 팀 채팅이면 참여자인지 확인
+func (c *chatRepo) IsChatRoomParticipant(ctx context.Context, roomID, userID int64) (_ bool, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.IsChatRoomParticipant")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM chat_room r
+			WHERE r.id=$1
+			  AND ((r.room_type='direct' AND (r.client_id=$2 OR r.host_id=$2))
+			   OR EXISTS (SELECT 1 FROM chat_room_participant cp WHERE cp.chat_room_id=r.id AND cp.user_id=$2))
+		);
+	`)
+	if err != nil {
+		return false, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	var isParticipant bool
+
+	if err = stmt.QueryRowContext(ctx, roomID, userID).Scan(&isParticipant); err != nil {
+		return false, errors.NewInternalServerError("database error " + err.Error())
+	}
+
+	return isParticipant, nil
+}
+
+// GetChatRoomParticipantIDs 채팅룸 참여자들의 user id (1:1이면 client/host, 팀 채팅이면 팀원)
+func (c *chatRepo) GetChatRoomParticipantIDs(ctx context.Context, roomID int64) (_ []int64, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.GetChatRoomParticipantIDs")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
+		SELECT r.client_id FROM chat_room r WHERE r.id=$1 AND r.room_type='direct'
+		UNION
+		SELECT r.host_id FROM chat_room r WHERE r.id=$1 AND r.room_type='direct'
+		UNION
+		SELECT cp.user_id FROM chat_room_participant cp WHERE cp.chat_room_id=$1;
+	`)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, roomID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer rows.Close()
+
+	var userIDs []int64
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, errors.NewInternalServerError("database error " + err.Error())
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, nil
+}
+
+func (c *chatRepo) GetChatRoomByRoomName(ctx context.Context, roomName string) (_ *entity.ChatRoom, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.GetChatRoomByRoomName")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
 		SELECT *
 		FROM chat_room
 		WHERE room_name=$1;
@@ -73,19 +291,26 @@ func (c *chatRepo) GetChatRoomByRoomName(roomName string) (*entity.ChatRoom, *er
 	if err != nil {
 		return nil, errors.NewInternalServerError("database error " + err.Error())
 	}
+	defer stmt.Close()
 
 	var newRoom entity.ChatRoom
 
-	err = stmt.QueryRow(roomName).Scan(&newRoom.ID, &newRoom.ClientID, &newRoom.RoomName, &newRoom.HostID, &newRoom.StudyPostID)
+	err = stmt.QueryRowContext(ctx, roomName).Scan(&newRoom.ID, &newRoom.RoomName, &newRoom.ClientID, &newRoom.HostID, &newRoom.StudyPostID, &newRoom.RoomType)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("chat room not found")
+		}
 		return nil, errors.NewInternalServerError("query row error " + err.Error())
 	}
 
 	return &newRoom, nil
 }
 
-func (c *chatRepo) GetChatRoomByID(id int64) (*entity.ChatRoom, *errors.RestErr) {
-	stmt, err := c.db.Prepare(`
+func (c *chatRepo) GetChatRoomByID(ctx context.Context, id int64) (_ *entity.ChatRoom, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.GetChatRoomByID")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
 		SELECT *
 		FROM chat_room
 		WHERE id=$1;
@@ -93,42 +318,133 @@ func (c *chatRepo) GetChatRoomByID(id int64) (*entity.ChatRoom, *errors.RestErr)
 	if err != nil {
 		return nil, errors.NewInternalServerError("database error " + err.Error())
 	}
+	defer stmt.Close()
 
 	var newRoom entity.ChatRoom
 
-	err = stmt.QueryRow(id).Scan(&newRoom.ID, &newRoom.ClientID, &newRoom.RoomName, &newRoom.HostID, &newRoom.StudyPostID)
+	err = stmt.QueryRowContext(ctx, id).Scan(&newRoom.ID, &newRoom.RoomName, &newRoom.ClientID, &newRoom.HostID, &newRoom.StudyPostID, &newRoom.RoomType)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("chat room not found")
+		}
 		return nil, errors.NewInternalServerError("query row error " + err.Error())
 	}
 
 	return &newRoom, nil
 }
 
-func (c *chatRepo) SaveChatMessage(msg *entity.ChatMessage) (*entity.ChatMessage, *errors.RestErr) {
-	stmt, err := c.db.Prepare(`
-		INSERT INTO chat_message (chat_room_id, chat_room_name, sender_id, sender, message_type, message, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+// SaveChatMessage 같은 채팅룸에 client_message_id가 같은 메시지가 이미 저장되어 있으면 새로 저장하지 않고 저장된 메시지를 반환
+func (c *chatRepo) SaveChatMessage(ctx context.Context, msg *entity.ChatMessage) (_ *entity.ChatMessage, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.SaveChatMessage")
+	defer endSpan(span, &restErr)
+
+	if msg.ClientMessageID.Valid {
+		savedMsg, restErr := c.GetChatMessageByClientMessageID(ctx, msg.ChatRoomID, msg.SenderID, msg.ClientMessageID.String)
+		if restErr == nil {
+			return savedMsg, nil
+		}
+		if restErr.Status != http.StatusNotFound {
+			return nil, restErr
+		}
+	}
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
+		INSERT INTO chat_message (chat_room_id, chat_room_name, sender_id, sender, message_type, message, created_at, attachment_id, client_message_id)
+		SELECT $1::bigint, $2, $3::bigint, $4, $5, $6, $7, $8::bigint, $9
+		WHERE $8::bigint IS NULL OR EXISTS (
+			SELECT 1 FROM chat_attachment a
+			WHERE a.id=$8::bigint AND a.chat_room_id=$1 AND a.uploader_id=$3
+			AND NOT EXISTS (SELECT 1 FROM chat_message m WHERE m.attachment_id=a.id)
+		)
+		ON CONFLICT (chat_room_id, sender_id, client_message_id) WHERE client_message_id IS NOT NULL DO NOTHING
 		RETURNING *;
 	`)
 	if err != nil {
 		return nil, errors.NewInternalServerError("database error " + err.Error())
 	}
+	defer stmt.Close()
 
 	now := helpers.GetCurrentTimeForDB()
 
 	newMsg := entity.ChatMessage{}
 
-	err = stmt.QueryRow(msg.ChatRoomID, msg.ChatRoomName, msg.SenderID, msg.Sender, msg.MessageType, msg.Message, now).Scan(&newMsg.ChatRoomID, &newMsg.ChatRoomName, &newMsg.SenderID, &newMsg.Sender, &newMsg.MessageType, &newMsg.Message, &newMsg.CreatedAt)
+	// 첨부파일은 같은 채팅룸에 본인이 올린, 아직 다른 메시지에 쓰이지 않은 것만 붙일 수 있음
+	err = stmt.QueryRowContext(ctx, msg.ChatRoomID, msg.ChatRoomName, msg.SenderID, msg.Sender, msg.MessageType, msg.Message, now, msg.AttachmentID, msg.ClientMessageID).
+		Scan(chatMessageFields(&newMsg)...)
 	if err != nil {
+		if err == sql.ErrNoRows && msg.ClientMessageID.Valid {
+			// 동시에 같은 메시지를 다시 보내서 다른 요청이 먼저 저장한 경우
+			if savedMsg, restErr := c.GetChatMessageByClientMessageID(ctx, msg.ChatRoomID, msg.SenderID, msg.ClientMessageID.String); restErr == nil {
+				return savedMsg, nil
+			}
+		}
+		if err == sql.ErrNoRows || strings.Contains(err.Error(), "chat_message_attachment_id_idx") {
+			return nil, errors.NewBadRequestError("invalid attachment")
+		}
 		return nil, errors.NewInternalServerError("queryrow error " + err.Error())
 	}
 
 	return &newMsg, nil
 }
 
+func (c *chatRepo) GetChatMessageByClientMessageID(ctx context.Context, roomID, senderID int64, clientMessageID string) (_ *entity.ChatMessage, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.GetChatMessageByClientMessageID")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
+		SELECT *
+		FROM chat_message
+		WHERE chat_room_id=$1 AND sender_id=$2 AND client_message_id=$3;
+	`)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	var chatMessage entity.ChatMessage
+	err = stmt.QueryRowContext(ctx, roomID, senderID, clientMessageID).Scan(chatMessageFields(&chatMessage)...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("chat message not found")
+		}
+		return nil, errors.NewInternalServerError("queryrow error " + err.Error())
+	}
+
+	return &chatMessage, nil
+}
+
+func (c *chatRepo) GetChatMessage(ctx context.Context, messageID int64) (_ *entity.ChatMessage, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.GetChatMessage")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
+		SELECT *
+		FROM chat_message
+		WHERE id=$1;
+	`)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	var chatMessage entity.ChatMessage
+	err = stmt.QueryRowContext(ctx, messageID).Scan(chatMessageFields(&chatMessage)...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("chat message doesn't exist")
+		}
+		return nil, errors.NewInternalServerError("queryrow error " + err.Error())
+	}
+
+	return &chatMessage, nil
+}
+
 // TODO: 메시지 query문 수정 필요(user_name 불러오는 query문 작성!)
-func (c *chatRepo) GetChatMessages(roomID int64) ([]entity.ChatMessage, *errors.RestErr) {
-	stmt, err := c.db.Prepare(`
+func (c *chatRepo) GetChatMessages(ctx context.Context, roomID int64) (_ []entity.ChatMessage, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.GetChatMessages")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
 		SELECT *
 		FROM chat_message
 		WHERE chat_room_id=$1 ORDER BY created_at DESC;
@@ -138,20 +454,535 @@ func (c *chatRepo) GetChatMessages(roomID int64) ([]entity.ChatMessage, *errors.
 	}
 	defer stmt.Close()
 
-	rows, err := stmt.Query(roomID)
+	rows, err := stmt.QueryContext(ctx, roomID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+
+	return scanChatMessages(rows)
+}
+
+// GetChatMessagesBefore messageID보다 이전에 저장된 메시지를 최신순으로 limit개 반환 (messageID가 0이면 가장 최근 메시지부터)
+func (c *chatRepo) GetChatMessagesBefore(ctx context.Context, roomID, messageID, limit int64) (_ []entity.ChatMessage, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.GetChatMessagesBefore")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
+		SELECT *
+		FROM chat_message
+		WHERE chat_room_id=$1 AND ($2=0 OR id<$2)
+		ORDER BY id DESC
+		LIMIT $3;
+	`)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, roomID, messageID, limit)
 	if err != nil {
 		return nil, errors.NewInternalServerError("database error " + err.Error())
 	}
 
+	return scanChatMessages(rows)
+}
+
+// GetChatMessagesAfter messageID 이후에 저장된 메시지를 오래된순으로 limit개 반환 (재접속 후 놓친 메시지를 받기 위함)
+func (c *chatRepo) GetChatMessagesAfter(ctx context.Context, roomID, messageID, limit int64) (_ []entity.ChatMessage, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.GetChatMessagesAfter")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
+		SELECT *
+		FROM chat_message
+		WHERE chat_room_id=$1 AND id>$2
+		ORDER BY id ASC
+		LIMIT $3;
+	`)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, roomID, messageID, limit)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+
+	return scanChatMessages(rows)
+}
+
+// ForEachChatMessage 채팅룸의 모든 메시지를 오래된순으로 하나씩 fn에 넘김 (메시지가 많아도 메모리에 모두 올리지 않음)
+// fn이 에러를 반환하면 중단함
+func (c *chatRepo) ForEachChatMessage(ctx context.Context, roomID int64, fn func(entity.ChatMessage) error) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.ForEachChatMessage")
+	defer endSpan(span, &restErr)
+
+	rows, err := conn(ctx, c.db).QueryContext(ctx, `
+		SELECT *
+		FROM chat_message
+		WHERE chat_room_id=$1
+		ORDER BY id ASC;
+	`, roomID)
+	if err != nil {
+		return errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var chatMessage entity.ChatMessage
+		if err := rows.Scan(chatMessageFields(&chatMessage)...); err != nil {
+			return errors.NewInternalServerError("database error " + err.Error())
+		}
+
+		if err := fn(chatMessage); err != nil {
+			return errors.NewInternalServerError("chat message iteration stopped " + err.Error())
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return errors.NewInternalServerError("database error " + err.Error())
+	}
+
+	return nil
+}
+
+// chatMessageFields chat_message의 컬럼 순서대로 Scan할 필드들
+func chatMessageFields(m *entity.ChatMessage) []interface{} {
+	return []interface{}{&m.ID, &m.ChatRoomID, &m.ChatRoomName, &m.SenderID, &m.Sender, &m.MessageType, &m.Message, &m.CreatedAt,
+		&m.EditedAt, &m.DeletedAt, &m.AttachmentID, &m.ClientMessageID}
+}
+
+func scanChatMessages(rows *sql.Rows) ([]entity.ChatMessage, *errors.RestErr) {
+	defer rows.Close()
+
 	var chatMessages []entity.ChatMessage
 	for rows.Next() {
 		var chatMessage entity.ChatMessage
-		err = rows.Scan(&chatMessage.ChatRoomID, &chatMessage.ChatRoomName, &chatMessage.SenderID, &chatMessage.Sender, &chatMessage.MessageType, &chatMessage.Message, &chatMessage.CreatedAt)
+		err := rows.Scan(chatMessageFields(&chatMessage)...)
 		if err != nil {
 			return nil, errors.NewInternalServerError("database error " + err.Error())
 		}
 		chatMessages = append(chatMessages, chatMessage)
 	}
 
+	if err := rows.Err(); err != nil { // 끝난 후에도 에러체크 한번
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+
 	return chatMessages, nil
 }
+
+// SaveReadMarker 유저가 마지막으로 읽은 메시지를 저장 (이미 더 최근 메시지를 읽었으면 유지)
+func (c *chatRepo) SaveReadMarker(ctx context.Context, marker *entity.ChatReadMarker) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.SaveReadMarker")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
+		INSERT INTO chat_read_marker (chat_room_id, user_id, last_read_message_id, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (chat_room_id, user_id)
+		DO UPDATE SET last_read_message_id=GREATEST(chat_read_marker.last_read_message_id, EXCLUDED.last_read_message_id),
+		              updated_at=EXCLUDED.updated_at;
+	`)
+	if err != nil {
+		return errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	now := helpers.GetCurrentTimeForDB()
+
+	_, err = stmt.ExecContext(ctx, marker.ChatRoomID, marker.UserID, marker.LastReadMessageID, now)
+	if err != nil {
+		return errors.NewInternalServerError("execute error " + err.Error())
+	}
+
+	marker.UpdatedAt = now
+
+	return nil
+}
+
+// GetUnreadChatRooms 유저가 속한 채팅룸들을 안 읽은 메시지 개수, 마지막 메시지와 함께 최근 메시지 순으로 반환
+// 자기가 보낸 메시지는 안 읽은 메시지로 세지 않음
+func (c *chatRepo) GetUnreadChatRooms(ctx context.Context, userID int64) (_ []entity.UnreadChatRoom, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.GetUnreadChatRooms")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
+		SELECT r.id, r.room_name, r.client_id, r.host_id, r.study_post_id, r.room_type,
+		       COALESCE(rm.last_read_message_id, 0),
+		       (SELECT COUNT(*)
+		        FROM chat_message m
+		        WHERE m.chat_room_id=r.id AND m.id>COALESCE(rm.last_read_message_id, 0) AND m.sender_id<>$1),
+		       lm.id, lm.sender_id, lm.sender, lm.message_type, lm.message, lm.created_at, lm.edited_at, lm.deleted_at, lm.attachment_id
+		FROM chat_room r
+		LEFT JOIN chat_read_marker rm ON rm.chat_room_id=r.id AND rm.user_id=$1
+		LEFT JOIN LATERAL (
+			SELECT *
+			FROM chat_message
+			WHERE chat_room_id=r.id
+			ORDER BY id DESC
+			LIMIT 1
+		) lm ON true
+		WHERE r.client_id=$1 OR r.host_id=$1
+		   OR EXISTS (SELECT 1 FROM chat_room_participant cp WHERE cp.chat_room_id=r.id AND cp.user_id=$1)
+		ORDER BY lm.id DESC NULLS LAST;
+	`)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, userID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer rows.Close()
+
+	var unreadRooms []entity.UnreadChatRoom
+	for rows.Next() {
+		var unreadRoom entity.UnreadChatRoom
+		var lastMessage nullableChatMessage
+
+		err = rows.Scan(&unreadRoom.ChatRoom.ID, &unreadRoom.ChatRoom.RoomName, &unreadRoom.ChatRoom.ClientID, &unreadRoom.ChatRoom.HostID, &unreadRoom.ChatRoom.StudyPostID, &unreadRoom.ChatRoom.RoomType,
+			&unreadRoom.LastReadMessageID, &unreadRoom.UnreadCount,
+			&lastMessage.ID, &lastMessage.SenderID, &lastMessage.Sender, &lastMessage.MessageType, &lastMessage.Message, &lastMessage.CreatedAt,
+			&lastMessage.EditedAt, &lastMessage.DeletedAt, &lastMessage.AttachmentID)
+		if err != nil {
+			return nil, errors.NewInternalServerError("database error " + err.Error())
+		}
+		unreadRoom.LastMessage = lastMessage.toEntity(&unreadRoom.ChatRoom)
+
+		unreadRooms = append(unreadRooms, unreadRoom)
+	}
+
+	if err = rows.Err(); err != nil { // 끝난 후에도 에러체크 한번
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+
+	return unreadRooms, nil
+}
+
+// GetChatInbox 유저가 참여중인 채팅룸들을 상대방(팀 채팅이면 다른 참여자들) 정보, 게시글 제목, 마지막 메시지와 함께 최근 활동순으로 반환
+func (c *chatRepo) GetChatInbox(ctx context.Context, userID int64) (_ []entity.ChatInboxItem, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.GetChatInbox")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
+		SELECT r.id, r.room_name, r.client_id, r.host_id, r.study_post_id, r.room_type,
+		       u.id, u.email, u.nickname,
+		       p.title,
+		       (SELECT COUNT(*)
+		        FROM chat_message m
+		        WHERE m.chat_room_id=r.id AND m.id>COALESCE(rm.last_read_message_id, 0) AND m.sender_id<>$1),
+		       lm.id, lm.sender_id, lm.sender, lm.message_type, lm.message, lm.created_at, lm.edited_at, lm.deleted_at, lm.attachment_id
+		FROM chat_room r
+		LEFT JOIN users u ON r.room_type='direct' AND u.id=(CASE WHEN r.host_id=$1 THEN r.client_id ELSE r.host_id END)
+		JOIN study_post p ON p.id=r.study_post_id
+		LEFT JOIN chat_read_marker rm ON rm.chat_room_id=r.id AND rm.user_id=$1
+		LEFT JOIN LATERAL (
+			SELECT *
+			FROM chat_message
+			WHERE chat_room_id=r.id
+			ORDER BY id DESC
+			LIMIT 1
+		) lm ON true
+		WHERE r.client_id=$1 OR r.host_id=$1
+		   OR EXISTS (SELECT 1 FROM chat_room_participant cp WHERE cp.chat_room_id=r.id AND cp.user_id=$1)
+		ORDER BY lm.created_at DESC NULLS LAST, r.id DESC;
+	`)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, userID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer rows.Close()
+
+	var inbox []entity.ChatInboxItem
+	for rows.Next() {
+		var item entity.ChatInboxItem
+		var counterpartID sql.NullInt64
+		var counterpartEmail, counterpartNickname sql.NullString
+		var lastMessage nullableChatMessage
+
+		err = rows.Scan(&item.ChatRoom.ID, &item.ChatRoom.RoomName, &item.ChatRoom.ClientID, &item.ChatRoom.HostID, &item.ChatRoom.StudyPostID, &item.ChatRoom.RoomType,
+			&counterpartID, &counterpartEmail, &counterpartNickname,
+			&item.StudyPostTitle, &item.UnreadCount,
+			&lastMessage.ID, &lastMessage.SenderID, &lastMessage.Sender, &lastMessage.MessageType, &lastMessage.Message, &lastMessage.CreatedAt,
+			&lastMessage.EditedAt, &lastMessage.DeletedAt, &lastMessage.AttachmentID)
+		if err != nil {
+			return nil, errors.NewInternalServerError("database error " + err.Error())
+		}
+		if counterpartID.Valid {
+			item.Counterpart = &entity.PublicUser{ID: counterpartID.Int64, Email: counterpartEmail.String, Nickname: counterpartNickname.String}
+		}
+		item.LastMessage = lastMessage.toEntity(&item.ChatRoom)
+
+		inbox = append(inbox, item)
+	}
+
+	if err = rows.Err(); err != nil { // 끝난 후에도 에러체크 한번
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+
+	participants, restErr := c.groupChatParticipants(ctx, userID)
+	if restErr != nil {
+		return nil, restErr
+	}
+	for i := range inbox {
+		inbox[i].Participants = participants[inbox[i].ChatRoom.ID]
+	}
+
+	return inbox, nil
+}
+
+// groupChatParticipants 유저가 참여중인 팀 채팅룸마다 다른 참여자들을 반환
+func (c *chatRepo) groupChatParticipants(ctx context.Context, userID int64) (map[int64][]entity.PublicUser, *errors.RestErr) {
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
+		SELECT cp.chat_room_id, u.id, u.email, u.nickname
+		FROM chat_room_participant cp
+		JOIN users u ON u.id=cp.user_id
+		WHERE cp.user_id<>$1
+		  AND cp.chat_room_id IN (SELECT chat_room_id FROM chat_room_participant WHERE user_id=$1)
+		ORDER BY cp.chat_room_id, u.id;
+	`)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, userID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer rows.Close()
+
+	participants := make(map[int64][]entity.PublicUser)
+	for rows.Next() {
+		var roomID int64
+		var participant entity.PublicUser
+		if err := rows.Scan(&roomID, &participant.ID, &participant.Email, &participant.Nickname); err != nil {
+			return nil, errors.NewInternalServerError("database error " + err.Error())
+		}
+		participants[roomID] = append(participants[roomID], participant)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+
+	return participants, nil
+}
+
+// nullableChatMessage LEFT JOIN으로 가져온 메시지 (메시지가 없으면 모든 값이 NULL)
+type nullableChatMessage struct {
+	ID           sql.NullInt64
+	SenderID     sql.NullInt64
+	Sender       sql.NullString
+	MessageType  sql.NullString
+	Message      sql.NullString
+	CreatedAt    sql.NullString
+	EditedAt     sql.NullString
+	DeletedAt    sql.NullString
+	AttachmentID sql.NullInt64
+}
+
+func (m *nullableChatMessage) toEntity(chatRoom *entity.ChatRoom) *entity.ChatMessage {
+	if !m.ID.Valid {
+		return nil
+	}
+
+	return &entity.ChatMessage{
+		ID:           m.ID.Int64,
+		ChatRoomID:   chatRoom.ID,
+		ChatRoomName: chatRoom.RoomName,
+		SenderID:     m.SenderID.Int64,
+		Sender:       m.Sender.String,
+		MessageType:  m.MessageType.String,
+		Message:      m.Message.String,
+		CreatedAt:    m.CreatedAt.String,
+		EditedAt:     m.EditedAt,
+		DeletedAt:    m.DeletedAt,
+		AttachmentID: m.AttachmentID,
+	}
+}
+
+// EditChatMessage 보낸 사람이 editWindow 안에 roomID 채팅룸의 메시지를 수정, 수정 전 내용은 chat_message_edit에 남김
+func (c *chatRepo) EditChatMessage(ctx context.Context, roomID, messageID, senderID int64, message string, editWindow time.Duration) (_ *entity.ChatMessage, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.EditChatMessage")
+	defer endSpan(span, &restErr)
+
+	tx, err := beginTx(ctx, c.db)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer tx.Rollback() // commit된 후에는 아무것도 하지 않음
+
+	if restErr := checkModifiableChatMessage(ctx, tx, roomID, messageID, senderID, editWindow); restErr != nil {
+		return nil, restErr
+	}
+
+	now := helpers.GetCurrentTimeForDB()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO chat_message_edit (chat_message_id, message, edited_at)
+		SELECT id, message, $2
+		FROM chat_message
+		WHERE id=$1;
+	`, messageID, now)
+	if err != nil {
+		return nil, errors.NewInternalServerError("execute error " + err.Error())
+	}
+
+	var editedMsg entity.ChatMessage
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE chat_message
+		SET message=$1, edited_at=$2
+		WHERE id=$3
+		RETURNING *;
+	`, message, now, messageID).Scan(chatMessageFields(&editedMsg)...)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database update error " + err.Error())
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.NewInternalServerError("commit error " + err.Error())
+	}
+
+	return &editedMsg, nil
+}
+
+// DeleteChatMessage 보낸 사람이 editWindow 안에 roomID 채팅룸의 메시지를 삭제 (deleted_at만 표시하고 내용은 남겨둠)
+func (c *chatRepo) DeleteChatMessage(ctx context.Context, roomID, messageID, senderID int64, editWindow time.Duration) (_ *entity.ChatMessage, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.DeleteChatMessage")
+	defer endSpan(span, &restErr)
+
+	tx, err := beginTx(ctx, c.db)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer tx.Rollback() // commit된 후에는 아무것도 하지 않음
+
+	if restErr := checkModifiableChatMessage(ctx, tx, roomID, messageID, senderID, editWindow); restErr != nil {
+		return nil, restErr
+	}
+
+	now := helpers.GetCurrentTimeForDB()
+
+	var deletedMsg entity.ChatMessage
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE chat_message
+		SET deleted_at=$1
+		WHERE id=$2
+		RETURNING *;
+	`, now, messageID).Scan(chatMessageFields(&deletedMsg)...)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database update error " + err.Error())
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.NewInternalServerError("commit error " + err.Error())
+	}
+
+	return &deletedMsg, nil
+}
+
+// RemoveChatMessage 모더레이터가 메시지를 삭제 (보낸 사람, 수정 가능 시간을 확인하지 않음)
+func (c *chatRepo) RemoveChatMessage(ctx context.Context, messageID int64) (_ *entity.ChatMessage, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.RemoveChatMessage")
+	defer endSpan(span, &restErr)
+
+	var removedMsg entity.ChatMessage
+
+	err := conn(ctx, c.db).QueryRowContext(ctx, `
+		UPDATE chat_message
+		SET deleted_at=COALESCE(deleted_at, $1)
+		WHERE id=$2
+		RETURNING *;
+	`, helpers.GetCurrentTimeForDB(), messageID).Scan(chatMessageFields(&removedMsg)...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("chat message doesn't exist")
+		}
+		return nil, errors.NewInternalServerError("database update error " + err.Error())
+	}
+
+	return &removedMsg, nil
+}
+
+// checkModifiableChatMessage 메시지를 lock하고 roomID 채팅룸의 메시지인지, 보낸 사람인지, 삭제되지 않았는지, editWindow가 지나지 않았는지 확인
+func checkModifiableChatMessage(ctx context.Context, tx dbConn, roomID, messageID, senderID int64, editWindow time.Duration) *errors.RestErr {
+	cutoff := helpers.GetTimeForDB(time.Now().Add(-editWindow))
+
+	var ownerID int64
+	var isDeleted, inWindow bool
+
+	err := tx.QueryRowContext(ctx, `
+		SELECT sender_id, deleted_at IS NOT NULL, created_at>=$2
+		FROM chat_message
+		WHERE id=$1 AND chat_room_id=$3
+		FOR UPDATE;
+	`, messageID, cutoff, roomID).Scan(&ownerID, &isDeleted, &inWindow)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.NewNotFoundError("chat message doesn't exist")
+		}
+		return errors.NewInternalServerError("database error " + err.Error())
+	}
+
+	if ownerID != senderID {
+		return errors.NewForbiddenError("only the sender can modify the message")
+	}
+	if isDeleted {
+		return errors.NewBadRequestError("chat message is already deleted")
+	}
+	if !inWindow {
+		return errors.NewForbiddenError("chat message can't be modified anymore")
+	}
+
+	return nil
+}
+
+// GetChatMessageEdits 메시지의 수정 이력을 오래된순으로 반환
+func (c *chatRepo) GetChatMessageEdits(ctx context.Context, messageID int64) (_ []entity.ChatMessageEdit, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "chatRepo.GetChatMessageEdits")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, c.db).PrepareContext(ctx, `
+		SELECT *
+		FROM chat_message_edit
+		WHERE chat_message_id=$1
+		ORDER BY id ASC;
+	`)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, messageID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer rows.Close()
+
+	edits := make([]entity.ChatMessageEdit, 0)
+	for rows.Next() {
+		var edit entity.ChatMessageEdit
+		if err := rows.Scan(&edit.ID, &edit.ChatMessageID, &edit.Message, &edit.EditedAt); err != nil {
+			return nil, errors.NewInternalServerError("database error " + err.Error())
+		}
+		edits = append(edits, edit)
+	}
+
+	if err = rows.Err(); err != nil { // 끝난 후에도 에러체크 한번
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+
+	return edits, nil
+}
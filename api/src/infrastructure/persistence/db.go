@@ -1,59 +1,90 @@
 package persistence
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/logger"
 )
 
-const maxOpenDBConn = 10
-const maxIdleDBConn = 5
-const maxDBLifetime = 5 * time.Minute
+// DBPool sql.DB connection pool 설정
+type DBPool struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
 
 type Repositories struct {
 	db                 *sql.DB
+	Tx                 repository.TxManager
 	StudyPost          repository.StudyPostRepository
 	User               repository.UserRepository
 	TechStack          repository.TechStackRepository
 	StudyPostTechStack repository.StudyPostTechStackRepository
+	StudyPostMember    repository.StudyPostMemberRepository
 	Chat               repository.ChatRepository
+	ChatAttachment     repository.ChatAttachmentRepository
+	Notification       repository.NotificationRepository
+	UserBlock          repository.UserBlockRepository
+	ChatReport         repository.ChatReportRepository
 }
 
-func NewRepositories(driver, host, port, dbUser, password, dbName string) (*Repositories, error) {
+func NewRepositories(driver, host, port, dbUser, password, dbName string, pool DBPool) (*Repositories, error) {
 	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		host, port, dbUser, password, dbName)
 
 	db, err := sql.Open(driver, dsn)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	if err = db.Ping(); err != nil {
 		return nil, err
 	}
 
-	db.SetMaxOpenConns(maxOpenDBConn)
-	db.SetMaxIdleConns(maxIdleDBConn)
-	db.SetConnMaxLifetime(maxDBLifetime)
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
 
 	if err = db.Ping(); err != nil {
 		return nil, err
 	}
-	log.Println("db connected successfully")
+	logger.Info("db connected successfully", logger.F("host", host), logger.F("port", port), logger.F("db_name", dbName))
 
 	return &Repositories{
 		db:                 db,
+		Tx:                 NewTxManager(db),
 		StudyPost:          NewStudyPostRepo(db),
 		User:               NewUserRepository(db),
 		TechStack:          NewTechStackRepo(db),
 		StudyPostTechStack: NewStudyPostTechStackRepo(db),
+		StudyPostMember:    NewStudyPostMemberRepo(db),
 		Chat:               NewChatRepo(db),
+		ChatAttachment:     NewChatAttachmentRepo(db),
+		Notification:       NewNotificationRepo(db),
+		UserBlock:          NewUserBlockRepo(db),
+		ChatReport:         NewChatReportRepo(db),
 	}, nil
 }
 
+// Migrator 같은 DB 연결로 migration을 실행
+func (s *Repositories) Migrator() (*Migrator, error) {
+	return NewMigrator(s.db)
+}
+
+// DB: connection pool 통계 수집용 (metrics)
+func (s *Repositories) DB() *sql.DB {
+	return s.db
+}
+
+// Ping: readiness 확인
+func (s *Repositories) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
 func (s *Repositories) Close() error {
 	return s.db.Close()
 }
@@ -0,0 +1,114 @@
+package persistence
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+)
+
+// LocalBlobStore 첨부파일을 로컬 파일시스템의 rootDir 아래에 저장
+type LocalBlobStore struct {
+	rootDir string
+}
+
+var _ repository.BlobStore = &LocalBlobStore{}
+
+func NewLocalBlobStore(rootDir string) (*LocalBlobStore, error) {
+	absDir, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.MkdirAll(absDir, 0o750); err != nil {
+		return nil, err
+	}
+
+	return &LocalBlobStore{rootDir: absDir}, nil
+}
+
+// path key를 rootDir 밖으로 벗어나지 않는 파일 경로로 바꿈
+func (l *LocalBlobStore) path(key string) (string, *errors.RestErr) {
+	p := filepath.Join(l.rootDir, filepath.FromSlash(key))
+	if !strings.HasPrefix(p, l.rootDir+string(os.PathSeparator)) {
+		return "", errors.NewBadRequestError("invalid storage key")
+	}
+	return p, nil
+}
+
+func (l *LocalBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) *errors.RestErr {
+	p, restErr := l.path(key)
+	if restErr != nil {
+		return restErr
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return errors.NewInternalServerError("blob store error " + err.Error())
+	}
+
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if err != nil {
+		return errors.NewInternalServerError("blob store error " + err.Error())
+	}
+
+	written, err := io.Copy(f, io.LimitReader(&ctxReader{ctx: ctx, r: r}, size+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && written != size {
+		err = errors.NewError("size mismatch")
+	}
+	if err != nil {
+		os.Remove(p)
+		return errors.NewInternalServerError("blob store error " + err.Error())
+	}
+
+	return nil
+}
+
+func (l *LocalBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, *errors.RestErr) {
+	p, restErr := l.path(key)
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.NewNotFoundError("attachment file not found")
+		}
+		return nil, errors.NewInternalServerError("blob store error " + err.Error())
+	}
+
+	return f, nil
+}
+
+func (l *LocalBlobStore) Delete(ctx context.Context, key string) *errors.RestErr {
+	p, restErr := l.path(key)
+	if restErr != nil {
+		return restErr
+	}
+
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return errors.NewInternalServerError("blob store error " + err.Error())
+	}
+
+	return nil
+}
+
+// ctxReader ctx가 끝나면 ctx.Err()를 반환해서 업로드가 취소된 뒤에는 파일을 더 쓰지 않음
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *ctxReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
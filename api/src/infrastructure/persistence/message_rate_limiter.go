@@ -0,0 +1,47 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/logger"
+	"github.com/go-redis/redis/v8"
+)
+
+var _ repository.MessageRateLimiter = &MessageRateLimiter{}
+
+// MessageRateLimiter 유저마다 window 동안 limit개의 메시지만 허용 (redis fixed window, key에 window 번호를 넣어서 만료시간이 없는 key가 남지 않음)
+type MessageRateLimiter struct {
+	rClient *redis.Client
+	limit   int64
+	window  time.Duration
+}
+
+func NewMessageRateLimiter(rClient *redis.Client, limit int64, window time.Duration) *MessageRateLimiter {
+	return &MessageRateLimiter{
+		rClient: rClient,
+		limit:   limit,
+		window:  window,
+	}
+}
+
+func (ml *MessageRateLimiter) Allow(ctx context.Context, userID int64) (_ bool, restErr *errors.RestErr) {
+	ctx, span := startRedisSpan(ctx, "MessageRateLimiter.Allow")
+	defer endSpan(span, &restErr)
+
+	windowNumber := time.Now().UnixNano() / int64(ml.window)
+	key := fmt.Sprintf("chat_rate:%d:%d", userID, windowNumber)
+
+	pipe := ml.rClient.TxPipeline()
+	count := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, ml.window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Error("error when count chat messages in redis", logger.Err(err))
+		return false, errors.NewInternalServerError("redis error")
+	}
+
+	return count.Val() <= ml.limit, nil
+}
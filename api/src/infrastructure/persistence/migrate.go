@@ -0,0 +1,274 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/code-wave/go-wave/infrastructure/logger"
+)
+
+// migrationFiles {version}_{name}.up.sql / {version}_{name}.down.sql, version 순서대로 적용됨
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// 여러 인스턴스가 동시에 migration을 실행하지 않도록 잡는 pg advisory lock key
+const migrationLockKey = 72301830
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt string
+}
+
+// Migrator schema_migrations 테이블에 적용한 version을 기록하고, migration 하나를 transaction 하나로 적용함
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// loadMigrations 바이너리에 포함된 migration 파일을 version 순서로 읽음 (up, down이 모두 있어야 함)
+func loadMigrations() ([]Migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := migrationFiles.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d has different names", version)
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both up and down files", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Up 적용하지 않은 migration을 오래된 것부터 steps개 적용 (steps가 0이면 모두)
+func (m *Migrator) Up(steps int) error {
+	return m.withLock(func(conn *sql.Conn, applied map[int64]bool) error {
+		count := 0
+		for _, migration := range m.migrations {
+			if applied[migration.Version] {
+				continue
+			}
+			if steps > 0 && count >= steps {
+				break
+			}
+
+			logger.Info("migrate up", logger.F("version", migration.Version), logger.F("name", migration.Name))
+			if err := m.apply(conn, migration.Up, `INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, NOW());`,
+				migration.Version, migration.Name); err != nil {
+				return fmt.Errorf("migration %d_%s failed: %v", migration.Version, migration.Name, err)
+			}
+			count++
+		}
+
+		if count == 0 {
+			logger.Info("no migrations to apply")
+		}
+		return nil
+	})
+}
+
+// Down 적용한 migration을 최근 것부터 steps개 되돌림
+func (m *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive")
+	}
+
+	return m.withLock(func(conn *sql.Conn, applied map[int64]bool) error {
+		count := 0
+		for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+			migration := m.migrations[i]
+			if !applied[migration.Version] {
+				continue
+			}
+
+			logger.Info("migrate down", logger.F("version", migration.Version), logger.F("name", migration.Name))
+			if err := m.apply(conn, migration.Down, `DELETE FROM schema_migrations WHERE version=$1;`, migration.Version); err != nil {
+				return fmt.Errorf("migration %d_%s failed: %v", migration.Version, migration.Name, err)
+			}
+			count++
+		}
+		return nil
+	})
+}
+
+// Force migration을 실행하지 않고 version까지 적용한 것으로 기록 (initdb.sh로 만든 기존 DB에서 시작할 때)
+func (m *Migrator) Force(version int64) error {
+	return m.withLock(func(conn *sql.Conn, applied map[int64]bool) error {
+		for _, migration := range m.migrations {
+			if migration.Version > version || applied[migration.Version] {
+				continue
+			}
+
+			if _, err := conn.ExecContext(context.Background(), `INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, NOW());`,
+				migration.Version, migration.Name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+
+	err := m.withLock(func(conn *sql.Conn, applied map[int64]bool) error {
+		appliedAt := make(map[int64]string)
+
+		rows, err := conn.QueryContext(context.Background(), `SELECT version, applied_at FROM schema_migrations;`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var version int64
+			var at string
+			if err := rows.Scan(&version, &at); err != nil {
+				return err
+			}
+			appliedAt[version] = at
+		}
+
+		for _, migration := range m.migrations {
+			statuses = append(statuses, MigrationStatus{
+				Migration: migration,
+				Applied:   applied[migration.Version],
+				AppliedAt: appliedAt[migration.Version],
+			})
+		}
+		return rows.Err()
+	})
+
+	return statuses, err
+}
+
+// apply migration SQL과 schema_migrations 기록을 한 transaction으로 실행
+func (m *Migrator) apply(conn *sql.Conn, migrationSQL, recordSQL string, recordArgs ...interface{}) error {
+	ctx := context.Background()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // commit된 후에는 아무것도 하지 않음
+
+	if _, err := tx.ExecContext(ctx, migrationSQL); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, recordSQL, recordArgs...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// withLock advisory lock을 잡은 연결 하나에서 schema_migrations를 만들고 적용된 version을 읽은 후 fn 실행
+func (m *Migrator) withLock(fn func(conn *sql.Conn, applied map[int64]bool) error) error {
+	ctx := context.Background()
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1);`, migrationLockKey); err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1);`, migrationLockKey)
+
+	_, err = conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version bigint NOT NULL,
+			name varchar(255) NOT NULL,
+			applied_at timestamp NOT NULL,
+			PRIMARY KEY (version)
+		);
+	`)
+	if err != nil {
+		return err
+	}
+
+	rows, err := conn.QueryContext(ctx, `SELECT version FROM schema_migrations;`)
+	if err != nil {
+		return err
+	}
+
+	applied := make(map[int64]bool)
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			rows.Close()
+			return err
+		}
+		applied[version] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return fn(conn, applied)
+}
@@ -0,0 +1,23 @@
+package persistence
+
+import "testing"
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(migrations) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+
+	for i, migration := range migrations {
+		if migration.Version != int64(i+1) {
+			t.Errorf("migration %d_%s: versions should be sequential, want %d", migration.Version, migration.Name, i+1)
+		}
+		if migration.Up == "" || migration.Down == "" {
+			t.Errorf("migration %d_%s: up and down are required", migration.Version, migration.Name)
+		}
+	}
+}
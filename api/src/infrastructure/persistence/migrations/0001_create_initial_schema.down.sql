@@ -0,0 +1,6 @@
+drop table if exists chat_message;
+drop table if exists chat_room;
+drop table if exists study_post_tech_stack;
+drop table if exists tech_stack;
+drop table if exists study_post;
+drop table if exists users;
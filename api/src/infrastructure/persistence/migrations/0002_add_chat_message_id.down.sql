@@ -0,0 +1,2 @@
+drop index if exists chat_message_chat_room_id_idx;
+alter table chat_message drop column if exists id;
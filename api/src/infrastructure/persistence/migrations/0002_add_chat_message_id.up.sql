@@ -0,0 +1,5 @@
+-- 이미 있는 메시지에도 id가 채워짐
+alter table chat_message add column id serial NOT NULL;
+alter table chat_message add PRIMARY KEY (id);
+
+create index chat_message_chat_room_id_idx on chat_message (chat_room_id, id);
@@ -0,0 +1,9 @@
+create table chat_read_marker (
+    chat_room_id bigint NOT NULL,
+    user_id bigint NOT NULL,
+    last_read_message_id bigint NOT NULL,
+    updated_at timestamp NOT NULL,
+    PRIMARY KEY (chat_room_id, user_id),
+    FOREIGN KEY (chat_room_id) REFERENCES chat_room (id),
+    FOREIGN KEY (user_id) REFERENCES users (id)
+);
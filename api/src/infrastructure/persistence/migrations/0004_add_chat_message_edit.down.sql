@@ -0,0 +1,3 @@
+drop table if exists chat_message_edit;
+alter table chat_message drop column if exists deleted_at;
+alter table chat_message drop column if exists edited_at;
@@ -0,0 +1,11 @@
+alter table chat_message add column edited_at timestamp;
+alter table chat_message add column deleted_at timestamp;
+
+create table chat_message_edit (
+    id serial NOT NULL,
+    chat_message_id bigint NOT NULL,
+    message text NOT NULL,
+    edited_at timestamp NOT NULL,
+    PRIMARY KEY (id),
+    FOREIGN KEY (chat_message_id) REFERENCES chat_message (id)
+);
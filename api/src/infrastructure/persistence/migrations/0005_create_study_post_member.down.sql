@@ -0,0 +1,4 @@
+drop table if exists chat_room_participant;
+drop index if exists chat_room_group_study_post_id_idx;
+alter table chat_room drop column if exists room_type;
+drop table if exists study_post_member;
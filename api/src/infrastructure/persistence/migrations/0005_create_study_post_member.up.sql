@@ -0,0 +1,22 @@
+create table study_post_member (
+    study_post_id bigint NOT NULL,
+    user_id bigint NOT NULL,
+    joined_at timestamp NOT NULL,
+    PRIMARY KEY (study_post_id, user_id),
+    FOREIGN KEY (study_post_id) REFERENCES study_post (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+-- 이미 있는 채팅룸은 1:1 채팅룸 (참여자는 client_id, host_id)
+alter table chat_room add column room_type varchar(16) NOT NULL DEFAULT 'direct';
+
+create unique index chat_room_group_study_post_id_idx on chat_room (study_post_id) WHERE room_type='group';
+
+create table chat_room_participant (
+    chat_room_id bigint NOT NULL,
+    user_id bigint NOT NULL,
+    joined_at timestamp NOT NULL,
+    PRIMARY KEY (chat_room_id, user_id),
+    FOREIGN KEY (chat_room_id) REFERENCES chat_room (id),
+    FOREIGN KEY (user_id) REFERENCES users (id)
+);
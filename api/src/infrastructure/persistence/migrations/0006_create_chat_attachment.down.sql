@@ -0,0 +1,3 @@
+drop index if exists chat_message_attachment_id_idx;
+alter table chat_message drop column if exists attachment_id;
+drop table if exists chat_attachment;
@@ -0,0 +1,17 @@
+create table chat_attachment (
+    id serial NOT NULL,
+    chat_room_id bigint NOT NULL,
+    uploader_id bigint NOT NULL,
+    file_name varchar(255) NOT NULL,
+    content_type varchar(128) NOT NULL,
+    size bigint NOT NULL,
+    storage_key varchar(255) NOT NULL UNIQUE,
+    created_at timestamp NOT NULL,
+    PRIMARY KEY (id),
+    FOREIGN KEY (chat_room_id) REFERENCES chat_room (id),
+    FOREIGN KEY (uploader_id) REFERENCES users (id)
+);
+
+alter table chat_message add column attachment_id bigint REFERENCES chat_attachment (id);
+
+create unique index chat_message_attachment_id_idx on chat_message (attachment_id) WHERE attachment_id IS NOT NULL;
@@ -0,0 +1,2 @@
+drop index if exists chat_message_client_message_id_idx;
+alter table chat_message drop column if exists client_message_id;
@@ -0,0 +1,3 @@
+alter table chat_message add column client_message_id varchar(64);
+
+create unique index chat_message_client_message_id_idx on chat_message (chat_room_id, sender_id, client_message_id) WHERE client_message_id IS NOT NULL;
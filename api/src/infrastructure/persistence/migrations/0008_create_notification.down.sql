@@ -0,0 +1,3 @@
+drop table if exists push_subscription;
+drop table if exists notification_preference;
+drop table if exists notification;
@@ -0,0 +1,39 @@
+create table notification (
+    id serial NOT NULL,
+    user_id bigint NOT NULL,
+    type varchar(48) NOT NULL,
+    chat_room_id bigint NOT NULL,
+    chat_message_id bigint NOT NULL,
+    sender_name varchar(48) NOT NULL,
+    message varchar(255) NOT NULL,
+    read_at timestamp,
+    emailed_at timestamp,
+    created_at timestamp NOT NULL,
+    PRIMARY KEY (id),
+    FOREIGN KEY (user_id) REFERENCES users (id),
+    FOREIGN KEY (chat_room_id) REFERENCES chat_room (id),
+    FOREIGN KEY (chat_message_id) REFERENCES chat_message (id)
+);
+
+create index notification_user_id_idx on notification (user_id, id);
+
+create table notification_preference (
+    user_id bigint NOT NULL,
+    in_app boolean NOT NULL,
+    email_digest boolean NOT NULL,
+    web_push boolean NOT NULL,
+    updated_at timestamp NOT NULL,
+    PRIMARY KEY (user_id),
+    FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+create table push_subscription (
+    id serial NOT NULL,
+    user_id bigint NOT NULL,
+    endpoint text NOT NULL UNIQUE,
+    p256dh varchar(255) NOT NULL,
+    auth varchar(255) NOT NULL,
+    created_at timestamp NOT NULL,
+    PRIMARY KEY (id),
+    FOREIGN KEY (user_id) REFERENCES users (id)
+);
@@ -0,0 +1,2 @@
+drop table if exists chat_message_report;
+drop table if exists user_block;
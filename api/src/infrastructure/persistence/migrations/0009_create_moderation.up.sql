@@ -0,0 +1,26 @@
+create table user_block (
+    blocker_id bigint NOT NULL,
+    blocked_id bigint NOT NULL,
+    created_at timestamp NOT NULL,
+    PRIMARY KEY (blocker_id, blocked_id),
+    FOREIGN KEY (blocker_id) REFERENCES users (id),
+    FOREIGN KEY (blocked_id) REFERENCES users (id)
+);
+
+create table chat_message_report (
+    id serial NOT NULL,
+    chat_message_id bigint NOT NULL,
+    reporter_id bigint NOT NULL,
+    reason varchar(500) NOT NULL,
+    status varchar(16) NOT NULL,
+    reviewed_by bigint,
+    reviewed_at timestamp,
+    created_at timestamp NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (chat_message_id, reporter_id),
+    FOREIGN KEY (chat_message_id) REFERENCES chat_message (id),
+    FOREIGN KEY (reporter_id) REFERENCES users (id),
+    FOREIGN KEY (reviewed_by) REFERENCES users (id)
+);
+
+create index chat_message_report_status_idx on chat_message_report (status, id);
@@ -0,0 +1,42 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/logger"
+	"github.com/go-redis/redis/v8"
+)
+
+var _ repository.NotificationRateLimiter = &NotificationLimiter{}
+
+// NotificationLimiter 유저마다 채팅룸의 알림을 window에 한 번만 허용 (redis SET NX + 만료시간)
+type NotificationLimiter struct {
+	rClient *redis.Client
+	window  time.Duration
+}
+
+func NewNotificationLimiter(rClient *redis.Client, window time.Duration) *NotificationLimiter {
+	return &NotificationLimiter{
+		rClient: rClient,
+		window:  window,
+	}
+}
+
+func (nl *NotificationLimiter) Allow(ctx context.Context, userID, chatRoomID int64) (_ bool, restErr *errors.RestErr) {
+	ctx, span := startRedisSpan(ctx, "NotificationLimiter.Allow")
+	defer endSpan(span, &restErr)
+
+	key := fmt.Sprintf("notification_limit:%d:%d", userID, chatRoomID)
+
+	ok, err := nl.rClient.SetNX(ctx, key, 1, nl.window).Result()
+	if err != nil {
+		logger.Error("error when set notification limit in redis", logger.Err(err))
+		return false, errors.NewInternalServerError("redis error")
+	}
+
+	return ok, nil
+}
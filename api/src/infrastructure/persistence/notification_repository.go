@@ -0,0 +1,313 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/helpers"
+)
+
+// 알림에 남기는 메시지 미리보기 길이
+const notificationPreviewLength = 200
+
+type notificationRepo struct {
+	db *sql.DB
+}
+
+func NewNotificationRepo(db *sql.DB) *notificationRepo {
+	return &notificationRepo{db}
+}
+
+var _ repository.NotificationRepository = &notificationRepo{}
+
+func (n *notificationRepo) SaveNotification(ctx context.Context, notification *entity.Notification) (_ *entity.Notification, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "notificationRepo.SaveNotification")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, n.db).PrepareContext(ctx, `
+		INSERT INTO notification (user_id, type, chat_room_id, chat_message_id, sender_name, message, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, user_id, type, chat_room_id, chat_message_id, sender_name, message, read_at IS NOT NULL, created_at;
+	`)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	preview := []rune(notification.Message)
+	if len(preview) > notificationPreviewLength {
+		preview = preview[:notificationPreviewLength]
+	}
+
+	var newNotification entity.Notification
+	err = stmt.QueryRowContext(ctx, notification.UserID, notification.Type, notification.ChatRoomID, notification.ChatMessageID, notification.SenderName, string(preview),
+		helpers.GetCurrentTimeForDB()).
+		Scan(notificationFields(&newNotification)...)
+	if err != nil {
+		return nil, errors.NewInternalServerError("queryrow error " + err.Error())
+	}
+
+	return &newNotification, nil
+}
+
+func notificationFields(n *entity.Notification) []interface{} {
+	return []interface{}{&n.ID, &n.UserID, &n.Type, &n.ChatRoomID, &n.ChatMessageID, &n.SenderName, &n.Message, &n.Read, &n.CreatedAt}
+}
+
+// GetNotifications 최근 알림부터 limit개
+func (n *notificationRepo) GetNotifications(ctx context.Context, userID, limit int64) (_ []entity.Notification, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "notificationRepo.GetNotifications")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, n.db).PrepareContext(ctx, `
+		SELECT id, user_id, type, chat_room_id, chat_message_id, sender_name, message, read_at IS NOT NULL, created_at
+		FROM notification
+		WHERE user_id=$1
+		ORDER BY id DESC
+		LIMIT $2;
+	`)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, userID, limit)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer rows.Close()
+
+	var notifications []entity.Notification
+	for rows.Next() {
+		var notification entity.Notification
+		if err := rows.Scan(notificationFields(&notification)...); err != nil {
+			return nil, errors.NewInternalServerError("database error " + err.Error())
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, nil
+}
+
+// MarkNotificationsRead lastNotificationID까지의 알림을 읽음으로 표시
+func (n *notificationRepo) MarkNotificationsRead(ctx context.Context, userID, lastNotificationID int64) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "notificationRepo.MarkNotificationsRead")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, n.db).PrepareContext(ctx, `
+		UPDATE notification
+		SET read_at=$3
+		WHERE user_id=$1 AND id<=$2 AND read_at IS NULL;
+	`)
+	if err != nil {
+		return errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	if _, err = stmt.ExecContext(ctx, userID, lastNotificationID, helpers.GetCurrentTimeForDB()); err != nil {
+		return errors.NewInternalServerError("execute error " + err.Error())
+	}
+
+	return nil
+}
+
+// GetPendingDigests 이메일 요약을 받는 유저들의 읽지 않았고 아직 이메일로 보내지 않은 알림
+func (n *notificationRepo) GetPendingDigests(ctx context.Context) (_ []entity.NotificationDigest, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "notificationRepo.GetPendingDigests")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, n.db).PrepareContext(ctx, `
+		SELECT n.id, n.user_id, n.type, n.chat_room_id, n.chat_message_id, n.sender_name, n.message, n.read_at IS NOT NULL, n.created_at,
+		       u.email, u.nickname
+		FROM notification n
+		JOIN users u ON u.id=n.user_id
+		JOIN notification_preference p ON p.user_id=n.user_id AND p.email_digest
+		WHERE n.read_at IS NULL AND n.emailed_at IS NULL
+		ORDER BY n.user_id, n.id;
+	`)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer rows.Close()
+
+	var digests []entity.NotificationDigest
+	for rows.Next() {
+		var notification entity.Notification
+		var email, nickname string
+
+		if err := rows.Scan(append(notificationFields(&notification), &email, &nickname)...); err != nil {
+			return nil, errors.NewInternalServerError("database error " + err.Error())
+		}
+
+		if len(digests) == 0 || digests[len(digests)-1].UserID != notification.UserID {
+			digests = append(digests, entity.NotificationDigest{
+				UserID:   notification.UserID,
+				Email:    email,
+				Nickname: nickname,
+			})
+		}
+		last := &digests[len(digests)-1]
+		last.Notifications = append(last.Notifications, notification)
+	}
+
+	return digests, nil
+}
+
+func (n *notificationRepo) MarkNotificationsEmailed(ctx context.Context, userID, lastNotificationID int64) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "notificationRepo.MarkNotificationsEmailed")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, n.db).PrepareContext(ctx, `
+		UPDATE notification
+		SET emailed_at=$3
+		WHERE user_id=$1 AND id<=$2 AND emailed_at IS NULL;
+	`)
+	if err != nil {
+		return errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	if _, err = stmt.ExecContext(ctx, userID, lastNotificationID, helpers.GetCurrentTimeForDB()); err != nil {
+		return errors.NewInternalServerError("execute error " + err.Error())
+	}
+
+	return nil
+}
+
+// GetPreference 저장된 설정이 없으면 기본 설정
+func (n *notificationRepo) GetPreference(ctx context.Context, userID int64) (_ *entity.NotificationPreference, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "notificationRepo.GetPreference")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, n.db).PrepareContext(ctx, `
+		SELECT user_id, in_app, email_digest, web_push
+		FROM notification_preference
+		WHERE user_id=$1;
+	`)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	var preference entity.NotificationPreference
+	err = stmt.QueryRowContext(ctx, userID).Scan(&preference.UserID, &preference.InApp, &preference.EmailDigest, &preference.WebPush)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return entity.DefaultNotificationPreference(userID), nil
+		}
+		return nil, errors.NewInternalServerError("queryrow error " + err.Error())
+	}
+
+	return &preference, nil
+}
+
+func (n *notificationRepo) SavePreference(ctx context.Context, preference *entity.NotificationPreference) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "notificationRepo.SavePreference")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, n.db).PrepareContext(ctx, `
+		INSERT INTO notification_preference (user_id, in_app, email_digest, web_push, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET in_app=EXCLUDED.in_app, email_digest=EXCLUDED.email_digest, web_push=EXCLUDED.web_push, updated_at=EXCLUDED.updated_at;
+	`)
+	if err != nil {
+		return errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, preference.UserID, preference.InApp, preference.EmailDigest, preference.WebPush, helpers.GetCurrentTimeForDB())
+	if err != nil {
+		return errors.NewInternalServerError("execute error " + err.Error())
+	}
+
+	return nil
+}
+
+// SavePushSubscription 같은 endpoint면 구독 정보를 갱신 (브라우저가 다른 유저로 로그인한 경우 포함)
+func (n *notificationRepo) SavePushSubscription(ctx context.Context, subscription *entity.PushSubscription) (_ *entity.PushSubscription, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "notificationRepo.SavePushSubscription")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, n.db).PrepareContext(ctx, `
+		INSERT INTO push_subscription (user_id, endpoint, p256dh, auth, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (endpoint) DO UPDATE
+		SET user_id=EXCLUDED.user_id, p256dh=EXCLUDED.p256dh, auth=EXCLUDED.auth
+		RETURNING id, user_id, endpoint, p256dh, auth, created_at;
+	`)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	var newSubscription entity.PushSubscription
+	err = stmt.QueryRowContext(ctx, subscription.UserID, subscription.Endpoint, subscription.P256dh, subscription.Auth, helpers.GetCurrentTimeForDB()).
+		Scan(&newSubscription.ID, &newSubscription.UserID, &newSubscription.Endpoint, &newSubscription.P256dh, &newSubscription.Auth, &newSubscription.CreatedAt)
+	if err != nil {
+		return nil, errors.NewInternalServerError("queryrow error " + err.Error())
+	}
+
+	return &newSubscription, nil
+}
+
+func (n *notificationRepo) GetPushSubscriptions(ctx context.Context, userID int64) (_ []entity.PushSubscription, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "notificationRepo.GetPushSubscriptions")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, n.db).PrepareContext(ctx, `
+		SELECT id, user_id, endpoint, p256dh, auth, created_at
+		FROM push_subscription
+		WHERE user_id=$1;
+	`)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, userID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer rows.Close()
+
+	var subscriptions []entity.PushSubscription
+	for rows.Next() {
+		var s entity.PushSubscription
+		if err := rows.Scan(&s.ID, &s.UserID, &s.Endpoint, &s.P256dh, &s.Auth, &s.CreatedAt); err != nil {
+			return nil, errors.NewInternalServerError("database error " + err.Error())
+		}
+		subscriptions = append(subscriptions, s)
+	}
+
+	return subscriptions, nil
+}
+
+func (n *notificationRepo) DeletePushSubscription(ctx context.Context, userID int64, endpoint string) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "notificationRepo.DeletePushSubscription")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, n.db).PrepareContext(ctx, `
+		DELETE FROM push_subscription
+		WHERE user_id=$1 AND endpoint=$2;
+	`)
+	if err != nil {
+		return errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	if _, err = stmt.ExecContext(ctx, userID, endpoint); err != nil {
+		return errors.NewInternalServerError("execute error " + err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/helpers"
+	"github.com/code-wave/go-wave/infrastructure/logger"
+	"github.com/go-redis/redis/v8"
+)
+
+var _ repository.PresenceRepository = &PresenceRepo{}
+
+// PresenceRepo 유저의 websocket 연결들을 redis sorted set(presence:{userID})에 만료시간을 score로 저장
+// 연결이 하나라도 만료되지 않았으면 online, heartbeat마다 만료시간을 갱신함
+// 채팅룸별 연결은 presence:{userID}:room:{roomID}에 같은 방식으로 저장함
+type PresenceRepo struct {
+	rClient *redis.Client
+}
+
+func NewPresenceRepository(rClient *redis.Client) *PresenceRepo {
+	return &PresenceRepo{
+		rClient: rClient,
+	}
+}
+
+func presenceKey(userID int64) string {
+	return fmt.Sprintf("presence:%d", userID)
+}
+
+func roomPresenceKey(userID, roomID int64) string {
+	return fmt.Sprintf("presence:%d:room:%d", userID, roomID)
+}
+
+func lastSeenKey(userID int64) string {
+	return fmt.Sprintf("last_seen:%d", userID)
+}
+
+// Connect 연결을 추가하고 이전에 offline이었는지(= 상태가 바뀌었는지) 반환
+func (pr *PresenceRepo) Connect(ctx context.Context, userID int64, connID string, roomIDs []int64, ttl time.Duration) (_ bool, restErr *errors.RestErr) {
+	ctx, span := startRedisSpan(ctx, "PresenceRepo.Connect")
+	defer endSpan(span, &restErr)
+
+	wasOnline, err := pr.isOnline(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := pr.Refresh(ctx, userID, connID, roomIDs, ttl); err != nil {
+		return false, err
+	}
+
+	return !wasOnline, nil
+}
+
+// Refresh 연결의 만료시간을 now + ttl로 갱신
+func (pr *PresenceRepo) Refresh(ctx context.Context, userID int64, connID string, roomIDs []int64, ttl time.Duration) (restErr *errors.RestErr) {
+	ctx, span := startRedisSpan(ctx, "PresenceRepo.Refresh")
+	defer endSpan(span, &restErr)
+
+	keys := []string{presenceKey(userID)}
+	for _, roomID := range roomIDs {
+		keys = append(keys, roomPresenceKey(userID, roomID))
+	}
+	expiresAt := time.Now().Add(ttl).Unix()
+
+	pipe := pr.rClient.TxPipeline()
+	for _, key := range keys {
+		pipe.ZAdd(ctx, key, &redis.Z{Score: float64(expiresAt), Member: connID})
+		pipe.Expire(ctx, key, ttl) // 서버가 죽어서 Disconnect가 호출되지 않아도 key가 남지 않도록
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Error("error when refresh presence in redis", logger.Err(err))
+		return errors.NewInternalServerError("redis error")
+	}
+
+	return nil
+}
+
+// Disconnect 연결을 제거하고 마지막 연결이었으면 last_seen을 저장, offline이 되었는지 반환
+func (pr *PresenceRepo) Disconnect(ctx context.Context, userID int64, connID string, roomIDs []int64) (_ bool, restErr *errors.RestErr) {
+	ctx, span := startRedisSpan(ctx, "PresenceRepo.Disconnect")
+	defer endSpan(span, &restErr)
+
+	pipe := pr.rClient.TxPipeline()
+	pipe.ZRem(ctx, presenceKey(userID), connID)
+	for _, roomID := range roomIDs {
+		pipe.ZRem(ctx, roomPresenceKey(userID, roomID), connID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Error("error when remove presence in redis", logger.Err(err))
+		return false, errors.NewInternalServerError("redis error")
+	}
+
+	online, restErr := pr.isOnline(ctx, userID)
+	if restErr != nil {
+		return false, restErr
+	}
+	if online { // 다른 연결이 남아있음
+		return false, nil
+	}
+
+	lastSeen := helpers.GetDateString(time.Now())
+	if err := pr.rClient.Set(ctx, lastSeenKey(userID), lastSeen, 0).Err(); err != nil {
+		logger.Error("error when save last seen in redis", logger.Err(err))
+		return false, errors.NewInternalServerError("redis error")
+	}
+
+	return true, nil
+}
+
+func (pr *PresenceRepo) GetPresence(ctx context.Context, userID int64) (_ *entity.Presence, restErr *errors.RestErr) {
+	ctx, span := startRedisSpan(ctx, "PresenceRepo.GetPresence")
+	defer endSpan(span, &restErr)
+
+	online, restErr := pr.isOnline(ctx, userID)
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	presence := &entity.Presence{
+		UserID: userID,
+		Online: online,
+	}
+	if online {
+		return presence, nil
+	}
+
+	lastSeen, err := pr.rClient.Get(ctx, lastSeenKey(userID)).Result()
+	if err != nil && err != redis.Nil {
+		logger.Error("error when get last seen in redis", logger.Err(err))
+		return nil, errors.NewInternalServerError("redis error")
+	}
+	presence.LastSeen = lastSeen
+
+	return presence, nil
+}
+
+// GetConnections 만료되지 않은 연결 ID들
+func (pr *PresenceRepo) GetConnections(ctx context.Context, userID int64) (_ []string, restErr *errors.RestErr) {
+	ctx, span := startRedisSpan(ctx, "PresenceRepo.GetConnections")
+	defer endSpan(span, &restErr)
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	connIDs, err := pr.rClient.ZRangeByScore(ctx, presenceKey(userID), &redis.ZRangeBy{Min: now, Max: "+inf"}).Result()
+	if err != nil {
+		logger.Error("error when get connections in redis", logger.Err(err))
+		return nil, errors.NewInternalServerError("redis error")
+	}
+
+	return connIDs, nil
+}
+
+// IsConnectedToRoom 유저가 만료되지 않은 연결로 채팅룸에 접속해 있는지 확인
+func (pr *PresenceRepo) IsConnectedToRoom(ctx context.Context, userID, roomID int64) (_ bool, restErr *errors.RestErr) {
+	ctx, span := startRedisSpan(ctx, "PresenceRepo.IsConnectedToRoom")
+	defer endSpan(span, &restErr)
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	count, err := pr.rClient.ZCount(ctx, roomPresenceKey(userID, roomID), now, "+inf").Result()
+	if err != nil {
+		logger.Error("error when get room presence in redis", logger.Err(err))
+		return false, errors.NewInternalServerError("redis error")
+	}
+
+	return count > 0, nil
+}
+
+// isOnline 만료되지 않은 연결이 있는지 확인 (만료된 연결은 같이 정리함)
+func (pr *PresenceRepo) isOnline(ctx context.Context, userID int64) (bool, *errors.RestErr) {
+	key := presenceKey(userID)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	pipe := pr.rClient.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", "("+now)
+	count := pipe.ZCard(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Error("error when get presence in redis", logger.Err(err))
+		return false, errors.NewInternalServerError("redis error")
+	}
+
+	return count.Val() > 0, nil
+}
@@ -2,15 +2,16 @@ package persistence
 
 import (
 	"context"
-	"log"
 
 	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/logger"
 	"github.com/go-redis/redis/v8"
 )
 
 type RedisService struct {
-	Auth    repository.AuthRepository
-	RClient *redis.Client
+	Auth     repository.AuthRepository
+	Presence repository.PresenceRepository
+	RClient  *redis.Client
 }
 
 func NewRedisDB(host, port, password string) (*RedisService, error) {
@@ -21,14 +22,25 @@ func NewRedisDB(host, port, password string) (*RedisService, error) {
 	})
 
 	if err := rClient.Ping(context.Background()).Err(); err != nil {
-		log.Println("redis server doesn't connect")
+		logger.Error("redis server doesn't connect", logger.Err(err), logger.F("addr", host+":"+port))
 		return nil, err
 	}
 
-	log.Println("redis connected successfully")
+	logger.Info("redis connected successfully", logger.F("addr", host+":"+port))
 
 	return &RedisService{
-		Auth:    NewAuthRepository(rClient),
-		RClient: rClient,
+		Auth:     NewAuthRepository(rClient),
+		Presence: NewPresenceRepository(rClient),
+		RClient:  rClient,
 	}, nil
 }
+
+// Ping: readiness 확인
+func (r *RedisService) Ping(ctx context.Context) error {
+	return r.RClient.Ping(ctx).Err()
+}
+
+// Close: redis 연결을 닫음 (구독 중인 연결도 끊김)
+func (r *RedisService) Close() error {
+	return r.RClient.Close()
+}
@@ -0,0 +1,75 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/domain/repository/repositorytest"
+	"github.com/code-wave/go-wave/utils/config"
+	"github.com/go-redis/redis/v8"
+)
+
+func TestPostgresRepositories(t *testing.T) {
+	db := openTestDB(t)
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = migrator.Up(0); err != nil {
+		t.Fatal(err)
+	}
+
+	repositorytest.Run(t, repositorytest.Repositories{
+		Tx:                 NewTxManager(db),
+		User:               NewUserRepository(db),
+		StudyPost:          NewStudyPostRepo(db),
+		TechStack:          NewTechStackRepo(db),
+		StudyPostTechStack: NewStudyPostTechStackRepo(db),
+		StudyPostMember:    NewStudyPostMemberRepo(db),
+		Chat:               NewChatRepo(db),
+		ChatAttachment:     NewChatAttachmentRepo(db),
+		Notification:       NewNotificationRepo(db),
+		UserBlock:          NewUserBlockRepo(db),
+		ChatReport:         NewChatReportRepo(db),
+	})
+}
+
+func TestRedisRepositories(t *testing.T) {
+	cfg, _, err := config.Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rClient := redis.NewClient(&redis.Options{
+		Addr:     cfg.Redis.Host + ":" + cfg.Redis.Port,
+		Password: cfg.Redis.Password,
+	})
+	t.Cleanup(func() { rClient.Close() })
+
+	if err = rClient.Ping(context.Background()).Err(); err != nil {
+		t.Skip("redis is not available: ", err.Error())
+	}
+
+	repositorytest.Run(t, repositorytest.Repositories{
+		Auth:     NewAuthRepository(rClient),
+		Presence: NewPresenceRepository(rClient),
+		NewMessageRateLimiter: func(limit int64, window time.Duration) repository.MessageRateLimiter {
+			return NewMessageRateLimiter(rClient, limit, window)
+		},
+		NewNotificationLimiter: func(window time.Duration) repository.NotificationRateLimiter {
+			return NewNotificationLimiter(rClient, window)
+		},
+	})
+}
+
+func TestLocalBlobStore(t *testing.T) {
+	blobStore, err := NewLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repositorytest.Run(t, repositorytest.Repositories{BlobStore: blobStore})
+}
@@ -0,0 +1,119 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/helpers"
+)
+
+type studyPostMemberRepo struct {
+	db *sql.DB
+}
+
+func NewStudyPostMemberRepo(db *sql.DB) *studyPostMemberRepo {
+	return &studyPostMemberRepo{db}
+}
+
+var _ repository.StudyPostMemberRepository = &studyPostMemberRepo{}
+
+func (s *studyPostMemberRepo) SaveMember(ctx context.Context, member *entity.StudyPostMember) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "studyPostMemberRepo.SaveMember")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, s.db).PrepareContext(ctx, `
+		INSERT INTO study_post_member (study_post_id, user_id, joined_at)
+		VALUES ($1, $2, $3);
+	`)
+	if err != nil {
+		return errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	now := helpers.GetCurrentTimeForDB()
+
+	_, err = stmt.ExecContext(ctx, member.StudyPostID, member.UserID, now)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate") {
+			return errors.NewBadRequestError("user is already a member of the study post")
+		}
+		return errors.NewInternalServerError("execute error " + err.Error())
+	}
+
+	member.JoinedAt = now
+
+	return nil
+}
+
+func (s *studyPostMemberRepo) DeleteMember(ctx context.Context, studyPostID, userID int64) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "studyPostMemberRepo.DeleteMember")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, s.db).PrepareContext(ctx, `
+		DELETE FROM study_post_member
+		WHERE study_post_id=$1 AND user_id=$2;
+	`)
+	if err != nil {
+		return errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, studyPostID, userID)
+	if err != nil {
+		return errors.NewInternalServerError("execute error " + err.Error())
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewInternalServerError("database error " + err.Error())
+	}
+
+	if n == 0 {
+		return errors.NewBadRequestError("no rows to be deleted")
+	}
+
+	return nil
+}
+
+// GetMembers 게시글의 팀원들을 수락된 순서대로 반환
+func (s *studyPostMemberRepo) GetMembers(ctx context.Context, studyPostID int64) (_ entity.StudyPostMembers, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "studyPostMemberRepo.GetMembers")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, s.db).PrepareContext(ctx, `
+		SELECT m.study_post_id, m.user_id, u.nickname, m.joined_at
+		FROM study_post_member m
+		JOIN users u ON u.id=m.user_id
+		WHERE m.study_post_id=$1
+		ORDER BY m.joined_at ASC;
+	`)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, studyPostID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer rows.Close()
+
+	members := make(entity.StudyPostMembers, 0)
+	for rows.Next() {
+		var member entity.StudyPostMember
+		if err := rows.Scan(&member.StudyPostID, &member.UserID, &member.Nickname, &member.JoinedAt); err != nil {
+			return nil, errors.NewInternalServerError("database error " + err.Error())
+		}
+		members = append(members, member)
+	}
+
+	if err = rows.Err(); err != nil { // 끝난 후에도 에러체크 한번
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+
+	return members, nil
+}
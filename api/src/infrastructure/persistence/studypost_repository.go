@@ -1,6 +1,7 @@
 package persistence
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/code-wave/go-wave/domain/entity"
@@ -20,8 +21,11 @@ func NewStudyPostRepo(db *sql.DB) *studyPostRepo {
 
 var _ repository.StudyPostRepository = &studyPostRepo{}
 
-func (s *studyPostRepo) SavePost(studyPost *entity.StudyPost) (*entity.StudyPost, *errors.RestErr) {
-	stmt, err := s.db.Prepare(`
+func (s *studyPostRepo) SavePost(ctx context.Context, studyPost *entity.StudyPost) (_ *entity.StudyPost, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "studyPostRepo.SavePost")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, s.db).PrepareContext(ctx, `
 		INSERT INTO study_post (user_id, title, topic, content, num_of_members, is_mentor, price, start_date, end_date, is_online, tech_stack, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		RETURNING id;
@@ -32,7 +36,7 @@ func (s *studyPostRepo) SavePost(studyPost *entity.StudyPost) (*entity.StudyPost
 
 	currentTime := helpers.GetCurrentTimeForDB()
 
-	row := stmt.QueryRow(studyPost.UserID, studyPost.Title, studyPost.Topic, studyPost.Content,
+	row := stmt.QueryRowContext(ctx, studyPost.UserID, studyPost.Title, studyPost.Topic, studyPost.Content,
 		studyPost.NumOfMembers, studyPost.IsMentor, studyPost.Price, studyPost.StartDate, studyPost.EndDate,
 		studyPost.IsOnline, studyPost.TechStack, currentTime, currentTime)
 
@@ -48,8 +52,11 @@ func (s *studyPostRepo) SavePost(studyPost *entity.StudyPost) (*entity.StudyPost
 	return studyPost, nil
 }
 
-func (s *studyPostRepo) GetPost(id int64) (*entity.StudyPost, *errors.RestErr) {
-	stmt, err := s.db.Prepare(`
+func (s *studyPostRepo) GetPost(ctx context.Context, id int64) (_ *entity.StudyPost, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "studyPostRepo.GetPost")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, s.db).PrepareContext(ctx, `
 		SELECT *
 		FROM study_post
 		WHERE id=$1;
@@ -60,7 +67,7 @@ func (s *studyPostRepo) GetPost(id int64) (*entity.StudyPost, *errors.RestErr) {
 
 	var studyPost entity.StudyPost
 
-	err = stmt.QueryRow(id).Scan(&studyPost.ID, &studyPost.UserID, &studyPost.Title, &studyPost.Topic, &studyPost.Content, &studyPost.NumOfMembers,
+	err = stmt.QueryRowContext(ctx, id).Scan(&studyPost.ID, &studyPost.UserID, &studyPost.Title, &studyPost.Topic, &studyPost.Content, &studyPost.NumOfMembers,
 		&studyPost.IsMentor, &studyPost.Price, &studyPost.StartDate, &studyPost.EndDate, &studyPost.IsOnline,
 		pq.Array(&studyPost.TechStack), &studyPost.CreatedAt, &studyPost.UpdatedAt)
 	if err != nil {
@@ -73,8 +80,11 @@ func (s *studyPostRepo) GetPost(id int64) (*entity.StudyPost, *errors.RestErr) {
 	return &studyPost, nil
 }
 
-func (s *studyPostRepo) GetPostsInLatestOrder(limit, offset int64) (entity.StudyPosts, *errors.RestErr) { // TODO: uint64 관련해서 js의 number는 64bit float형이라 데이터 받을때 string으로 받아야함
-	stmt, err := s.db.Prepare(`
+func (s *studyPostRepo) GetPostsInLatestOrder(ctx context.Context, limit, offset int64) (_ entity.StudyPosts, restErr *errors.RestErr) { // TODO: uint64 관련해서 js의 number는 64bit float형이라 데이터 받을때 string으로 받아야함
+	ctx, span := startDBSpan(ctx, "studyPostRepo.GetPostsInLatestOrder")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, s.db).PrepareContext(ctx, `
 		SELECT *
 		FROM study_post
 		ORDER BY created_at DESC
@@ -84,7 +94,7 @@ func (s *studyPostRepo) GetPostsInLatestOrder(limit, offset int64) (entity.Study
 		return nil, errors.NewInternalServerError("database error " + err.Error())
 	}
 
-	rows, err := stmt.Query(limit, offset)
+	rows, err := stmt.QueryContext(ctx, limit, offset)
 	if err != nil {
 		return nil, errors.NewInternalServerError("database error " + err.Error())
 	}
@@ -111,8 +121,11 @@ func (s *studyPostRepo) GetPostsInLatestOrder(limit, offset int64) (entity.Study
 }
 
 // GetPostsByUserID 특정 user가 쓴 게시글들을 최신순으로 return
-func (s *studyPostRepo) GetPostsByUserID(userID, limit, offset int64) (entity.StudyPosts, *errors.RestErr) {
-	stmt, err := s.db.Prepare(`
+func (s *studyPostRepo) GetPostsByUserID(ctx context.Context, userID, limit, offset int64) (_ entity.StudyPosts, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "studyPostRepo.GetPostsByUserID")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, s.db).PrepareContext(ctx, `
 		SELECT *
 		FROM study_post
 		WHERE user_id=$1
@@ -123,7 +136,7 @@ func (s *studyPostRepo) GetPostsByUserID(userID, limit, offset int64) (entity.St
 		return nil, errors.NewInternalServerError("database error " + err.Error())
 	}
 
-	rows, err := stmt.Query(userID, limit, offset)
+	rows, err := stmt.QueryContext(ctx, userID, limit, offset)
 	if err != nil {
 		return nil, errors.NewInternalServerError("database error " + err.Error())
 	}
@@ -149,31 +162,23 @@ func (s *studyPostRepo) GetPostsByUserID(userID, limit, offset int64) (entity.St
 	return studyPosts, nil
 }
 
-func (s *studyPostRepo) UpdatePost(studyPost *entity.StudyPost) (*entity.StudyPost, *errors.RestErr) {
-	tx, err := s.db.Begin()
-	if err != nil {
-		return nil, errors.NewInternalServerError("database error " + err.Error())
-	}
-	stmt, err := tx.Prepare(`
+func (s *studyPostRepo) UpdatePost(ctx context.Context, studyPost *entity.StudyPost) (_ *entity.StudyPost, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "studyPostRepo.UpdatePost")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, s.db).PrepareContext(ctx, `
 		UPDATE study_post
 		SET title=$1, topic=$2, content=$3, num_of_members=$4, is_mentor=$5, price=$6,
 		    start_date=$7, end_date=$8, is_online=$9, tech_stack=$10, updated_at=$11
 		WHERE id=$12
 		RETURNING *;
 	`)
-	//stmt, err := s.db.Prepare(`
-	//	UPDATE study_post
-	//	SET title=$1, topic=$2, content=$3, num_of_members=$4, is_mentor=$5, price=$6,
-	//	    start_date=$7, end_date=$8, is_online=$9, tech_stack=$10, updated_at=$11
-	//	WHERE id=$12
-	//	RETURNING *;
-	//`)
 	if err != nil {
 		return nil, errors.NewInternalServerError("database error " + err.Error())
 	}
 
 	now := helpers.GetCurrentTimeForDB()
-	row := stmt.QueryRow(studyPost.Title, studyPost.Topic, studyPost.Content, studyPost.NumOfMembers, studyPost.IsMentor, studyPost.Price, studyPost.StartDate,
+	row := stmt.QueryRowContext(ctx, studyPost.Title, studyPost.Topic, studyPost.Content, studyPost.NumOfMembers, studyPost.IsMentor, studyPost.Price, studyPost.StartDate,
 		studyPost.EndDate, studyPost.IsOnline, pq.Array(studyPost.TechStack), now, studyPost.ID)
 	err = row.Err()
 	if err != nil {
@@ -183,18 +188,17 @@ func (s *studyPostRepo) UpdatePost(studyPost *entity.StudyPost) (*entity.StudyPo
 	err = row.Scan(&studyPost.ID, &studyPost.UserID, &studyPost.Title, &studyPost.Topic, &studyPost.Content, &studyPost.NumOfMembers,
 		&studyPost.IsMentor, &studyPost.Price, &studyPost.StartDate, &studyPost.EndDate, &studyPost.IsOnline, pq.Array(&studyPost.TechStack), &studyPost.CreatedAt, &studyPost.UpdatedAt)
 	if err != nil {
-		rErr := tx.Rollback() // 에러시 rollback
-		if rErr != nil {
-			return nil, errors.NewInternalServerError("rollback error " + err.Error())
-		}
 		return nil, errors.NewInternalServerError("database update error " + err.Error())
 	}
 
 	return studyPost, nil
 }
 
-func (s *studyPostRepo) DeletePost(studyPostID int64) *errors.RestErr {
-	stmt, err := s.db.Prepare(`
+func (s *studyPostRepo) DeletePost(ctx context.Context, studyPostID int64) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "studyPostRepo.DeletePost")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, s.db).PrepareContext(ctx, `
 		DELETE 
 		FROM study_post
 		WHERE id=$1;
@@ -203,7 +207,7 @@ func (s *studyPostRepo) DeletePost(studyPostID int64) *errors.RestErr {
 		return errors.NewInternalServerError("database error " + err.Error())
 	}
 
-	res, err := stmt.Exec(studyPostID)
+	res, err := stmt.ExecContext(ctx, studyPostID)
 	if err != nil {
 		return errors.NewInternalServerError("database error " + err.Error())
 	}
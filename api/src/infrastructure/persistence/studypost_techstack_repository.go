@@ -1,6 +1,7 @@
 package persistence
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/code-wave/go-wave/domain/repository"
@@ -18,15 +19,18 @@ func NewStudyPostTechStackRepo(db *sql.DB) *studyPostTechStackRepo {
 var _ repository.StudyPostTechStackRepository = &studyPostTechStackRepo{}
 
 // SaveStudyPostTechStack (studyPostID, techStackID)의 형태로 인자로 받는 techStack 배열만큼 한번에 저장
-func (s *studyPostTechStackRepo) SaveStudyPostTechStack(studyPostID int64, techStack []string) *errors.RestErr {
+func (s *studyPostTechStackRepo) SaveStudyPostTechStack(ctx context.Context, studyPostID int64, techStack []string) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "studyPostTechStackRepo.SaveStudyPostTechStack")
+	defer endSpan(span, &restErr)
+
 	query := s.insertAllTechStackQuery(studyPostID, techStack)
 
-	stmt, err := s.db.Prepare(query)
+	stmt, err := conn(ctx, s.db).PrepareContext(ctx, query)
 	if err != nil {
 		return errors.NewInternalServerError("database error" + err.Error())
 	}
 
-	_, err = stmt.Exec()
+	_, err = stmt.ExecContext(ctx)
 	if err != nil {
 		return errors.NewInternalServerError("execute error " + err.Error())
 	}
@@ -50,8 +54,11 @@ func (s *studyPostTechStackRepo) insertAllTechStackQuery(studyPostID int64, tech
 
 func (s *studyPostTechStackRepo) GetAllTechStackQuery() {}
 
-func (s *studyPostTechStackRepo) UpdateStudyPostTechStack(studyPostID int64, techStack []string) *errors.RestErr {
-	stmt, err := s.db.Prepare(`
+func (s *studyPostTechStackRepo) UpdateStudyPostTechStack(ctx context.Context, studyPostID int64, techStack []string) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "studyPostTechStackRepo.UpdateStudyPostTechStack")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, s.db).PrepareContext(ctx, `
 		DELETE FROM study_post_tech_stack
 		WHERE study_post_id=$1
 	`)
@@ -59,10 +66,10 @@ func (s *studyPostTechStackRepo) UpdateStudyPostTechStack(studyPostID int64, tec
 		return errors.NewInternalServerError("database error " + err.Error())
 	}
 
-	_, err = stmt.Exec(studyPostID)
+	_, err = stmt.ExecContext(ctx, studyPostID)
 	if err != nil {
 		return errors.NewInternalServerError("execute error")
 	}
 
-	return s.SaveStudyPostTechStack(studyPostID, techStack)
+	return s.SaveStudyPostTechStack(ctx, studyPostID, techStack)
 }
@@ -1,6 +1,7 @@
 package persistence
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/code-wave/go-wave/domain/entity"
@@ -19,8 +20,11 @@ func NewTechStackRepo(db *sql.DB) *techStackRepo {
 var _ repository.TechStackRepository = &techStackRepo{}
 
 // SaveTechStack 나중에 추가로 필요한 기술들 외부에서 추가 가능하게 하기 위함 예를 들어 기존 테이블에 TypeScript가 없다면 추가 가능
-func (t *techStackRepo) SaveTechStack(techName string) *errors.RestErr {
-	stmt, err := t.db.Prepare(`
+func (t *techStackRepo) SaveTechStack(ctx context.Context, techName string) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "techStackRepo.SaveTechStack")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, t.db).PrepareContext(ctx, `
 		INSERT INTO tech_stack (tech_name)
 		VALUES ($1);
 	`)
@@ -28,7 +32,7 @@ func (t *techStackRepo) SaveTechStack(techName string) *errors.RestErr {
 		return errors.NewInternalServerError("database error " + err.Error())
 	}
 
-	_, err = stmt.Exec(techName)
+	_, err = stmt.ExecContext(ctx, techName)
 	if err != nil {
 		return errors.NewInternalServerError(err.Error())
 	}
@@ -36,8 +40,11 @@ func (t *techStackRepo) SaveTechStack(techName string) *errors.RestErr {
 	return nil
 }
 
-func (t *techStackRepo) GetTechStack(id int64) (*entity.TechStack, *errors.RestErr) {
-	stmt, err := t.db.Prepare(`
+func (t *techStackRepo) GetTechStack(ctx context.Context, id int64) (_ *entity.TechStack, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "techStackRepo.GetTechStack")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, t.db).PrepareContext(ctx, `
 		SELECT tech_name
 		FROM tech_stack
 		WHERE id=$1;
@@ -48,7 +55,7 @@ func (t *techStackRepo) GetTechStack(id int64) (*entity.TechStack, *errors.RestE
 
 	var techStack entity.TechStack
 
-	err = stmt.QueryRow(id).Scan(&techStack.TechName)
+	err = stmt.QueryRowContext(ctx, id).Scan(&techStack.TechName)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.NewBadRequestError(err.Error())
@@ -59,8 +66,11 @@ func (t *techStackRepo) GetTechStack(id int64) (*entity.TechStack, *errors.RestE
 	return &techStack, nil
 }
 
-func (t *techStackRepo) GetAllTechStack() (entity.TechStacks, *errors.RestErr) {
-	stmt, err := t.db.Prepare(`
+func (t *techStackRepo) GetAllTechStack(ctx context.Context) (_ entity.TechStacks, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "techStackRepo.GetAllTechStack")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, t.db).PrepareContext(ctx, `
 		SELECT tech_name
 		FROM tech_stack;
 	`)
@@ -68,7 +78,7 @@ func (t *techStackRepo) GetAllTechStack() (entity.TechStacks, *errors.RestErr) {
 		return nil, errors.NewInternalServerError("database error" + err.Error())
 	}
 
-	rows, err := stmt.Query()
+	rows, err := stmt.QueryContext(ctx)
 	if err != nil {
 		return nil, errors.NewInternalServerError("query error" + err.Error())
 	}
@@ -88,8 +98,11 @@ func (t *techStackRepo) GetAllTechStack() (entity.TechStacks, *errors.RestErr) {
 	return techStacks, nil
 }
 
-func (t *techStackRepo) GetAllTechStackByStudyPostID(studyPostID int64) (entity.TechStacks, *errors.RestErr) {
-	stmt, err := t.db.Prepare(`
+func (t *techStackRepo) GetAllTechStackByStudyPostID(ctx context.Context, studyPostID int64) (_ entity.TechStacks, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "techStackRepo.GetAllTechStackByStudyPostID")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, t.db).PrepareContext(ctx, `
 		SELECT tech_name
 		FROM tech_stack
 		WHERE id IN (SELECT tech_stack_id FROM study_post_tech_stack WHERE study_post_id=$1);
@@ -98,7 +111,7 @@ func (t *techStackRepo) GetAllTechStackByStudyPostID(studyPostID int64) (entity.
 		return nil, errors.NewInternalServerError("database error" + err.Error())
 	}
 
-	rows, err := stmt.Query(studyPostID)
+	rows, err := stmt.QueryContext(ctx, studyPostID)
 	if err != nil {
 		return nil, errors.NewInternalServerError("query error" + err.Error())
 	}
@@ -118,8 +131,11 @@ func (t *techStackRepo) GetAllTechStackByStudyPostID(studyPostID int64) (entity.
 	return techStacks, nil
 }
 
-func (t *techStackRepo) DeleteTechStack(techName string) *errors.RestErr {
-	stmt, err := t.db.Prepare(`
+func (t *techStackRepo) DeleteTechStack(ctx context.Context, techName string) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "techStackRepo.DeleteTechStack")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, t.db).PrepareContext(ctx, `
 		DELETE FROM tech_stack
 		WHERE tech_name=$1
 	`)
@@ -127,7 +143,7 @@ func (t *techStackRepo) DeleteTechStack(techName string) *errors.RestErr {
 		return errors.NewInternalServerError("database error" + err.Error())
 	}
 
-	res, err := stmt.Exec(techName)
+	res, err := stmt.ExecContext(ctx, techName)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return errors.NewBadRequestError(err.Error())
@@ -146,14 +162,17 @@ func (t *techStackRepo) DeleteTechStack(techName string) *errors.RestErr {
 	return nil
 }
 
-func (t *techStackRepo) CheckTechStack(techStack []string) *errors.RestErr {
+func (t *techStackRepo) CheckTechStack(ctx context.Context, techStack []string) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "techStackRepo.CheckTechStack")
+	defer endSpan(span, &restErr)
+
 	query := t.checkTechStackQuery(techStack)
-	stmt, err := t.db.Prepare(query)
+	stmt, err := conn(ctx, t.db).PrepareContext(ctx, query)
 	if err != nil {
 		return errors.NewInternalServerError("database error " + err.Error())
 	}
 
-	res, err := stmt.Exec()
+	res, err := stmt.ExecContext(ctx)
 	if err != nil {
 		return errors.NewInternalServerError("database error " + err.Error())
 	}
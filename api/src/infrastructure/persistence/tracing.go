@@ -0,0 +1,25 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startDBSpan repository 메서드마다 span을 남김 (span 이름 ex. chatRepo.GetChatRoom)
+func startDBSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(semconv.DBSystemPostgreSQL))
+}
+
+// startRedisSpan redis 명령 span(tracing.RedisHook)은 이 span의 자식으로 남음
+func startRedisSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, name, trace.WithAttributes(semconv.DBSystemRedis))
+}
+
+// endSpan defer로 호출해서 메서드가 반환한 restErr를 기록함
+func endSpan(span trace.Span, restErr **errors.RestErr) {
+	tracing.End(span, *restErr)
+}
@@ -0,0 +1,108 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/logger"
+)
+
+// dbConn sql.DB와 sql.Tx가 같이 가진 메서드, repository는 conn(ctx, db)로 받아서 쿼리를 실행함
+type dbConn interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txKey struct{}
+
+// conn TxManager.WithinTx 안에서 호출되면 진행 중인 transaction을, 아니면 db를 반환
+func conn(ctx context.Context, db *sql.DB) dbConn {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// scopedTx repository 메서드 안에서 여는 transaction
+// 이미 WithinTx 안이면 그 transaction을 같이 쓰고 commit, rollback은 WithinTx에 맡김
+type scopedTx struct {
+	*sql.Tx
+	owner bool
+}
+
+func beginTx(ctx context.Context, db *sql.DB) (*scopedTx, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return &scopedTx{Tx: tx}, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &scopedTx{Tx: tx, owner: true}, nil
+}
+
+func (t *scopedTx) Commit() error {
+	if !t.owner {
+		return nil
+	}
+	return t.Tx.Commit()
+}
+
+func (t *scopedTx) Rollback() error {
+	if !t.owner {
+		return nil
+	}
+	return t.Tx.Rollback()
+}
+
+var _ repository.TxManager = &TxManager{}
+
+type TxManager struct {
+	db *sql.DB
+}
+
+func NewTxManager(db *sql.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithinTx fn 안에서 ctx로 호출한 repository는 모두 같은 transaction을 씀
+// fn이 error를 반환하거나 panic하면 rollback, 이미 transaction 안이면 fn만 호출함
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) *errors.RestErr) (restErr *errors.RestErr) {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	ctx, span := startDBSpan(ctx, "TxManager.WithinTx")
+	defer endSpan(span, &restErr)
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.NewInternalServerError("database error " + err.Error())
+	}
+
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			logger.FromContext(ctx).Error("rollback error", logger.Err(err))
+		}
+	}()
+
+	if restErr = fn(context.WithValue(ctx, txKey{}, tx)); restErr != nil {
+		return restErr
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.NewInternalServerError("commit error " + err.Error())
+	}
+	committed = true
+
+	return nil
+}
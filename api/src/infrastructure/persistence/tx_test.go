@@ -0,0 +1,81 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/utils/config"
+	"github.com/google/uuid"
+	_ "github.com/jackc/pgx/v4/stdlib"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	cfg, _, err := config.Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pg := cfg.Postgres
+	db, err := sql.Open("pgx", fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		pg.Host, pg.Port, pg.User, pg.Password, pg.DBName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err = db.Ping(); err != nil {
+		t.Skip("postgres is not available: ", err.Error())
+	}
+	return db
+}
+
+func TestTxManager_WithinTx(t *testing.T) {
+	db := openTestDB(t)
+	txManager := NewTxManager(db)
+	ctx := context.Background()
+
+	tableExists := func(table string) bool {
+		var exists bool
+		if err := db.QueryRow(`SELECT to_regclass($1) IS NOT NULL;`, table).Scan(&exists); err != nil {
+			t.Fatal(err)
+		}
+		return exists
+	}
+
+	// postgres는 DDL도 transaction 안에서 rollback됨
+	rolledBack := "tx_test_" + strings.ReplaceAll(uuid.New().String(), "-", "")
+	restErr := txManager.WithinTx(ctx, func(ctx context.Context) *errors.RestErr {
+		if _, err := conn(ctx, db).ExecContext(ctx, "CREATE TABLE "+rolledBack+" (id INT);"); err != nil {
+			t.Fatal(err)
+		}
+		return errors.NewBadRequestError("second write failed")
+	})
+	if restErr == nil || restErr.Message != "second write failed" {
+		t.Fatalf("expected fn error, got %v", restErr)
+	}
+	if tableExists(rolledBack) {
+		t.Errorf("table %s should have been rolled back", rolledBack)
+	}
+
+	committed := "tx_test_" + strings.ReplaceAll(uuid.New().String(), "-", "")
+	restErr = txManager.WithinTx(ctx, func(ctx context.Context) *errors.RestErr {
+		// 안에서 다시 WithinTx를 호출해도 같은 transaction을 씀
+		return txManager.WithinTx(ctx, func(ctx context.Context) *errors.RestErr {
+			if _, err := conn(ctx, db).ExecContext(ctx, "CREATE TABLE "+committed+" (id INT);"); err != nil {
+				t.Fatal(err)
+			}
+			return nil
+		})
+	})
+	if restErr != nil {
+		t.Fatal(restErr.Message)
+	}
+	if !tableExists(committed) {
+		t.Fatalf("table %s should have been committed", committed)
+	}
+	db.Exec("DROP TABLE " + committed + ";")
+}
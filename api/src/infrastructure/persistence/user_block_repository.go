@@ -0,0 +1,147 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/domain/repository"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/helpers"
+)
+
+type userBlockRepo struct {
+	db *sql.DB
+}
+
+func NewUserBlockRepo(db *sql.DB) *userBlockRepo {
+	return &userBlockRepo{db}
+}
+
+var _ repository.UserBlockRepository = &userBlockRepo{}
+
+// BlockUser 이미 차단한 유저면 아무것도 하지 않음
+func (u *userBlockRepo) BlockUser(ctx context.Context, blockerID, blockedID int64) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "userBlockRepo.BlockUser")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, u.db).PrepareContext(ctx, `
+		INSERT INTO user_block (blocker_id, blocked_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (blocker_id, blocked_id) DO NOTHING;
+	`)
+	if err != nil {
+		return errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	if _, err = stmt.ExecContext(ctx, blockerID, blockedID, helpers.GetCurrentTimeForDB()); err != nil {
+		return errors.NewInternalServerError("database insert error " + err.Error())
+	}
+
+	return nil
+}
+
+func (u *userBlockRepo) UnblockUser(ctx context.Context, blockerID, blockedID int64) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "userBlockRepo.UnblockUser")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, u.db).PrepareContext(ctx, `
+		DELETE FROM user_block
+		WHERE blocker_id=$1 AND blocked_id=$2;
+	`)
+	if err != nil {
+		return errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	if _, err = stmt.ExecContext(ctx, blockerID, blockedID); err != nil {
+		return errors.NewInternalServerError("database delete error " + err.Error())
+	}
+
+	return nil
+}
+
+func (u *userBlockRepo) GetBlockedUsers(ctx context.Context, blockerID int64) (_ []entity.UserBlock, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "userBlockRepo.GetBlockedUsers")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, u.db).PrepareContext(ctx, `
+		SELECT b.blocker_id, b.blocked_id, users.nickname, b.created_at
+		FROM user_block b
+		JOIN users ON users.id=b.blocked_id
+		WHERE b.blocker_id=$1
+		ORDER BY b.created_at DESC;
+	`)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, blockerID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer rows.Close()
+
+	var blocks []entity.UserBlock
+	for rows.Next() {
+		var block entity.UserBlock
+		if err := rows.Scan(&block.BlockerID, &block.BlockedID, &block.Nickname, &block.CreatedAt); err != nil {
+			return nil, errors.NewInternalServerError("database error " + err.Error())
+		}
+		blocks = append(blocks, block)
+	}
+
+	return blocks, nil
+}
+
+func (u *userBlockRepo) IsBlocked(ctx context.Context, userID, otherUserID int64) (_ bool, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "userBlockRepo.IsBlocked")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, u.db).PrepareContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM user_block
+			WHERE (blocker_id=$1 AND blocked_id=$2) OR (blocker_id=$2 AND blocked_id=$1)
+		);
+	`)
+	if err != nil {
+		return false, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	var isBlocked bool
+	if err = stmt.QueryRowContext(ctx, userID, otherUserID).Scan(&isBlocked); err != nil {
+		return false, errors.NewInternalServerError("database error " + err.Error())
+	}
+
+	return isBlocked, nil
+}
+
+func (u *userBlockRepo) IsDirectChatRoomBlocked(ctx context.Context, roomName string) (_ bool, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "userBlockRepo.IsDirectChatRoomBlocked")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, u.db).PrepareContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM chat_room r
+			JOIN user_block b ON (b.blocker_id=r.client_id AND b.blocked_id=r.host_id)
+			                  OR (b.blocker_id=r.host_id AND b.blocked_id=r.client_id)
+			WHERE r.room_name=$1 AND r.room_type='direct'
+		);
+	`)
+	if err != nil {
+		return false, errors.NewInternalServerError("database error " + err.Error())
+	}
+	defer stmt.Close()
+
+	var isBlocked bool
+	if err = stmt.QueryRowContext(ctx, roomName).Scan(&isBlocked); err != nil {
+		return false, errors.NewInternalServerError("database error " + err.Error())
+	}
+
+	return isBlocked, nil
+}
@@ -1,14 +1,15 @@
 package persistence
 
 import (
+	"context"
 	"database/sql"
-	"log"
 	"strings"
 
 	"github.com/code-wave/go-wave/domain/entity"
 	"github.com/code-wave/go-wave/domain/repository"
 	"github.com/code-wave/go-wave/infrastructure/encryption"
 	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/logger"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -33,17 +34,20 @@ func NewUserRepository(db *sql.DB) *UserRepo {
 	return &UserRepo{db: db}
 }
 
-func (r *UserRepo) Save(user *entity.User) *errors.RestErr {
-	stmt, err := r.db.Prepare(querySaveUser)
+func (r *UserRepo) Save(ctx context.Context, user *entity.User) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "UserRepo.Save")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, querySaveUser)
 	if err != nil {
-		log.Println("error when trying to prepare to save user, ", err)
+		logger.Error("error when trying to prepare to save user", logger.Err(err))
 		return errors.NewInternalServerError("database error")
 	}
 	defer stmt.Close()
 
-	if err = stmt.QueryRow(user.Email, user.Password, user.Name, user.Nickname, user.CreatedAt).
+	if err = stmt.QueryRowContext(ctx, user.Email, user.Password, user.Name, user.Nickname, user.CreatedAt).
 		Scan(&user.ID); err != nil {
-		log.Println("error when trying to scan to save user, ", err)
+		logger.Error("error when trying to scan to save user", logger.Err(err))
 		if strings.Contains(err.Error(), "duplicate") || strings.Contains(err.Error(), "Duplicate") {
 			return errors.NewBadRequestError("email is duplicated, already taken")
 		}
@@ -53,10 +57,13 @@ func (r *UserRepo) Save(user *entity.User) *errors.RestErr {
 	return nil
 }
 
-func (r *UserRepo) GetUserByID(userID int64) (*entity.User, *errors.RestErr) {
-	stmt, err := r.db.Prepare(queryGetUserByID)
+func (r *UserRepo) GetUserByID(ctx context.Context, userID int64) (_ *entity.User, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "UserRepo.GetUserByID")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, queryGetUserByID)
 	if err != nil {
-		log.Println("error when trying to prepare to get user by id, ", err)
+		logger.Error("error when trying to prepare to get user by id", logger.Err(err))
 		return nil, errors.NewInternalServerError("database error")
 	}
 	defer stmt.Close()
@@ -65,43 +72,49 @@ func (r *UserRepo) GetUserByID(userID int64) (*entity.User, *errors.RestErr) {
 		ID: userID,
 	}
 
-	if err = stmt.QueryRow(user.ID).Scan(&user.ID, &user.Email, &user.Name, &user.Nickname, &user.CreatedAt, &user.UpdatedAt); err != nil {
-		log.Println("error when trying to scan after get user by id, ", err)
+	if err = stmt.QueryRowContext(ctx, user.ID).Scan(&user.ID, &user.Email, &user.Name, &user.Nickname, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		logger.Error("error when trying to scan after get user by id", logger.Err(err))
 		return nil, errors.NewInternalServerError("database error")
 	}
 	return &user, nil
 }
 
-func (r *UserRepo) Get(user *entity.User) *errors.RestErr {
-	stmt, err := r.db.Prepare(queryGetUserByID)
+func (r *UserRepo) Get(ctx context.Context, user *entity.User) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "UserRepo.Get")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, queryGetUserByID)
 	if err != nil {
-		log.Println("error when trying to prepare to get user by id, ", err)
+		logger.Error("error when trying to prepare to get user by id", logger.Err(err))
 		return errors.NewInternalServerError("database error")
 	}
 	defer stmt.Close()
 
-	if err = stmt.QueryRow(user.ID).Scan(&user.ID, &user.Email, &user.Name, &user.Nickname, &user.CreatedAt, &user.UpdatedAt); err != nil {
+	if err = stmt.QueryRowContext(ctx, user.ID).Scan(&user.ID, &user.Email, &user.Name, &user.Nickname, &user.CreatedAt, &user.UpdatedAt); err != nil {
 		// if strings.Contains(err.Error(), "no rows in result set") {
 		// 	log.Println("error when trying to scan after get user by id " + err.Error())
 		// 	return errors.NewNoRowsError()
 		// }
-		log.Println("error when trying to scan after get user by id, ", err)
+		logger.Error("error when trying to scan after get user by id", logger.Err(err))
 		return errors.NewInternalServerError("database error")
 	}
 	return nil
 }
 
-func (r *UserRepo) GetAll(limit, offset int64) (entity.Users, *errors.RestErr) {
-	stmt, err := r.db.Prepare(queryGetAllUsers)
+func (r *UserRepo) GetAll(ctx context.Context, limit, offset int64) (_ entity.Users, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "UserRepo.GetAll")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, queryGetAllUsers)
 	if err != nil {
-		log.Println("error when trying to prepare to get all users with limit & offset, ", err)
+		logger.Error("error when trying to prepare to get all users with limit & offset", logger.Err(err))
 		return nil, errors.NewInternalServerError("database error")
 	}
 	defer stmt.Close()
 
-	rows, err := stmt.Query(limit, offset)
+	rows, err := stmt.QueryContext(ctx, limit, offset)
 	if err != nil {
-		log.Println("error when trying to Query to get all users with limit & offset, ", err)
+		logger.Error("error when trying to Query to get all users with limit & offset", logger.Err(err))
 		return nil, errors.NewInternalServerError("database error")
 	}
 	defer rows.Close()
@@ -110,7 +123,7 @@ func (r *UserRepo) GetAll(limit, offset int64) (entity.Users, *errors.RestErr) {
 	for rows.Next() {
 		var user entity.User
 		if err := rows.Scan(&user.ID, &user.Email, &user.Password, &user.Name, &user.Nickname, &user.CreatedAt, &user.UpdatedAt); err != nil {
-			log.Println("error when trying to scan to get all users, ", err)
+			logger.Error("error when trying to scan to get all users", logger.Err(err))
 			return nil, errors.NewInternalServerError("database error")
 		}
 		users = append(users, user)
@@ -119,60 +132,69 @@ func (r *UserRepo) GetAll(limit, offset int64) (entity.Users, *errors.RestErr) {
 	return users, nil
 }
 
-func (r *UserRepo) Update(user *entity.User) *errors.RestErr {
-	stmt, err := r.db.Prepare(queryUpdateUser)
+func (r *UserRepo) Update(ctx context.Context, user *entity.User) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "UserRepo.Update")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, queryUpdateUser)
 	if err != nil {
-		log.Println("error when trying to prepare to update user, ", err)
+		logger.Error("error when trying to prepare to update user", logger.Err(err))
 		return errors.NewInternalServerError("database error")
 	}
 	defer stmt.Close()
 
 	if user.UpdatedAt.Valid {
-		_, err = stmt.Exec(user.Password, user.Name, user.Nickname, user.UpdatedAt.String, user.ID)
+		_, err = stmt.ExecContext(ctx, user.Password, user.Name, user.Nickname, user.UpdatedAt.String, user.ID)
 	} else {
-		_, err = stmt.Exec(user.Password, user.Name, user.Nickname, nil, user.ID)
+		_, err = stmt.ExecContext(ctx, user.Password, user.Name, user.Nickname, nil, user.ID)
 	}
 
 	if err != nil {
-		log.Println("error when trying to execute update user, ", err)
+		logger.Error("error when trying to execute update user", logger.Err(err))
 		return errors.NewInternalServerError("database error")
 	}
 
 	return nil
 }
 
-func (r *UserRepo) Delete(userID int64) *errors.RestErr {
-	stmt, err := r.db.Prepare(queryDeleteUser)
+func (r *UserRepo) Delete(ctx context.Context, userID int64) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "UserRepo.Delete")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, queryDeleteUser)
 	if err != nil {
-		log.Println("error when trying to prepare to delete user, ", err)
+		logger.Error("error when trying to prepare to delete user", logger.Err(err))
 		return errors.NewInternalServerError("database error")
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(userID)
+	_, err = stmt.ExecContext(ctx, userID)
 	if err != nil {
-		log.Println("error when trying to exeucte delete user, ", err)
+		logger.Error("error when trying to exeucte delete user", logger.Err(err))
 		return errors.NewInternalServerError("database error")
 	}
 
 	return nil
 }
 
-func (r *UserRepo) FindByEmailAndPassword(lu *entity.User) (*entity.User, *errors.RestErr) {
-	stmt, err := r.db.Prepare(queryFindByEmailAndPassword)
+func (r *UserRepo) FindByEmailAndPassword(ctx context.Context, lu *entity.User) (_ *entity.User, restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "UserRepo.FindByEmailAndPassword")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, queryFindByEmailAndPassword)
 	if err != nil {
-		log.Println("error when trying to prepare to find user by email and password, ", err)
+		logger.Error("error when trying to prepare to find user by email and password", logger.Err(err))
 		return nil, errors.NewInternalServerError("database error")
 	}
 	defer stmt.Close()
 
 	var user entity.User
-	if err := stmt.QueryRow(lu.Email).
+	if err := stmt.QueryRowContext(ctx, lu.Email).
 		Scan(&user.ID, &user.Email, &user.Password, &user.Name, &user.Nickname, &user.CreatedAt, &user.UpdatedAt); err != nil {
 		if strings.Contains(err.Error(), "no rows in result set") {
 			return nil, errors.NewNotFoundError("wrong, email does not matched")
 		}
-		log.Println("error when trying to find user by email and password after scan, ", err)
+		logger.Error("error when trying to find user by email and password after scan", logger.Err(err))
 		return nil, errors.NewInternalServerError("database error")
 	}
 
@@ -186,42 +208,48 @@ func (r *UserRepo) FindByEmailAndPassword(lu *entity.User) (*entity.User, *error
 	return &user, nil
 }
 
-func (r *UserRepo) FindByEmail(email string) *errors.RestErr {
-	stmt, err := r.db.Prepare(queryFindByEmail)
+func (r *UserRepo) FindByEmail(ctx context.Context, email string) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "UserRepo.FindByEmail")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, queryFindByEmail)
 	if err != nil {
-		log.Println("error when trying to prepare to find by email " + err.Error())
+		logger.Error("error when trying to prepare to find by email", logger.Err(err))
 		return errors.NewInternalServerError("database error " + err.Error())
 	}
 	defer stmt.Close()
 
 	var u entity.User
-	if err := stmt.QueryRow(email).Scan(&u.Email); err != nil {
+	if err := stmt.QueryRowContext(ctx, email).Scan(&u.Email); err != nil {
 		if err == sql.ErrNoRows {
-			log.Println("email doesn't exits " + err.Error())
+			logger.Debug("email doesn't exist")
 			return errors.NewNotFoundError("email doesn't exits " + err.Error())
 		}
-		log.Println("error when trying to scan to find by email " + err.Error())
+		logger.Error("error when trying to scan to find by email", logger.Err(err))
 		return errors.NewInternalServerError(err.Error())
 	}
 
 	return nil
 }
 
-func (r *UserRepo) FindByNickname(nickname string) *errors.RestErr {
-	stmt, err := r.db.Prepare(queryFindByNickname)
+func (r *UserRepo) FindByNickname(ctx context.Context, nickname string) (restErr *errors.RestErr) {
+	ctx, span := startDBSpan(ctx, "UserRepo.FindByNickname")
+	defer endSpan(span, &restErr)
+
+	stmt, err := conn(ctx, r.db).PrepareContext(ctx, queryFindByNickname)
 	if err != nil {
-		log.Println("error when trying to prepare to find by email " + err.Error())
+		logger.Error("error when trying to prepare to find by email", logger.Err(err))
 		return errors.NewInternalServerError("database error " + err.Error())
 	}
 	defer stmt.Close()
 
 	var u entity.User
-	if err := stmt.QueryRow(nickname).Scan(&u.Nickname); err != nil {
+	if err := stmt.QueryRowContext(ctx, nickname).Scan(&u.Nickname); err != nil {
 		if err == sql.ErrNoRows {
-			log.Println("nickname doesn't exits " + err.Error())
+			logger.Debug("nickname doesn't exist")
 			return errors.NewNotFoundError("nickname doesn't exits " + err.Error())
 		}
-		log.Println("error when trying to scan to find by nickname " + err.Error())
+		logger.Error("error when trying to scan to find by nickname", logger.Err(err))
 		return errors.NewInternalServerError(err.Error())
 	}
 
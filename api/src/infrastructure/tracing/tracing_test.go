@@ -0,0 +1,45 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetup_FileExporter(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(prev)
+
+	path := filepath.Join(t.TempDir(), "spans.json")
+	shutdown, err := Setup(Options{ServiceName: "go-wave-test", Exporter: ExporterFile, File: path, SampleRatio: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, parent := Start(context.Background(), "chat.receive")
+	carrier := Inject(ctx)
+	_, child := Start(Extract(context.Background(), carrier), "chat.deliver")
+	child.End()
+	parent.End()
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if child.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+		t.Error("span started from the extracted carrier should be in the same trace")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"chat.receive", "chat.deliver", parent.SpanContext().TraceID().String()} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("%q not exported: %s", want, data)
+		}
+	}
+}
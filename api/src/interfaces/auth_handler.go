@@ -2,11 +2,11 @@ package interfaces
 
 import (
 	"encoding/json"
-	"log"
 	"net/http"
 	"strings"
 
 	"github.com/code-wave/go-wave/infrastructure/helpers"
+	"github.com/code-wave/go-wave/infrastructure/logger"
 
 	"github.com/code-wave/go-wave/application"
 	"github.com/code-wave/go-wave/domain/entity"
@@ -43,7 +43,7 @@ func (ah *AuthHandler) LoginUser(w http.ResponseWriter, r *http.Request) {
 		Password: lu.Password,
 	}
 
-	findUser, err := ah.ua.FindByEmailAndPassword(user)
+	findUser, err := ah.ua.FindByEmailAndPassword(r.Context(), user)
 	if err != nil {
 		w.WriteHeader(err.Status)
 		if strings.Contains(err.Message, "wrong") {
@@ -54,7 +54,7 @@ func (ah *AuthHandler) LoginUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := ah.ua.LoginUser(findUser)
+	result, err := ah.ua.LoginUser(r.Context(), findUser)
 	if err != nil {
 		w.WriteHeader(err.Status)
 		w.Write(err.ResponseJSON().([]byte))
@@ -81,7 +81,7 @@ func (ah *AuthHandler) LoginUser(w http.ResponseWriter, r *http.Request) {
 	http.SetCookie(w, &rtCookie)
 
 	//save result["refreshToken"] to redis metadata
-	if authErr := ah.au.CreateAuth(rt); authErr != nil {
+	if authErr := ah.au.CreateAuth(r.Context(), rt); authErr != nil {
 		w.WriteHeader(authErr.Status)
 		w.Write(authErr.ResponseJSON().([]byte))
 		return
@@ -114,7 +114,7 @@ func (ah *AuthHandler) LogoutUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if authErr := ah.au.DeleteAuth(refreshUuid.Value); authErr != nil {
+	if authErr := ah.au.DeleteAuth(r.Context(), refreshUuid.Value); authErr != nil {
 		w.WriteHeader(authErr.Status)
 		w.Write(authErr.ResponseJSON().([]byte))
 		return
@@ -124,7 +124,7 @@ func (ah *AuthHandler) LogoutUser(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write(result)
 
-	log.Println(userID)
+	logger.FromContext(r.Context()).Info("user logged out", logger.F("user_id", userID))
 }
 
 func (ah *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
@@ -138,7 +138,7 @@ func (ah *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	at, authErr := ah.au.Refresh(refreshUuid.Value, userID.(int64))
+	at, authErr := ah.au.Refresh(r.Context(), refreshUuid.Value, userID.(int64))
 	if authErr != nil {
 		w.WriteHeader(authErr.Status)
 		w.Write(authErr.ResponseJSON().([]byte))
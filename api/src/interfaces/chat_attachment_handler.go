@@ -0,0 +1,161 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/code-wave/go-wave/application"
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/helpers"
+	"github.com/code-wave/go-wave/infrastructure/logger"
+	"github.com/code-wave/go-wave/interfaces/middleware"
+)
+
+// multipart form에서 파일 외의 필드와 헤더에 허용하는 크기
+const multipartOverhead = 1 << 20
+
+type ChatAttachmentHandler struct {
+	ca application.ChatAttachmentInterface
+}
+
+func NewChatAttachmentHandler(ca application.ChatAttachmentInterface) *ChatAttachmentHandler {
+	return &ChatAttachmentHandler{
+		ca: ca,
+	}
+}
+
+// UploadAttachment: multipart form (chat_room_id, file)으로 첨부파일 업로드
+func (h *ChatAttachmentHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	uploaderID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.ca.MaxAttachmentSize()+multipartOverhead)
+	if err := r.ParseMultipartForm(multipartOverhead); err != nil {
+		restErr := errors.NewBadRequestError("invalid multipart form or attachment is too large")
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	chatRoomID, err := strconv.ParseInt(r.FormValue("chat_room_id"), 10, 64)
+	if err != nil {
+		restErr := errors.NewBadRequestError("chat_room_id is not valid")
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		restErr := errors.NewBadRequestError("file is required")
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+	defer file.Close()
+
+	attachment, restErr := h.ca.UploadAttachment(r.Context(), uploaderID, chatRoomID, header.Filename, file, header.Size)
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	aJSON, err := json.Marshal(map[string]*entity.ChatAttachment{"attachment": attachment})
+	if err != nil {
+		restErr := errors.NewInternalServerError("marshal error " + err.Error())
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(aJSON)
+}
+
+// GetAttachmentURL: 채팅룸 참여자에게 만료 시간이 있는 다운로드 URL을 발급
+func (h *ChatAttachmentHandler) GetAttachmentURL(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	userID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	attachmentID, restErr := helpers.ExtractIntParam(r, "attachment_id")
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	url, restErr := h.ca.GetAttachmentURL(r.Context(), userID, attachmentID)
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	result, _ := json.Marshal(map[string]string{"url": url})
+	w.WriteHeader(http.StatusOK)
+	w.Write(result)
+}
+
+// DownloadAttachment: GetAttachmentURL로 발급받은 URL로 파일을 내려받음 (img 태그에서도 쓸 수 있게 토큰 대신 서명으로 확인)
+func (h *ChatAttachmentHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	attachmentID, restErr := helpers.ExtractIntParam(r, "attachment_id")
+	if restErr != nil {
+		helpers.SetJsonHeader(w)
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	userID, restErr := helpers.ExtractIntParam(r, "user_id")
+	if restErr != nil {
+		helpers.SetJsonHeader(w)
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	expires, restErr := helpers.ExtractIntParam(r, "expires")
+	if restErr != nil {
+		helpers.SetJsonHeader(w)
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	signature := helpers.ExtractStringParam(r, "signature")
+
+	attachment, file, restErr := h.ca.OpenAttachment(r.Context(), attachmentID, userID, expires, signature)
+	if restErr != nil {
+		helpers.SetJsonHeader(w)
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+	defer file.Close()
+
+	// 이미지는 채팅창에서 바로 보여주고 나머지는 다운로드
+	disposition := "attachment"
+	if strings.HasPrefix(attachment.ContentType, "image/") {
+		disposition = "inline"
+	}
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, file); err != nil {
+		logger.FromContext(r.Context()).Warn("attachment download error", logger.Err(err), logger.F("attachment_id", attachment.ID))
+	}
+}
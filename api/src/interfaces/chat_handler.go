@@ -1,49 +1,88 @@
 package interfaces
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
-	"log"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/code-wave/go-wave/infrastructure/helpers"
+	"github.com/code-wave/go-wave/infrastructure/logger"
+	"github.com/code-wave/go-wave/interfaces/middleware"
 
 	"github.com/code-wave/go-wave/application"
+	"github.com/code-wave/go-wave/domain/entity"
 	"github.com/code-wave/go-wave/infrastructure/chat"
 	"github.com/code-wave/go-wave/infrastructure/errors"
 	"github.com/gorilla/websocket"
 )
 
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  4096,
-	WriteBufferSize: 4096,
-	//websocket origin setting
-	CheckOrigin: func(r *http.Request) bool {
-		return true
-	},
-}
+// 채팅 기록을 내려받을 때 이 개수의 메시지마다 client에게 보냄
+const transcriptFlushInterval = 100
 
 type ChatHandler struct {
-	userApp      application.UserAppInterface
-	studyPostApp application.StudyPostInterface
-	chatApp      application.ChatAppInterface
+	userApp       application.UserAppInterface
+	studyPostApp  application.StudyPostInterface
+	chatApp       application.ChatAppInterface
+	presenceApp   application.PresenceAppInterface
+	moderationApp application.ModerationInterface
+	upgrader      websocket.Upgrader
 }
 
-func NewChatHandler(userApp application.UserAppInterface, studyPostApp application.StudyPostInterface, chatApp application.ChatAppInterface) *ChatHandler {
+// NewChatHandler allowedOrigins는 CORS와 같은 origin 목록으로, websocket 연결도 이 origin에서만 허용함
+func NewChatHandler(userApp application.UserAppInterface, studyPostApp application.StudyPostInterface, chatApp application.ChatAppInterface, presenceApp application.PresenceAppInterface,
+	moderationApp application.ModerationInterface, allowedOrigins []string) *ChatHandler {
 	return &ChatHandler{
-		userApp:      userApp,
-		studyPostApp: studyPostApp,
-		chatApp:      chatApp,
+		userApp:       userApp,
+		studyPostApp:  studyPostApp,
+		chatApp:       chatApp,
+		presenceApp:   presenceApp,
+		moderationApp: moderationApp,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  4096,
+			WriteBufferSize: 4096,
+			CheckOrigin: func(r *http.Request) bool {
+				return checkOrigin(r.Header.Get("Origin"), allowedOrigins)
+			},
+		},
+	}
+}
+
+// checkOrigin origin이 허용 목록에 있는지 확인, rs/cors처럼 "*"와 "https://*.example.com" 형태를 지원함
+// Origin 헤더가 없으면 브라우저가 아니므로 허용함 (gorilla/websocket 기본 동작과 같음)
+func checkOrigin(origin string, allowedOrigins []string) bool {
+	if origin == "" {
+		return true
+	}
+	origin = strings.ToLower(origin)
+
+	for _, allowed := range allowedOrigins {
+		allowed = strings.ToLower(allowed)
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+		if i := strings.IndexByte(allowed, '*'); i >= 0 {
+			prefix, suffix := allowed[:i], allowed[i+1:]
+			if len(origin) >= len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+				return true
+			}
+		}
 	}
+
+	return false
 }
 
-// ServeChatWs: roomName과 유저정보를 보내면 websocket 연결시켜줌
+// ServeChatWs: 로그인한 유저가 roomName을 보내면 websocket 연결시켜줌
 func (chatHandler *ChatHandler) ServeChatWs(chatServer *chat.ChatServer, w http.ResponseWriter, r *http.Request) {
-	log.Println("ws conneting....")
+	log := logger.FromContext(r.Context())
 
 	// websocket 기능 추가
-	conn, wsErr := upgrader.Upgrade(w, r, nil)
+	conn, wsErr := chatHandler.upgrader.Upgrade(w, r, nil)
 	if wsErr != nil {
-		log.Println("wsErr " + wsErr.Error())
+		log.Warn("websocket upgrade error", logger.Err(wsErr))
 		//error 처리 고민...
 		return
 	}
@@ -51,22 +90,22 @@ func (chatHandler *ChatHandler) ServeChatWs(chatServer *chat.ChatServer, w http.
 	var wsReq chat.WsRequest
 
 	if err := conn.ReadJSON(&wsReq); err != nil {
-		log.Println("read message error " + err.Error())
+		log.Warn("read message error", logger.Err(err))
 		return
 	}
 
-	// client의 정보를 가져옴
-	user, err := chatHandler.userApp.GetUserByID(wsReq.UserID)
+	user, roomInfo, err := chatHandler.authorizeChatRoom(r.Context(), wsReq.ChatRoomName)
 	if err != nil {
-		log.Println("get client err " + err.Message)
+		log.Warn("authorize chat room error", logger.F("error", err.Message), logger.F("room", wsReq.ChatRoomName))
 		conn.WriteJSON(err)
+		conn.Close()
 		return
 	}
 
 	// client의 정보를 토대로 ChatUser 객체 생성
-	chatClient := chat.NewChatUser(user.ID, user.Name, user.Nickname, conn, chatServer)
-	// 메시지 보내기를 눌렀을 때는 무조건 새로 생성
-	chatRoom := chatServer.CreateRoom(wsReq.ChatRoomName)
+	chatClient := chat.NewChatUser(r.Context(), user.ID, user.Name, user.Nickname, conn, chatServer)
+	// 이 인스턴스에 같은 채팅룸이 있으면 같이 쓰고 없으면 새로 생성
+	chatRoom := chatServer.CreateRoom(roomInfo.ID, wsReq.ChatRoomName)
 	chatClient.ChatRooms[wsReq.ChatRoomName] = chatRoom
 
 	var chatServerReq chat.ChatServerRequest
@@ -75,14 +114,129 @@ func (chatHandler *ChatHandler) ServeChatWs(chatServer *chat.ChatServer, w http.
 
 	chatServer.Register <- chatServerReq
 
+	// 재접속한 경우 마지막으로 받은 메시지 이후의 메시지를 다시 보내줌
+	if wsReq.LastMessageID > 0 {
+		chatClient.Replay(chatHandler.getMissedMessages(r.Context(), roomInfo.ID, wsReq.LastMessageID))
+	}
+
 	go chatClient.ReadPump()
 	go chatClient.WritePump()
 }
 
-// GetChatRoomInfo: 채팅룸과 기존메시지(존재하면)를 반환함
+// authorizeChatRoom: 채팅룸의 참여자만 접속할 수 있음 (1:1이면 client/host, 팀 채팅이면 팀원)
+// websocket, SSE, HTTP 메시지 전송이 같이 쓰고, 유저는 client가 보낸 값이 아니라 AuthVerifyMiddleware가 확인한 access token의 유저
+func (chatHandler *ChatHandler) authorizeChatRoom(ctx context.Context, roomName string) (*entity.User, *entity.ChatRoom, *errors.RestErr) {
+	userID := ctx.Value(middleware.ContextKeyTokenUserID).(int64)
+
+	// client의 정보를 가져옴
+	user, err := chatHandler.userApp.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	roomInfo, err := chatHandler.chatApp.GetChatRoomByRoomName(ctx, roomName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = chatHandler.chatApp.CheckChatRoomParticipant(ctx, roomInfo.ID, user.ID); err != nil {
+		return nil, nil, err
+	}
+
+	return user, roomInfo, nil
+}
+
+// StreamChatRoom: websocket을 쓸 수 없는 client를 위한 SSE 연결, 메시지 보내기는 SendChatMessage로 함
+// 재접속하면 Last-Event-ID(마지막으로 받은 메시지 ID) 이후의 메시지를 다시 보내줌
+func (chatHandler *ChatHandler) StreamChatRoom(chatServer *chat.ChatServer, w http.ResponseWriter, r *http.Request) {
+	roomName := helpers.ExtractStringParam(r, "chat_room_name")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		restErr := errors.NewInternalServerError("streaming is not supported")
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	user, roomInfo, restErr := chatHandler.authorizeChatRoom(r.Context(), roomName)
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx가 응답을 버퍼링하지 않도록
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	chatClient := chat.NewChatStreamUser(r.Context(), user.ID, user.Name, user.Nickname, chatServer)
+	chatClient.ChatRooms[roomName] = chatServer.CreateRoom(roomInfo.ID, roomName)
+	chatServer.Register <- chat.ChatServerRequest{User: chatClient, ChatRoomName: roomName}
+
+	if lastMessageID, err := strconv.ParseInt(r.Header.Get("Last-Event-ID"), 10, 64); err == nil && lastMessageID > 0 {
+		chatClient.Replay(chatHandler.getMissedMessages(r.Context(), roomInfo.ID, lastMessageID))
+	}
+
+	chatClient.StreamPump(r.Context(), w, flusher)
+}
+
+// SendChatMessage: websocket 없이 메시지(이벤트)를 보냄, websocket으로 보낸 것과 같이 채팅룸의 유저들에게 전달됨
+func (chatHandler *ChatHandler) SendChatMessage(chatServer *chat.ChatServer, w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	var chatMessage chat.Message
+	if err := json.NewDecoder(r.Body).Decode(&chatMessage); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+	defer r.Body.Close()
+
+	user, roomInfo, restErr := chatHandler.authorizeChatRoom(r.Context(), chatMessage.ChatRoomName)
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+	chatMessage.ChatRoomID = roomInfo.ID
+
+	ack, restErr := chatServer.SendMessage(r.Context(), user.ID, user.Nickname, chatMessage)
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	if ack == nil { // 저장하지 않는 이벤트 (typing, read, edit, delete)
+		result, _ := json.Marshal(map[string]string{"result": "success"})
+		w.WriteHeader(http.StatusOK)
+		w.Write(result)
+		return
+	}
+
+	aJSON, err := json.Marshal(map[string]*chat.Message{"ack": ack})
+	if err != nil {
+		restErr := errors.NewInternalServerError("marshal error " + err.Error())
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(aJSON)
+}
+
+// GetChatRoomInfo: 로그인한 유저와 게시글 host의 채팅룸과 기존메시지(존재하면)를 반환함
 func (chatHandler *ChatHandler) GetChatRoomInfo(w http.ResponseWriter, r *http.Request) {
 	helpers.SetJsonHeader(w)
 
+	userID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64) // client ID
+
 	var chatReq chat.ChatRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&chatReq); err != nil {
@@ -94,7 +248,7 @@ func (chatHandler *ChatHandler) GetChatRoomInfo(w http.ResponseWriter, r *http.R
 	defer r.Body.Close()
 
 	// host user ID 가져옴
-	hostUserID, err := chatHandler.studyPostApp.GetUserIDByPostID(chatReq.StudyPostID)
+	hostUserID, err := chatHandler.studyPostApp.GetUserIDByPostID(r.Context(), chatReq.StudyPostID)
 	if err != nil {
 		w.WriteHeader(err.Status)
 		w.Write(err.ResponseJSON().([]byte))
@@ -103,10 +257,17 @@ func (chatHandler *ChatHandler) GetChatRoomInfo(w http.ResponseWriter, r *http.R
 
 	// 채팅룸이 기존에 존재하는지 새로 만들어야하는지 확인
 	isRoomExist := true
-	chatRoom, err := chatHandler.chatApp.GetChatRoom(chatReq.UserID, hostUserID, chatReq.StudyPostID) // chatReq.UserID = clientID
+	chatRoom, err := chatHandler.chatApp.GetChatRoom(r.Context(), userID, hostUserID, chatReq.StudyPostID)
 	if err != nil {
 		if err.Message == errors.ErrNoRows { // 기존 채팅룸이 존재하지 않으므로 새로운 방 만듬
-			chatRoom, restErr := chatHandler.chatApp.SaveChatRoom(chatReq.UserID, hostUserID, chatReq.StudyPostID)
+			// 서로 차단한 유저와는 새 채팅룸을 만들 수 없음
+			if restErr := chatHandler.moderationApp.CheckNotBlocked(r.Context(), userID, hostUserID); restErr != nil {
+				w.WriteHeader(restErr.Status)
+				w.Write(restErr.ResponseJSON().([]byte))
+				return
+			}
+
+			chatRoom, restErr := chatHandler.chatApp.SaveChatRoom(r.Context(), userID, hostUserID, chatReq.StudyPostID)
 			if restErr != nil {
 				w.WriteHeader(restErr.Status)
 				w.Write(restErr.ResponseJSON().([]byte))
@@ -141,7 +302,7 @@ func (chatHandler *ChatHandler) GetChatRoomInfo(w http.ResponseWriter, r *http.R
 	// 채팅룸이 이미 존재하면 기존에 존재하던 채팅룸을 보냄
 	if isRoomExist {
 		var chatMessages chat.Messages
-		chatMessages, err = chatHandler.chatApp.GetChatMessages(chatRoom.ID)
+		chatMessages, err = chatHandler.chatApp.GetChatMessagesBefore(r.Context(), chatRoom.ID, 0, application.DefaultMessagePageSize)
 		if err != nil {
 			w.WriteHeader(err.Status)
 			w.Write(err.ResponseJSON().([]byte))
@@ -164,3 +325,272 @@ func (chatHandler *ChatHandler) GetChatRoomInfo(w http.ResponseWriter, r *http.R
 		w.Write(cJSON)
 	}
 }
+
+// getMissedMessages: 재접속한 유저가 놓친 메시지를 가져옴
+func (chatHandler *ChatHandler) getMissedMessages(ctx context.Context, roomID, lastMessageID int64) chat.Messages {
+	messages, err := chatHandler.chatApp.GetChatMessagesAfter(ctx, roomID, lastMessageID, chat.MaxReplayMessages)
+	if err != nil {
+		logger.FromContext(ctx).Error("get missed messages error", logger.F("error", err.Message), logger.F("chat_room_id", roomID))
+		return nil
+	}
+
+	return messages
+}
+
+// GetChatMessagesBefore: message_id 이전의 메시지를 최신순으로 limit개 반환 (message_id가 0이면 가장 최근 메시지부터)
+func (chatHandler *ChatHandler) GetChatMessagesBefore(w http.ResponseWriter, r *http.Request) {
+	chatHandler.getChatMessagesPage(w, r, chatHandler.chatApp.GetChatMessagesBefore)
+}
+
+// GetChatMessagesAfter: message_id 이후의 메시지를 오래된순으로 limit개 반환 (재접속 후 놓친 메시지 조회용)
+func (chatHandler *ChatHandler) GetChatMessagesAfter(w http.ResponseWriter, r *http.Request) {
+	chatHandler.getChatMessagesPage(w, r, chatHandler.chatApp.GetChatMessagesAfter)
+}
+
+// getChatMessagesPage: 채팅룸 참여자만 메시지를 조회할 수 있음
+func (chatHandler *ChatHandler) getChatMessagesPage(w http.ResponseWriter, r *http.Request, getMessages func(ctx context.Context, roomID, messageID, limit int64) (chat.Messages, *errors.RestErr)) {
+	helpers.SetJsonHeader(w)
+
+	userID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	chatRoomID, err := helpers.ExtractIntParam(r, "chat_room_id")
+	if err != nil {
+		w.WriteHeader(err.Status)
+		w.Write(err.ResponseJSON().([]byte))
+		return
+	}
+
+	err = chatHandler.chatApp.CheckChatRoomParticipant(r.Context(), chatRoomID, userID)
+	if err != nil {
+		w.WriteHeader(err.Status)
+		w.Write(err.ResponseJSON().([]byte))
+		return
+	}
+
+	messageID, err := helpers.ExtractIntParam(r, "message_id")
+	if err != nil {
+		w.WriteHeader(err.Status)
+		w.Write(err.ResponseJSON().([]byte))
+		return
+	}
+
+	limit, err := helpers.ExtractIntParam(r, "limit")
+	if err != nil {
+		w.WriteHeader(err.Status)
+		w.Write(err.ResponseJSON().([]byte))
+		return
+	}
+
+	chatMessages, err := getMessages(r.Context(), chatRoomID, messageID, limit)
+	if err != nil {
+		w.WriteHeader(err.Status)
+		w.Write(err.ResponseJSON().([]byte))
+		return
+	}
+
+	mJSON, jsonErr := json.Marshal(map[string]chat.Messages{"chat_messages": chatMessages})
+	if jsonErr != nil {
+		restErr := errors.NewInternalServerError("marshalling error " + jsonErr.Error())
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(mJSON)
+}
+
+// GetUnreadChatRooms: 로그인한 유저가 속한 채팅룸들을 안 읽은 메시지 개수, 마지막 메시지와 함께 반환
+func (chatHandler *ChatHandler) GetUnreadChatRooms(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	userID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	chatRooms, err := chatHandler.chatApp.GetUnreadChatRooms(r.Context(), userID)
+	if err != nil {
+		w.WriteHeader(err.Status)
+		w.Write(err.ResponseJSON().([]byte))
+		return
+	}
+
+	rJSON, jsonErr := chatRooms.ResponseJSON()
+	if jsonErr != nil {
+		restErr := errors.NewInternalServerError("marshalling error " + jsonErr.Error())
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(rJSON)
+}
+
+// GetChatInbox: 로그인한 유저가 참여중인 대화 목록을 최근 활동순으로 반환 (1:1 채팅은 상대방, 팀 채팅은 다른 참여자들)
+func (chatHandler *ChatHandler) GetChatInbox(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	userID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	inbox, err := chatHandler.chatApp.GetChatInbox(r.Context(), userID)
+	if err != nil {
+		w.WriteHeader(err.Status)
+		w.Write(err.ResponseJSON().([]byte))
+		return
+	}
+
+	iJSON, jsonErr := inbox.ResponseJSON()
+	if jsonErr != nil {
+		restErr := errors.NewInternalServerError("marshalling error " + jsonErr.Error())
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(iJSON)
+}
+
+// GetPresence: 유저의 접속 상태(online, 마지막 접속 시간)를 반환, 본인이나 같은 채팅룸 참여자만 가능
+func (chatHandler *ChatHandler) GetPresence(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	userID, err := helpers.ExtractIntParam(r, "user_id")
+	if err != nil {
+		w.WriteHeader(err.Status)
+		w.Write(err.ResponseJSON().([]byte))
+		return
+	}
+
+	requesterID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	presence, err := chatHandler.presenceApp.GetPresence(r.Context(), requesterID, userID)
+	if err != nil {
+		w.WriteHeader(err.Status)
+		w.Write(err.ResponseJSON().([]byte))
+		return
+	}
+
+	pJSON, jsonErr := json.Marshal(presence)
+	if jsonErr != nil {
+		restErr := errors.NewInternalServerError("marshalling error " + jsonErr.Error())
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(pJSON)
+}
+
+// GetChatMessageEdits: 메시지의 수정 이력을 반환, 채팅룸 참여자만 가능
+func (chatHandler *ChatHandler) GetChatMessageEdits(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	userID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	messageID, err := helpers.ExtractIntParam(r, "message_id")
+	if err != nil {
+		w.WriteHeader(err.Status)
+		w.Write(err.ResponseJSON().([]byte))
+		return
+	}
+
+	edits, err := chatHandler.chatApp.GetChatMessageEdits(r.Context(), messageID, userID)
+	if err != nil {
+		w.WriteHeader(err.Status)
+		w.Write(err.ResponseJSON().([]byte))
+		return
+	}
+
+	eJSON, jsonErr := json.Marshal(map[string]interface{}{"edits": edits})
+	if jsonErr != nil {
+		restErr := errors.NewInternalServerError("marshalling error " + jsonErr.Error())
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(eJSON)
+}
+
+// ExportChatTranscript: 채팅룸의 전체 기록을 format(json | text | html) 파일로 내려받음, 채팅룸 참여자만 가능
+// 메시지가 많아도 메모리에 모두 올리지 않고 DB에서 읽는 대로 보냄
+func (chatHandler *ChatHandler) ExportChatTranscript(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	roomID, err := helpers.ExtractIntParam(r, "chat_room_id")
+	if err != nil {
+		helpers.SetJsonHeader(w)
+		w.WriteHeader(err.Status)
+		w.Write(err.ResponseJSON().([]byte))
+		return
+	}
+
+	format, ok := transcriptFormats[helpers.ExtractStringParam(r, "format")]
+	if !ok {
+		restErr := errors.NewBadRequestError("format must be json, text or html")
+		helpers.SetJsonHeader(w)
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	err = chatHandler.chatApp.CheckChatRoomParticipant(r.Context(), roomID, userID)
+	if err != nil {
+		helpers.SetJsonHeader(w)
+		w.WriteHeader(err.Status)
+		w.Write(err.ResponseJSON().([]byte))
+		return
+	}
+
+	chatRoom, err := chatHandler.chatApp.GetChatRoomByID(r.Context(), roomID)
+	if err != nil {
+		helpers.SetJsonHeader(w)
+		w.WriteHeader(err.Status)
+		w.Write(err.ResponseJSON().([]byte))
+		return
+	}
+
+	w.Header().Set("Content-Type", format.contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="chat-%d.%s"`, roomID, format.extension))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	// 헤더를 보낸 뒤에는 상태 코드를 바꿀 수 없으므로 중간에 실패하면 로그만 남기고 끊음
+	buf := bufio.NewWriter(w)
+	flusher, _ := w.(http.Flusher)
+	transcript := format.newWriter(buf)
+
+	if err := transcript.Begin(chatRoom); err != nil {
+		logger.FromContext(r.Context()).Warn("transcript write error", logger.Err(err))
+		return
+	}
+
+	// 메시지가 많으면 request deadline을 넘을 수 있어서 deadline 없이 읽음, client가 끊기면 write가 실패해서 멈춤
+	count := 0
+	err = chatHandler.chatApp.ExportChatMessages(logger.Detach(r.Context()), roomID, func(message chat.Message) error {
+		if err := transcript.WriteMessage(message); err != nil {
+			return err
+		}
+
+		count++
+		if count%transcriptFlushInterval == 0 && flusher != nil {
+			if err := buf.Flush(); err != nil {
+				return err
+			}
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(r.Context()).Error("transcript export error", logger.F("error", err.Message), logger.F("chat_room_id", roomID))
+		return
+	}
+
+	if err := transcript.End(); err != nil {
+		logger.FromContext(r.Context()).Warn("transcript write error", logger.Err(err))
+		return
+	}
+	buf.Flush()
+}
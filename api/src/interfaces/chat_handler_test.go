@@ -0,0 +1,25 @@
+package interfaces
+
+import "testing"
+
+func TestCheckOrigin(t *testing.T) {
+	allowedOrigins := []string{"https://go-wave.com", "https://*.go-wave.com"}
+
+	for origin, want := range map[string]bool{
+		"":                         true,
+		"https://go-wave.com":      true,
+		"https://GO-WAVE.com":      true,
+		"https://dev.go-wave.com":  true,
+		"http://go-wave.com":       false,
+		"https://evil.com":         false,
+		"https://go-wave.com.evil": false,
+	} {
+		if got := checkOrigin(origin, allowedOrigins); got != want {
+			t.Errorf("checkOrigin(%q) = %v, want %v", origin, got, want)
+		}
+	}
+
+	if !checkOrigin("https://evil.com", []string{"*"}) {
+		t.Error(`checkOrigin with "*" should allow every origin`)
+	}
+}
@@ -0,0 +1,142 @@
+package interfaces
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/infrastructure/chat"
+)
+
+// transcriptWriter 채팅 기록을 메시지 하나씩 파일 형식에 맞게 씀
+type transcriptWriter interface {
+	Begin(chatRoom *entity.ChatRoom) error
+	WriteMessage(message chat.Message) error
+	End() error
+}
+
+// transcriptFormats format 파라미터별 content type, 확장자, writer
+var transcriptFormats = map[string]struct {
+	contentType string
+	extension   string
+	newWriter   func(w *bufio.Writer) transcriptWriter
+}{
+	"json": {"application/json; charset=utf-8", "json", func(w *bufio.Writer) transcriptWriter { return &jsonTranscriptWriter{w: w} }},
+	"text": {"text/plain; charset=utf-8", "txt", func(w *bufio.Writer) transcriptWriter { return &textTranscriptWriter{w: w} }},
+	"html": {"text/html; charset=utf-8", "html", func(w *bufio.Writer) transcriptWriter { return &htmlTranscriptWriter{w: w} }},
+}
+
+// jsonTranscriptWriter {"chat_room": {...}, "messages": [...]}
+type jsonTranscriptWriter struct {
+	w     *bufio.Writer
+	count int
+}
+
+func (t *jsonTranscriptWriter) Begin(chatRoom *entity.ChatRoom) error {
+	roomJSON, err := json.Marshal(chatRoom)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(t.w, `{"chat_room":%s,"messages":[`, roomJSON)
+	return err
+}
+
+func (t *jsonTranscriptWriter) WriteMessage(message chat.Message) error {
+	messageJSON, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	if t.count > 0 {
+		t.w.WriteByte(',')
+	}
+	t.count++
+
+	_, err = t.w.Write(messageJSON)
+	return err
+}
+
+func (t *jsonTranscriptWriter) End() error {
+	_, err := t.w.WriteString("]}\n")
+	return err
+}
+
+// textTranscriptWriter [created_at] sender: message
+type textTranscriptWriter struct {
+	w *bufio.Writer
+}
+
+func (t *textTranscriptWriter) Begin(chatRoom *entity.ChatRoom) error {
+	_, err := fmt.Fprintf(t.w, "chat room: %s (study post %d)\n\n", chatRoom.RoomName, chatRoom.StudyPostID)
+	return err
+}
+
+func (t *textTranscriptWriter) WriteMessage(message chat.Message) error {
+	_, err := fmt.Fprintf(t.w, "[%s] %s: %s\n", message.CreatedAt, message.SenderName, transcriptBody(message))
+	return err
+}
+
+func (t *textTranscriptWriter) End() error {
+	return nil
+}
+
+type htmlTranscriptWriter struct {
+	w *bufio.Writer
+}
+
+func (t *htmlTranscriptWriter) Begin(chatRoom *entity.ChatRoom) error {
+	title := html.EscapeString(chatRoom.RoomName)
+	_, err := fmt.Fprintf(t.w, `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { font-family: sans-serif; }
+.message { margin: 4px 0; white-space: pre-wrap; }
+.time { color: #888; }
+.system { color: #888; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>%s</h1>
+`, title, title)
+	return err
+}
+
+func (t *htmlTranscriptWriter) WriteMessage(message chat.Message) error {
+	class := "message"
+	if message.MessageType == chat.MessageTypeSystem {
+		class += " system"
+	}
+
+	_, err := fmt.Fprintf(t.w, "<div class=\"%s\"><span class=\"time\">[%s]</span> <b>%s</b>: %s</div>\n",
+		class, html.EscapeString(message.CreatedAt), html.EscapeString(message.SenderName), html.EscapeString(transcriptBody(message)))
+	return err
+}
+
+func (t *htmlTranscriptWriter) End() error {
+	_, err := io.WriteString(t.w, "</body>\n</html>\n")
+	return err
+}
+
+// transcriptBody 삭제, 수정, 첨부파일 표시를 붙인 메시지 내용
+func transcriptBody(message chat.Message) string {
+	if message.Deleted {
+		return "(삭제된 메시지)"
+	}
+
+	body := message.Message
+	if message.AttachmentID > 0 {
+		body += fmt.Sprintf(" (첨부파일 #%d)", message.AttachmentID)
+	}
+	if message.EditedAt != "" {
+		body += " (수정됨)"
+	}
+
+	return body
+}
@@ -0,0 +1,109 @@
+package interfaces
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/infrastructure/chat"
+)
+
+var transcriptRoom = &entity.ChatRoom{ID: 1, RoomName: "<room>", ClientID: 1, HostID: 2, StudyPostID: 3}
+
+// transcriptMessages 보통 메시지, 수정된 메시지, HTML이 들어간 메시지, 삭제된 메시지
+func transcriptMessages() chat.Messages {
+	return chat.Messages{
+		chat.NewMessage(entity.ChatMessage{ID: 1, SenderID: 1, Sender: "client", Message: "hello", MessageType: "message", CreatedAt: "2022-01-01 10:00:00"}),
+		chat.NewMessage(entity.ChatMessage{ID: 2, SenderID: 2, Sender: "host", Message: "edited", MessageType: "message", CreatedAt: "2022-01-01 10:01:00",
+			EditedAt: sql.NullString{String: "2022-01-01 10:02:00", Valid: true}}),
+		chat.NewMessage(entity.ChatMessage{ID: 3, SenderID: 1, Sender: "<b>client</b>", Message: `<script>alert("x")</script>`, MessageType: "message", CreatedAt: "2022-01-01 10:03:00"}),
+		chat.NewMessage(entity.ChatMessage{ID: 4, SenderID: 2, Sender: "host", Message: "secret", MessageType: "message", CreatedAt: "2022-01-01 10:04:00",
+			DeletedAt: sql.NullString{String: "2022-01-01 10:05:00", Valid: true}}),
+	}
+}
+
+func writeTranscript(t *testing.T, format string, messages chat.Messages) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	writer := transcriptFormats[format].newWriter(w)
+
+	if err := writer.Begin(transcriptRoom); err != nil {
+		t.Fatal(err)
+	}
+	for _, message := range messages {
+		if err := writer.WriteMessage(message); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.End(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.String()
+}
+
+func TestTranscript_JSON(t *testing.T) {
+	var transcript struct {
+		ChatRoom entity.ChatRoom `json:"chat_room"`
+		Messages chat.Messages   `json:"messages"`
+	}
+
+	for _, messages := range []chat.Messages{nil, transcriptMessages()} {
+		if err := json.Unmarshal([]byte(writeTranscript(t, "json", messages)), &transcript); err != nil {
+			t.Fatalf("transcript with %d messages is not valid JSON: %v", len(messages), err)
+		}
+		if transcript.ChatRoom != *transcriptRoom || len(transcript.Messages) != len(messages) {
+			t.Errorf("transcript = %+v, want %d messages", transcript, len(messages))
+		}
+	}
+
+	deleted := transcript.Messages[3]
+	if !deleted.Deleted || deleted.Message != "" {
+		t.Errorf("deleted message = %+v, want tombstone", deleted)
+	}
+}
+
+func TestTranscript_Text(t *testing.T) {
+	got := writeTranscript(t, "text", transcriptMessages())
+	want := `chat room: <room> (study post 3)
+
+[2022-01-01 10:00:00] client: hello
+[2022-01-01 10:01:00] host: edited (수정됨)
+[2022-01-01 10:03:00] <b>client</b>: <script>alert("x")</script>
+[2022-01-01 10:04:00] host: (삭제된 메시지)
+`
+	if got != want {
+		t.Errorf("text transcript =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTranscript_HTML(t *testing.T) {
+	got := writeTranscript(t, "html", transcriptMessages())
+
+	for _, raw := range []string{"<room>", "<script>", "secret"} {
+		if strings.Contains(got, raw) {
+			t.Errorf("html transcript contains %q", raw)
+		}
+	}
+	for _, escaped := range []string{
+		"<title>&lt;room&gt;</title>",
+		"<b>&lt;b&gt;client&lt;/b&gt;</b>: &lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;",
+		"<b>host</b>: (삭제된 메시지)",
+	} {
+		if !strings.Contains(got, escaped) {
+			t.Errorf("html transcript doesn't contain %q", escaped)
+		}
+	}
+	if !strings.HasSuffix(got, "</body>\n</html>\n") {
+		t.Error("html transcript isn't closed")
+	}
+}
@@ -0,0 +1,51 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/code-wave/go-wave/application"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/helpers"
+)
+
+type HealthHandler struct {
+	ha application.HealthInterface
+}
+
+func NewHealthHandler(ha application.HealthInterface) *HealthHandler {
+	return &HealthHandler{
+		ha: ha,
+	}
+}
+
+// Healthz: liveness, 프로세스가 요청을 처리할 수 있으면 항상 200
+func (h *HealthHandler) Healthz(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	result, _ := json.Marshal(map[string]string{"status": "ok"})
+	w.WriteHeader(http.StatusOK)
+	w.Write(result)
+}
+
+// Readyz: readiness, postgres와 redis가 응답하고 종료 중이 아니면 200, 아니면 503 (서비스마다 상태를 함께 보냄)
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	readiness := h.ha.CheckReadiness(r.Context())
+
+	rJSON, err := json.Marshal(readiness)
+	if err != nil {
+		restErr := errors.NewInternalServerError("marshal error " + err.Error())
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	status := http.StatusOK
+	if !readiness.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	w.WriteHeader(status)
+	w.Write(rJSON)
+}
@@ -2,12 +2,12 @@ package middleware
 
 import (
 	"context"
-	"log"
 	"net/http"
 
 	"github.com/code-wave/go-wave/infrastructure/auth"
 	"github.com/code-wave/go-wave/infrastructure/errors"
 	"github.com/code-wave/go-wave/infrastructure/helpers"
+	"github.com/code-wave/go-wave/infrastructure/logger"
 )
 
 type contextKey string
@@ -37,7 +37,7 @@ func AuthVerifyPayloadMiddleware(next http.Handler) http.Handler {
 		claims, err := auth.JwtWrapper.ValidateToken(clientToken)
 		if err != nil {
 			authErr := errors.NewUnauthorizedError(err.Error())
-			log.Println(authErr)
+			logger.FromContext(r.Context()).Info("invalid access token", logger.Err(err))
 			w.WriteHeader(authErr.Status)
 			w.Write(authErr.ResponseJSON().([]byte))
 			return
@@ -55,7 +55,7 @@ func AuthVerifyMiddleware(next http.Handler) http.Handler {
 
 		atCookie, err := r.Cookie("access_token")
 		if err != nil {
-			log.Println("access token from client's cookie doesn't exist " + err.Error())
+			logger.FromContext(r.Context()).Debug("access token from client's cookie doesn't exist")
 			authErr := errors.NewUnauthorizedError(err.Error())
 			w.WriteHeader(authErr.Status)
 			w.Write(authErr.ResponseJSON().([]byte))
@@ -65,7 +65,7 @@ func AuthVerifyMiddleware(next http.Handler) http.Handler {
 		claims, err := auth.JwtWrapper.ValidateToken(atCookie.Value)
 		if err != nil {
 			authErr := errors.NewUnauthorizedError(err.Error())
-			log.Println(authErr)
+			logger.FromContext(r.Context()).Info("invalid access token", logger.Err(err))
 			w.WriteHeader(authErr.Status)
 			w.Write(authErr.ResponseJSON().([]byte))
 			return
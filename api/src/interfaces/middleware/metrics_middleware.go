@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/code-wave/go-wave/infrastructure/metrics"
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// MetricsMiddleware: 요청 수와 처리 시간을 chi route pattern별로 기록 (router 바깥에서 감싸야 함)
+// router가 쓸 route context를 미리 만들어 넣어서 routing이 끝난 후 pattern을 읽음
+func MetricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r, rctx := withRouteContext(r)
+		ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor) // websocket(Hijacker), SSE(Flusher)도 지원
+
+		start := time.Now()
+		next.ServeHTTP(ww, r)
+
+		route := routePattern(rctx)
+		status := ww.Status()
+		if status == 0 { // 아무것도 쓰지 않았거나 websocket으로 hijack한 경우
+			status = http.StatusOK
+		}
+
+		metrics.HTTPRequests.WithLabelValues(r.Method, route, strconv.Itoa(status)).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
+
+// withRouteContext router가 쓸 route context를 미리 넣음, 바깥 middleware가 이미 넣었으면 그대로 씀
+func withRouteContext(r *http.Request) (*http.Request, *chi.Context) {
+	if rctx, ok := r.Context().Value(chi.RouteCtxKey).(*chi.Context); ok && rctx != nil {
+		return r, rctx
+	}
+	rctx := chi.NewRouteContext()
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx)), rctx
+}
+
+// routePattern 매칭되지 않은 요청은 "unmatched" (없는 경로마다 label, span 이름이 생기지 않도록)
+func routePattern(rctx *chi.Context) string {
+	if route := rctx.RoutePattern(); route != "" {
+		return route
+	}
+	return "unmatched"
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/code-wave/go-wave/infrastructure/metrics"
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsMiddleware_RoutePattern(t *testing.T) {
+	r := chi.NewRouter()
+	r.Get("/users/{user_id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	r.Mount("/api", r)
+	handler := MetricsMiddleware(r)
+
+	for _, path := range []string{"/users/1", "/users/2", "/api/users/3", "/users/1/unknown"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	tests := []struct {
+		route, status string
+		want          float64
+	}{
+		{"/users/{user_id}", "201", 2},
+		{"/api/users/{user_id}", "201", 1},
+		{"unmatched", "404", 1},
+	}
+	for _, tt := range tests {
+		if got := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(http.MethodGet, tt.route, tt.status)); got != tt.want {
+			t.Errorf("route %s status %s: got %v, want %v", tt.route, tt.status, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/code-wave/go-wave/infrastructure/logger"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID proxy가 넘긴 id는 로그에 그대로 남기므로 형식을 제한함
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestIDMiddleware: X-Request-ID를 그대로 쓰거나 새로 만들어서 응답 header와 context에 넣음
+// 이후 logger.FromContext(r.Context())로 남기는 로그에 request_id가 붙음
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		w.Header().Set(RequestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(logger.WithRequestID(r.Context(), requestID)))
+	})
+}
+
+// AccessLogMiddleware: 요청마다 한 줄씩 남김 (RequestIDMiddleware 안쪽에 둬야 request_id가 붙음)
+func AccessLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		start := time.Now()
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		fields := []logger.Field{
+			logger.F("method", r.Method),
+			logger.F("path", r.URL.Path),
+			logger.F("status", status),
+			logger.F("bytes", ww.BytesWritten()),
+			logger.F("duration_ms", time.Since(start).Milliseconds()),
+			logger.F("remote_addr", r.RemoteAddr),
+		}
+
+		log := logger.FromContext(r.Context())
+		if status >= http.StatusInternalServerError {
+			log.Error("http request", fields...)
+		} else {
+			log.Info("http request", fields...)
+		}
+	})
+}
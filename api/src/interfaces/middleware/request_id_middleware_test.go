@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/code-wave/go-wave/infrastructure/logger"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var got string
+	handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = logger.RequestID(r.Context())
+	}))
+
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"from proxy", "3f2a9c1e-proxy", true},
+		{"missing", "", false},
+		{"invalid", "bad id\nwith newline", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if got == "" || rec.Header().Get(RequestIDHeader) != got {
+				t.Fatalf("request id in context %q, header %q", got, rec.Header().Get(RequestIDHeader))
+			}
+			if (got == tt.header) != tt.keep {
+				t.Errorf("header %q: got request id %q", tt.header, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/code-wave/go-wave/infrastructure/logger"
+)
+
+// RequestTimeoutMiddleware: 요청 context에 deadline을 걸어서 client가 끊기거나 시간이 지나면 postgres, redis 호출이 취소되게 함
+// websocket, SSE처럼 오래 유지되는 연결에는 걸지 않음 (timeout이 0이면 사용하지 않음)
+func RequestTimeoutMiddleware(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if timeout <= 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isLongLived(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+
+			if ctx.Err() == context.DeadlineExceeded {
+				logger.FromContext(ctx).Warn("request deadline exceeded", logger.F("method", r.Method), logger.F("path", r.URL.Path),
+					logger.F("timeout_ms", timeout.Milliseconds()))
+			}
+		})
+	}
+}
+
+// isLongLived websocket(/ws)과 SSE(/chat/stream/{chat_room_name}) 연결인지 확인
+// proxy를 거치면 /api 아래로 들어오고, 헤더를 안 보내는 client도 있으므로 경로로도 확인함
+func isLongLived(r *http.Request) bool {
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		return true
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api")
+	return path == "/ws" || strings.HasPrefix(path, "/chat/stream/")
+}
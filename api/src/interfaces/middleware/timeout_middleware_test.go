@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequestTimeoutMiddleware(t *testing.T) {
+	var hasDeadline bool
+	handler := RequestTimeoutMiddleware(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, hasDeadline = r.Context().Deadline()
+	}))
+
+	tests := []struct {
+		name     string
+		path     string
+		header   string
+		value    string
+		deadline bool
+	}{
+		{"api request", "/chat/inbox", "", "", true},
+		{"api request through proxy", "/api/chat/messages/chat_room_id=1&before=10&limit=20", "", "", true},
+		{"websocket", "/ws", "Upgrade", "websocket", false},
+		{"websocket through proxy", "/api/ws", "Upgrade", "websocket", false},
+		{"websocket without upgrade header", "/ws", "", "", false},
+		{"sse", "/chat/stream/room", "Accept", "text/event-stream", false},
+		{"sse through proxy", "/api/chat/stream/room", "Accept", "text/event-stream", false},
+		{"sse without accept header", "/chat/stream/room", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if hasDeadline != tt.deadline {
+				t.Errorf("deadline set %v, want %v", hasDeadline, tt.deadline)
+			}
+		})
+	}
+}
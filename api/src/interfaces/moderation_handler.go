@@ -0,0 +1,176 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/code-wave/go-wave/application"
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/helpers"
+	"github.com/code-wave/go-wave/interfaces/middleware"
+)
+
+type ModerationHandler struct {
+	ma application.ModerationInterface
+}
+
+func NewModerationHandler(ma application.ModerationInterface) *ModerationHandler {
+	return &ModerationHandler{
+		ma: ma,
+	}
+}
+
+// BlockUser: body의 user_id 유저를 차단
+func (h *ModerationHandler) BlockUser(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	userID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	var req struct {
+		UserID int64 `json:"user_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	if restErr := h.ma.BlockUser(r.Context(), userID, req.UserID); restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	result, _ := json.Marshal(map[string]string{"result": "success"})
+	w.WriteHeader(http.StatusOK)
+	w.Write(result)
+}
+
+func (h *ModerationHandler) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	userID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	blockedID, restErr := helpers.ExtractIntParam(r, "user_id")
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	if restErr := h.ma.UnblockUser(r.Context(), userID, blockedID); restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	result, _ := json.Marshal(map[string]string{"result": "success"})
+	w.WriteHeader(http.StatusOK)
+	w.Write(result)
+}
+
+func (h *ModerationHandler) GetBlockedUsers(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	userID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	blocks, restErr := h.ma.GetBlockedUsers(r.Context(), userID)
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	bJSON, _ := json.Marshal(map[string][]entity.UserBlock{"blocked_users": blocks})
+	w.WriteHeader(http.StatusOK)
+	w.Write(bJSON)
+}
+
+// ReportMessage: body의 chat_message_id, reason으로 메시지를 신고
+func (h *ModerationHandler) ReportMessage(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	userID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	var report entity.ChatMessageReport
+	if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+	report.ReporterID = userID
+
+	saved, restErr := h.ma.ReportMessage(r.Context(), &report)
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	rJSON, _ := json.Marshal(map[string]*entity.ChatMessageReport{"report": saved})
+	w.WriteHeader(http.StatusOK)
+	w.Write(rJSON)
+}
+
+// GetReports: 모더레이터가 status의 신고를 오래된 순으로 확인
+func (h *ModerationHandler) GetReports(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	userID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	limit, restErr := helpers.ExtractIntParam(r, "limit")
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	reports, restErr := h.ma.GetReports(r.Context(), userID, helpers.ExtractStringParam(r, "status"), limit)
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	rJSON, _ := json.Marshal(map[string][]entity.ChatMessageReport{"reports": reports})
+	w.WriteHeader(http.StatusOK)
+	w.Write(rJSON)
+}
+
+// ReviewReport: 모더레이터가 body의 status(dismissed | removed)로 신고를 처리
+func (h *ModerationHandler) ReviewReport(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	userID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	reportID, restErr := helpers.ExtractIntParam(r, "report_id")
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	var req struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	report, restErr := h.ma.ReviewReport(r.Context(), userID, reportID, req.Status)
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	rJSON, _ := json.Marshal(map[string]*entity.ChatMessageReport{"report": report})
+	w.WriteHeader(http.StatusOK)
+	w.Write(rJSON)
+}
@@ -0,0 +1,191 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/code-wave/go-wave/application"
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/helpers"
+	"github.com/code-wave/go-wave/interfaces/middleware"
+)
+
+type NotificationHandler struct {
+	na             application.NotificationInterface
+	vapidPublicKey string
+}
+
+// NewNotificationHandler vapidPublicKey는 브라우저가 web push를 구독할 때 쓰는 applicationServerKey (push를 쓰지 않으면 빈 값)
+func NewNotificationHandler(na application.NotificationInterface, vapidPublicKey string) *NotificationHandler {
+	return &NotificationHandler{
+		na:             na,
+		vapidPublicKey: vapidPublicKey,
+	}
+}
+
+// GetNotifications: 로그인한 유저의 최근 알림 목록
+func (h *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	userID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	limit, restErr := helpers.ExtractIntParam(r, "limit")
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	notifications, restErr := h.na.GetNotifications(r.Context(), userID, limit)
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	nJSON, err := json.Marshal(map[string][]entity.Notification{"notifications": notifications})
+	if err != nil {
+		restErr := errors.NewInternalServerError("marshal error " + err.Error())
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(nJSON)
+}
+
+// MarkNotificationsRead: notification_id까지의 알림을 읽음으로 표시
+func (h *NotificationHandler) MarkNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	userID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	notificationID, restErr := helpers.ExtractIntParam(r, "notification_id")
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	if restErr := h.na.MarkNotificationsRead(r.Context(), userID, notificationID); restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	result, _ := json.Marshal(map[string]string{"result": "success"})
+	w.WriteHeader(http.StatusOK)
+	w.Write(result)
+}
+
+func (h *NotificationHandler) GetPreference(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	userID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	preference, restErr := h.na.GetPreference(r.Context(), userID)
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	pJSON, _ := json.Marshal(map[string]*entity.NotificationPreference{"preference": preference})
+	w.WriteHeader(http.StatusOK)
+	w.Write(pJSON)
+}
+
+func (h *NotificationHandler) SavePreference(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	userID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	var preference entity.NotificationPreference
+	if err := json.NewDecoder(r.Body).Decode(&preference); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+	preference.UserID = userID
+
+	if restErr := h.na.SavePreference(r.Context(), &preference); restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	pJSON, _ := json.Marshal(map[string]*entity.NotificationPreference{"preference": &preference})
+	w.WriteHeader(http.StatusOK)
+	w.Write(pJSON)
+}
+
+// GetVAPIDPublicKey: web push 구독에 필요한 서버 공개키
+func (h *NotificationHandler) GetVAPIDPublicKey(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	if h.vapidPublicKey == "" {
+		restErr := errors.NewNotFoundError("web push is not enabled")
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	result, _ := json.Marshal(map[string]string{"public_key": h.vapidPublicKey})
+	w.WriteHeader(http.StatusOK)
+	w.Write(result)
+}
+
+// SavePushSubscription: 브라우저의 PushSubscription을 저장 (같은 endpoint면 갱신)
+func (h *NotificationHandler) SavePushSubscription(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	userID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	var subscription entity.PushSubscription
+	if err := json.NewDecoder(r.Body).Decode(&subscription); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+	subscription.UserID = userID
+
+	saved, restErr := h.na.SavePushSubscription(r.Context(), &subscription)
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	sJSON, _ := json.Marshal(map[string]*entity.PushSubscription{"push_subscription": saved})
+	w.WriteHeader(http.StatusOK)
+	w.Write(sJSON)
+}
+
+// DeletePushSubscription: body의 endpoint 구독을 삭제 (브라우저에서 구독을 해지했을 때)
+func (h *NotificationHandler) DeletePushSubscription(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	userID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	var subscription entity.PushSubscription
+	if err := json.NewDecoder(r.Body).Decode(&subscription); err != nil || subscription.Endpoint == "" {
+		restErr := errors.NewBadRequestError("endpoint is required")
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	if restErr := h.na.DeletePushSubscription(r.Context(), userID, subscription.Endpoint); restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	result, _ := json.Marshal(map[string]string{"result": "success"})
+	w.WriteHeader(http.StatusOK)
+	w.Write(result)
+}
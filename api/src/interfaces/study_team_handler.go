@@ -0,0 +1,115 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/code-wave/go-wave/application"
+	"github.com/code-wave/go-wave/domain/entity"
+	"github.com/code-wave/go-wave/infrastructure/errors"
+	"github.com/code-wave/go-wave/infrastructure/helpers"
+	"github.com/code-wave/go-wave/interfaces/middleware"
+)
+
+type StudyTeamHandler struct {
+	st application.StudyTeamInterface
+}
+
+func NewStudyTeamHandler(st application.StudyTeamInterface) *StudyTeamHandler {
+	return &StudyTeamHandler{
+		st: st,
+	}
+}
+
+// AddMember: host가 유저를 팀원으로 수락 (게시글의 팀 채팅룸에도 참여됨)
+func (h *StudyTeamHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	requesterID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	var member entity.StudyPostMember
+	if err := json.NewDecoder(r.Body).Decode(&member); err != nil {
+		restErr := errors.NewBadRequestError("invalid json body")
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+	defer r.Body.Close()
+
+	if restErr := h.st.AddMember(r.Context(), requesterID, &member); restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	mJSON, err := json.Marshal(map[string]entity.StudyPostMember{"member": member})
+	if err != nil {
+		restErr := errors.NewInternalServerError("marshal error " + err.Error())
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(mJSON)
+}
+
+// RemoveMember: host가 팀원을 내보내거나 팀원이 스스로 나감 (게시글의 팀 채팅룸에서도 빠짐)
+func (h *StudyTeamHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	requesterID := r.Context().Value(middleware.ContextKeyTokenUserID).(int64)
+
+	studyPostID, restErr := helpers.ExtractIntParam(r, "study_post_id")
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	userID, restErr := helpers.ExtractIntParam(r, "user_id")
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	if restErr = h.st.RemoveMember(r.Context(), requesterID, studyPostID, userID); restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	result, _ := json.Marshal(map[string]string{"result": "success"})
+	w.WriteHeader(http.StatusOK)
+	w.Write(result)
+}
+
+func (h *StudyTeamHandler) GetMembers(w http.ResponseWriter, r *http.Request) {
+	helpers.SetJsonHeader(w)
+
+	studyPostID, restErr := helpers.ExtractIntParam(r, "study_post_id")
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	members, restErr := h.st.GetMembers(r.Context(), studyPostID)
+	if restErr != nil {
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	mJSON, err := json.Marshal(map[string]entity.StudyPostMembers{"members": members})
+	if err != nil {
+		restErr := errors.NewInternalServerError("marshal error " + err.Error())
+		w.WriteHeader(restErr.Status)
+		w.Write(restErr.ResponseJSON().([]byte))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(mJSON)
+}
@@ -2,11 +2,11 @@ package interfaces
 
 import (
 	"encoding/json"
-	"fmt"
 	"github.com/code-wave/go-wave/application"
 	"github.com/code-wave/go-wave/domain/entity"
 	"github.com/code-wave/go-wave/infrastructure/errors"
 	"github.com/code-wave/go-wave/infrastructure/helpers"
+	"github.com/code-wave/go-wave/infrastructure/logger"
 	"net/http"
 )
 
@@ -35,8 +35,6 @@ func (s *StudyPost) SavePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fmt.Println("studypost: ", studyPost) // check용 나중에 삭제
-
 	restErr := studyPost.Validate(r.Method)
 	if restErr != nil {
 		w.WriteHeader(restErr.Status)
@@ -44,12 +42,13 @@ func (s *StudyPost) SavePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	restErr = s.sp.SavePost(&studyPost)
+	restErr = s.sp.SavePost(r.Context(), &studyPost)
 	if restErr != nil {
 		w.WriteHeader(restErr.Status)
 		w.Write(restErr.ResponseJSON().([]byte))
 		return
 	}
+	logger.FromContext(r.Context()).Info("study post saved", logger.F("study_post_id", studyPost.ID), logger.F("user_id", studyPost.UserID))
 
 	sJson, restErr := studyPost.ResponseJSON()
 	if restErr != nil {
@@ -72,7 +71,7 @@ func (s *StudyPost) GetPost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	studyPost, restErr := s.sp.GetPost(studyPostID)
+	studyPost, restErr := s.sp.GetPost(r.Context(), studyPostID)
 	if restErr != nil {
 		w.WriteHeader(restErr.Status)
 		w.Write(restErr.ResponseJSON().([]byte))
@@ -107,7 +106,7 @@ func (s *StudyPost) GetPostsInLatestOrder(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	studyPosts, err := s.sp.GetPostsInLatestOrder(limit, offset)
+	studyPosts, err := s.sp.GetPostsInLatestOrder(r.Context(), limit, offset)
 	if err != nil {
 		w.WriteHeader(err.Status)
 		w.Write(err.ResponseJSON().([]byte))
@@ -149,7 +148,7 @@ func (s *StudyPost) GetPostsByUserID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	studyPosts, err := s.sp.GetPostsByUserID(userID, limit, offset)
+	studyPosts, err := s.sp.GetPostsByUserID(r.Context(), userID, limit, offset)
 	if err != nil {
 		w.WriteHeader(err.Status)
 		w.Write(err.ResponseJSON().([]byte))
@@ -187,7 +186,7 @@ func (s *StudyPost) UpdatePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	updatedPost, restErr := s.sp.UpdatePost(&studyPost)
+	updatedPost, restErr := s.sp.UpdatePost(r.Context(), &studyPost)
 	if restErr != nil {
 		w.WriteHeader(restErr.Status)
 		w.Write(restErr.ResponseJSON().([]byte))
@@ -215,7 +214,7 @@ func (s *StudyPost) DeletePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = s.sp.DeletePost(studyPostID)
+	err = s.sp.DeletePost(r.Context(), studyPostID)
 	if err != nil {
 		w.WriteHeader(err.Status)
 		w.Write(err.ResponseJSON().([]byte))
@@ -32,7 +32,7 @@ func (t *TechStack) SaveTechStack(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = t.ts.SaveTechStack(techStack.TechName)
+	err = t.ts.SaveTechStack(r.Context(), techStack.TechName)
 	if err != nil {
 		w.WriteHeader(err.Status)
 		w.Write(err.ResponseJSON().([]byte))
@@ -53,7 +53,7 @@ func (t *TechStack) GetTechStack(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	techStack, err := t.ts.GetTechStack(id)
+	techStack, err := t.ts.GetTechStack(r.Context(), id)
 	if err != nil {
 		w.WriteHeader(err.Status)
 		w.Write(err.ResponseJSON().([]byte))
@@ -74,7 +74,7 @@ func (t *TechStack) GetTechStack(w http.ResponseWriter, r *http.Request) {
 func (t *TechStack) GetAllTechStack(w http.ResponseWriter, r *http.Request) {
 	helpers.SetJsonHeader(w)
 
-	techStacks, err := t.ts.GetAllTechStack()
+	techStacks, err := t.ts.GetAllTechStack(r.Context())
 	if err != nil {
 		w.WriteHeader(err.Status)
 		w.Write(err.ResponseJSON().([]byte))
@@ -102,7 +102,7 @@ func (t *TechStack) GetAllTechStackByStudyPostID(w http.ResponseWriter, r *http.
 		return
 	}
 
-	techStacks, err := t.ts.GetAllTechStackByStudyPostID(studyPostID)
+	techStacks, err := t.ts.GetAllTechStackByStudyPostID(r.Context(), studyPostID)
 	if err != nil {
 		w.WriteHeader(err.Status)
 		w.Write(err.ResponseJSON().([]byte))
@@ -125,7 +125,7 @@ func (t *TechStack) DeleteTechStack(w http.ResponseWriter, r *http.Request) {
 
 	techName := helpers.ExtractStringParam(r, "tech_name")
 
-	err := t.ts.DeleteTechStack(techName)
+	err := t.ts.DeleteTechStack(r.Context(), techName)
 	if err != nil {
 		w.WriteHeader(err.Status)
 		w.Write(err.ResponseJSON().([]byte))
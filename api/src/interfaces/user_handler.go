@@ -32,7 +32,7 @@ func (uh *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := uh.ua.GetUser(userID)
+	user, err := uh.ua.GetUser(r.Context(), userID)
 	if err != nil {
 		w.WriteHeader(err.Status)
 		w.Write(err.ResponseJSON().([]byte))
@@ -60,7 +60,7 @@ func (uh *UserHandler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	users, err := uh.ua.GetAllUsers(limit, offset)
+	users, err := uh.ua.GetAllUsers(r.Context(), limit, offset)
 	if err != nil {
 		w.WriteHeader(err.Status)
 		w.Write(err.ResponseJSON().([]byte))
@@ -83,7 +83,7 @@ func (uh *UserHandler) CheckDuplicatedEmail(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	if duplicatedErr := uh.ua.CheckDuplicatedEmail(u.Email); duplicatedErr != nil {
+	if duplicatedErr := uh.ua.CheckDuplicatedEmail(r.Context(), u.Email); duplicatedErr != nil {
 		w.WriteHeader(duplicatedErr.Status)
 		w.Write([]byte(duplicatedErr.Message))
 		return
@@ -103,7 +103,7 @@ func (uh *UserHandler) CheckDuplicatedNickname(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	if duplicatedErr := uh.ua.CheckDuplicatedNickname(u.Nickname); duplicatedErr != nil {
+	if duplicatedErr := uh.ua.CheckDuplicatedNickname(r.Context(), u.Nickname); duplicatedErr != nil {
 		w.WriteHeader(duplicatedErr.Status)
 		w.Write([]byte(duplicatedErr.Message))
 		return
@@ -124,7 +124,7 @@ func (uh *UserHandler) SaveUser(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
-	newUser, err := uh.ua.SaveUser(&u)
+	newUser, err := uh.ua.SaveUser(r.Context(), &u)
 	if err != nil {
 		// //이 부분 처리는 이메일 중복확인 handler에서 처리
 		// if strings.Contains(err.Message, "duplicated email") {
@@ -159,7 +159,7 @@ func (uh *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
 	u.ID = userID
-	updateUser, err := uh.ua.UpdateUser(&u)
+	updateUser, err := uh.ua.UpdateUser(r.Context(), &u)
 	if err != nil {
 		w.WriteHeader(err.Status)
 		w.Write(err.ResponseJSON().([]byte))
@@ -179,7 +179,7 @@ func (uh *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		w.Write(err.ResponseJSON().([]byte))
 		return
 	}
-	if err := uh.ua.DeleteUser(userID); err != nil {
+	if err := uh.ua.DeleteUser(r.Context(), userID); err != nil {
 		w.WriteHeader(err.Status)
 		w.Write(err.ResponseJSON().([]byte))
 		return
@@ -1,13 +1,25 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/code-wave/go-wave/domain/repository"
 	"github.com/code-wave/go-wave/infrastructure/chat"
+	"github.com/code-wave/go-wave/infrastructure/notification"
 
 	"github.com/code-wave/go-wave/application"
+	"github.com/code-wave/go-wave/infrastructure/auth"
+	"github.com/code-wave/go-wave/infrastructure/logger"
+	"github.com/code-wave/go-wave/infrastructure/metrics"
 	"github.com/code-wave/go-wave/infrastructure/persistence"
+	"github.com/code-wave/go-wave/infrastructure/tracing"
 	"github.com/code-wave/go-wave/interfaces"
 	"github.com/code-wave/go-wave/interfaces/middleware"
 	"github.com/code-wave/go-wave/utils/config"
@@ -16,24 +28,135 @@ import (
 	"github.com/rs/cors"
 )
 
+// newRepositories cfg의 postgres 설정으로 DB에 연결
+func newRepositories(cfg *config.Config) (*persistence.Repositories, error) {
+	pg := cfg.Postgres
+	return persistence.NewRepositories("pgx", pg.Host, pg.Port, pg.User, pg.Password, pg.DBName, persistence.DBPool{
+		MaxOpenConns:    pg.MaxOpenConns,
+		MaxIdleConns:    pg.MaxIdleConns,
+		ConnMaxLifetime: pg.ConnMaxLifetime,
+	})
+}
+
 func main() {
-	services, err := persistence.NewRepositories("pgx", config.Host, config.Port, config.DBUser, config.DBPassword, config.DBName)
+	cfg, args, err := config.Load(os.Args[1:])
+	if err == flag.ErrHelp {
+		return
+	}
 	if err != nil {
-		log.Println(err)
+		logger.Fatal("failed to load config", logger.Err(err))
+	}
+
+	level, _ := logger.ParseLevel(cfg.Log.Level) // Validate에서 확인함
+	logger.SetDefault(logger.New(os.Stderr, level, cfg.Log.Format))
+	// log 패키지로 남기는 라이브러리 로그도 같은 형식으로 남김
+	log.SetFlags(0)
+	log.SetOutput(logger.Default().Writer(logger.LevelInfo))
+
+	if len(args) > 0 {
+		if args[0] != "migrate" {
+			logger.Fatal("unknown command", logger.F("command", args[0]))
+		}
+		if err := runMigrate(cfg, args[1:]); err != nil {
+			logger.Fatal("migrate failed", logger.Err(err))
+		}
+		return
+	}
+
+	shutdownTracing, err := tracing.Setup(tracing.Options{
+		ServiceName:  cfg.Tracing.ServiceName,
+		Environment:  cfg.Env,
+		Exporter:     cfg.Tracing.Exporter,
+		File:         cfg.Tracing.File,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		OTLPInsecure: cfg.Tracing.OTLPInsecure,
+		SampleRatio:  cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		logger.Fatal("failed to set up tracing", logger.Err(err))
+	}
+
+	auth.JwtWrapper = auth.NewJwtInfo(cfg.Token.AccessTokenKey, cfg.Token.RefreshTokenKey, cfg.Token.Issuer,
+		cfg.Token.AccessTokenTTL, cfg.Token.RefreshTokenTTL)
+
+	services, err := newRepositories(cfg)
+	if err != nil {
+		logger.Error("failed to connect db", logger.Err(err))
 		return
 	}
 	defer services.Close()
 
-	redisService, err := persistence.NewRedisDB(config.RedisHost, config.RedisPort, config.RedisPassword)
+	if cfg.Postgres.AutoMigrate {
+		migrator, err := services.Migrator()
+		if err == nil {
+			err = migrator.Up(0)
+		}
+		if err != nil {
+			logger.Error("failed to migrate db", logger.Err(err))
+			return
+		}
+	}
+
+	redisService, err := persistence.NewRedisDB(cfg.Redis.Host, cfg.Redis.Port, cfg.Redis.Password)
 	if err != nil {
-		log.Println(err)
+		logger.Error("failed to connect redis", logger.Err(err))
 		return
 	}
+	defer redisService.Close()
+
+	//metrics
+	metrics.RegisterDB(services.DB(), cfg.Postgres.DBName)
+	redisService.RClient.AddHook(metrics.RedisHook{})
+	redisService.RClient.AddHook(tracing.RedisHook{})
 
 	chatServer := chat.NewChatServer(redisService, services.Chat)
+	chatServer.MessageEditWindow = cfg.Chat.MessageEditWindow
+	chatServer.MaxFrameSize = cfg.Chat.MaxFrameSize
+	chatServer.SendBufferSize = cfg.Chat.SendBufferSize
+	if cfg.Chat.RoomBus == "pubsub" {
+		chatServer.Bus = chat.NewRedisPubSubBus(redisService.RClient)
+		chatServer.EphemeralBus = nil // 같은 채널을 두 번 구독하지 않도록 Bus로 보냄
+	}
+
+	//notification
+	var mailer repository.Mailer = &notification.LogMailer{}
+	if cfg.Notification.SMTPHost != "" {
+		mailer = notification.NewSMTPMailer(cfg.Notification.SMTPHost, cfg.Notification.SMTPPort,
+			cfg.Notification.SMTPUsername, cfg.Notification.SMTPPassword, cfg.Notification.MailFrom)
+	}
+	var pusher repository.WebPusher = &notification.LogWebPusher{}
+	var vapidPublicKey string
+	if cfg.Notification.VAPIDPrivateKey != "" {
+		vapidPusher, err := notification.NewVAPIDPusher(cfg.Notification.VAPIDPrivateKey, cfg.Notification.VAPIDSubject)
+		if err != nil {
+			logger.Error("invalid vapid private key", logger.Err(err))
+			return
+		}
+		pusher = vapidPusher
+		vapidPublicKey = vapidPusher.PublicKey()
+	}
+	notificationLimiter := persistence.NewNotificationLimiter(redisService.RClient, cfg.Notification.RateWindow)
+	notificationApp := application.NewNotificationApp(services.Notification, services.Chat, redisService.Presence, notificationLimiter, mailer, pusher)
+	notificationApp.Run(cfg.Notification.DigestInterval)
+
+	chatServer.Notifier = notificationApp
+	chatServer.MaxMessageLength = cfg.Chat.MaxMessageLength
+	chatServer.RateLimiter = persistence.NewMessageRateLimiter(redisService.RClient, cfg.Chat.RateLimit, cfg.Chat.RateWindow)
+	chatServer.Filter = chat.NewContentFilter(cfg.Chat.BannedWords, cfg.Chat.BlockLinks)
+	chatServer.BlockRepo = services.UserBlock
 	go chatServer.Run()
 
 	r := chi.NewRouter()
+	//health
+	healthApp := application.NewHealthApp(map[string]application.DependencyChecker{
+		"postgres": services,
+		"redis":    redisService,
+	}, cfg.Server.HealthCheckTimeout)
+	healthHandler := interfaces.NewHealthHandler(healthApp)
+
+	r.Get("/healthz", healthHandler.Healthz)
+	r.Get("/readyz", healthHandler.Readyz)
+
 	//users
 	userApp := application.NewUserApp(services.User)
 	userHandler := interfaces.NewUserHandler(userApp)
@@ -56,7 +179,7 @@ func main() {
 	r.With(middleware.AuthVerifyMiddleware).Post("/auth/users/refresh", authHandler.Refresh)
 
 	//studyPost
-	studyPostApp := application.NewStudyPostApp(services.StudyPost, services.TechStack, services.StudyPostTechStack)
+	studyPostApp := application.NewStudyPostApp(services.StudyPost, services.TechStack, services.StudyPostTechStack, services.Tx)
 	studyPostHandler := interfaces.NewStudyPostHandler(studyPostApp)
 
 	r.Get("/study-post/{study_post_id}", studyPostHandler.GetPost)
@@ -66,6 +189,14 @@ func main() {
 	r.Patch("/study-post", studyPostHandler.UpdatePost)
 	r.Delete("/study-post/{study_post_id}", studyPostHandler.DeletePost)
 
+	//studyTeam
+	studyTeamApp := application.NewStudyTeamApp(services.StudyPost, services.StudyPostMember, services.User, services.Chat, services.UserBlock, services.Tx, chatServer)
+	studyTeamHandler := interfaces.NewStudyTeamHandler(studyTeamApp)
+
+	r.Get("/study-post/members/{study_post_id}", studyTeamHandler.GetMembers)
+	r.With(middleware.AuthVerifyMiddleware).Post("/study-post/member", studyTeamHandler.AddMember)
+	r.With(middleware.AuthVerifyMiddleware).Delete("/study-post/member/study_post_id={study_post_id}&user_id={user_id}", studyTeamHandler.RemoveMember)
+
 	//techStack
 	techStackApp := application.NewTechStackApp(services.TechStack)
 	techStackHandler := interfaces.NewTechStackHandler(techStackApp)
@@ -78,26 +209,135 @@ func main() {
 
 	//chat
 	chatApp := application.NewChatApp(services.Chat)
-	chatHandler := interfaces.NewChatHandler(userApp, studyPostApp, chatApp)
+	presenceApp := application.NewPresenceApp(redisService.Presence, services.Chat)
+	moderationApp := application.NewModerationApp(services.UserBlock, services.ChatReport, services.User, services.Chat, chatServer, cfg.Chat.ModeratorUserIDs)
+	chatHandler := interfaces.NewChatHandler(userApp, studyPostApp, chatApp, presenceApp, moderationApp, cfg.Server.CORSAllowedOrigins)
 
-	r.Post("/chat/chatroom-info", chatHandler.GetChatRoomInfo)
-	r.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+	r.With(middleware.AuthVerifyMiddleware).Post("/chat/chatroom-info", chatHandler.GetChatRoomInfo)
+	r.With(middleware.AuthVerifyMiddleware).Get("/chat/messages/chat_room_id={chat_room_id}&before={message_id}&limit={limit}", chatHandler.GetChatMessagesBefore)
+	r.With(middleware.AuthVerifyMiddleware).Get("/chat/messages/chat_room_id={chat_room_id}&after={message_id}&limit={limit}", chatHandler.GetChatMessagesAfter)
+	r.With(middleware.AuthVerifyMiddleware).Get("/chat/message-edits/message_id={message_id}", chatHandler.GetChatMessageEdits)
+	r.With(middleware.AuthVerifyMiddleware).Get("/chat/chatrooms/unread", chatHandler.GetUnreadChatRooms)
+	r.With(middleware.AuthVerifyMiddleware).Get("/chat/inbox", chatHandler.GetChatInbox)
+	r.With(middleware.AuthVerifyMiddleware).Get("/chat/presence/user_id={user_id}", chatHandler.GetPresence)
+	r.With(middleware.AuthVerifyMiddleware).Get("/chat/transcript/chat_room_id={chat_room_id}&format={format}", chatHandler.ExportChatTranscript)
+	r.With(middleware.AuthVerifyMiddleware).HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
 		chatHandler.ServeChatWs(chatServer, w, r)
 	})
+	r.With(middleware.AuthVerifyMiddleware).Get("/chat/stream/{chat_room_name}", func(w http.ResponseWriter, r *http.Request) {
+		chatHandler.StreamChatRoom(chatServer, w, r)
+	})
+	r.With(middleware.AuthVerifyMiddleware).Post("/chat/message", func(w http.ResponseWriter, r *http.Request) {
+		chatHandler.SendChatMessage(chatServer, w, r)
+	})
+
+	//chat attachment
+	blobStore, err := persistence.NewLocalBlobStore(cfg.Attachment.Dir)
+	if err != nil {
+		logger.Error("failed to create attachment dir", logger.Err(err))
+		return
+	}
+	chatAttachmentApp := application.NewChatAttachmentApp(services.ChatAttachment, services.Chat, blobStore,
+		cfg.Attachment.MaxSize, cfg.Attachment.URLKey, cfg.Attachment.URLTTL)
+	chatAttachmentHandler := interfaces.NewChatAttachmentHandler(chatAttachmentApp)
+
+	r.With(middleware.AuthVerifyMiddleware).Post("/chat/attachment", chatAttachmentHandler.UploadAttachment)
+	r.With(middleware.AuthVerifyMiddleware).Get("/chat/attachment-url/{attachment_id}", chatAttachmentHandler.GetAttachmentURL)
+	r.Get("/chat/attachment/attachment_id={attachment_id}&user_id={user_id}&expires={expires}&signature={signature}", chatAttachmentHandler.DownloadAttachment)
+
+	//moderation
+	moderationHandler := interfaces.NewModerationHandler(moderationApp)
+
+	r.With(middleware.AuthVerifyMiddleware).Post("/users/block", moderationHandler.BlockUser)
+	r.With(middleware.AuthVerifyMiddleware).Delete("/users/block/{user_id}", moderationHandler.UnblockUser)
+	r.With(middleware.AuthVerifyMiddleware).Get("/users/blocks", moderationHandler.GetBlockedUsers)
+	r.With(middleware.AuthVerifyMiddleware).Post("/chat/report", moderationHandler.ReportMessage)
+	r.With(middleware.AuthVerifyMiddleware).Get("/moderation/reports/status={status}&limit={limit}", moderationHandler.GetReports)
+	r.With(middleware.AuthVerifyMiddleware).Patch("/moderation/reports/{report_id}", moderationHandler.ReviewReport)
+
+	//notification
+	notificationHandler := interfaces.NewNotificationHandler(notificationApp, vapidPublicKey)
+
+	r.With(middleware.AuthVerifyMiddleware).Get("/notifications/limit={limit}", notificationHandler.GetNotifications)
+	r.With(middleware.AuthVerifyMiddleware).Patch("/notifications/read/{notification_id}", notificationHandler.MarkNotificationsRead)
+	r.With(middleware.AuthVerifyMiddleware).Get("/notifications/preference", notificationHandler.GetPreference)
+	r.With(middleware.AuthVerifyMiddleware).Put("/notifications/preference", notificationHandler.SavePreference)
+	r.Get("/notifications/vapid-public-key", notificationHandler.GetVAPIDPublicKey)
+	r.With(middleware.AuthVerifyMiddleware).Post("/notifications/push-subscription", notificationHandler.SavePushSubscription)
+	r.With(middleware.AuthVerifyMiddleware).Delete("/notifications/push-subscription", notificationHandler.DeletePushSubscription)
 
 	r.Mount("/api", r)
 
 	// cors option
 	c := cors.New(cors.Options{
-		// proxy server 주소만 origin 허용
-		// AllowedOrigins: []string{"http://localhost:8081"},
-		AllowedOrigins:   []string{"*"},
+		// prod에서는 proxy server 주소만 origin 허용
+		AllowedOrigins:   cfg.Server.CORSAllowedOrigins,
 		AllowCredentials: true,
-		// Enable Debugging for testing, consider disabling in production
-		Debug: true,
+		ExposedHeaders:   []string{middleware.RequestIDHeader},
+		Debug:            cfg.Env == config.EnvDev,
 	})
-	handler := cors.Default().Handler(r)
-	handler = c.Handler(handler)
+	// /metrics는 router 밖에 둬서 proxy가 넘기는 /api 아래로는 노출하지 않음
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	handler := middleware.RequestTimeoutMiddleware(cfg.Server.RequestTimeout)(r)
+	mux.Handle("/", middleware.RequestIDMiddleware(c.Handler(middleware.TracingMiddleware(middleware.AccessLogMiddleware(middleware.MetricsMiddleware(handler))))))
+
+	srv := &http.Server{
+		Addr:     cfg.Server.Addr,
+		Handler:  mux,
+		ErrorLog: log.New(logger.Default().Writer(logger.LevelWarn), "", 0),
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		logger.Info("listening", logger.F("addr", cfg.Server.Addr), logger.F("env", cfg.Env))
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	// SIGINT, SIGTERM을 받으면 연결을 정리한 후 종료 (한 번 더 받으면 바로 종료)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	select {
+	case err := <-serveErr:
+		logger.Error("http server error", logger.Err(err))
+	case <-ctx.Done():
+		stop()
+	}
+
+	// readiness를 먼저 false로 바꾸고 proxy가 이 인스턴스를 빼는 동안에는 요청을 계속 처리함
+	healthApp.SetShuttingDown()
+	time.Sleep(cfg.Server.ShutdownDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	shutdown(shutdownCtx, srv, chatServer, notificationApp, shutdownTracing)
+}
+
+// shutdown: 새 연결을 받지 않고 처리 중인 요청, 채팅 연결, 알림을 정리함 (SSE 요청은 채팅 서버가 끊어야 끝나므로 같이 닫음)
+// 남은 span은 마지막에 내보내고, redis와 DB는 main의 defer로 마지막에 닫음
+func shutdown(ctx context.Context, srv *http.Server, chatServer *chat.ChatServer, notificationApp application.NotificationInterface,
+	shutdownTracing func(context.Context) error) {
+	logger.Info("shutting down...")
+
+	httpErr := make(chan error, 1)
+	go func() {
+		httpErr <- srv.Shutdown(ctx)
+	}()
+
+	if err := chatServer.Shutdown(ctx); err != nil {
+		logger.Error("chat server shutdown error", logger.Err(err))
+	}
+	if err := <-httpErr; err != nil {
+		logger.Error("http server shutdown error", logger.Err(err))
+	}
+	if err := notificationApp.Shutdown(ctx); err != nil {
+		logger.Error("notification shutdown error", logger.Err(err))
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Error("tracing shutdown error", logger.Err(err))
+	}
 
-	log.Fatal(http.ListenAndServe(":8080", handler))
+	logger.Info("server stopped")
 }
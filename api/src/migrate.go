@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/code-wave/go-wave/utils/config"
+)
+
+const migrateUsage = `usage: go-wave [flags] migrate <command>
+  up [N]         적용하지 않은 migration을 N개 적용 (N이 없으면 모두)
+  down [N]       최근에 적용한 migration을 N개 되돌림 (N이 없으면 1개)
+  status         migration 적용 상태
+  force VERSION  migration을 실행하지 않고 VERSION까지 적용한 것으로 기록 (initdb.sh로 만든 기존 DB는 force 1)`
+
+// runMigrate: go-wave migrate 서브커맨드
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(migrateUsage)
+	}
+
+	var n int64
+	if len(args) > 1 {
+		var err error
+		if n, err = strconv.ParseInt(args[1], 10, 64); err != nil || n < 0 {
+			return fmt.Errorf("invalid number %q\n%s", args[1], migrateUsage)
+		}
+	}
+
+	services, err := newRepositories(cfg)
+	if err != nil {
+		return err
+	}
+	defer services.Close()
+
+	migrator, err := services.Migrator()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		return migrator.Up(int(n))
+	case "down":
+		if n == 0 {
+			n = 1
+		}
+		return migrator.Down(int(n))
+	case "force":
+		if len(args) < 2 {
+			return fmt.Errorf(migrateUsage)
+		}
+		return migrator.Force(n)
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.Applied {
+				applied = "applied " + status.AppliedAt
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, applied)
+		}
+		return nil
+	default:
+		return fmt.Errorf(migrateUsage)
+	}
+}
@@ -1,74 +1,284 @@
 package config
 
-import "os"
-
-//postgres env
-var (
-	Host       = os.Getenv("POSTGRES_HOST")
-	DBUser     = os.Getenv("POSTGRES_USER")
-	DBPassword = os.Getenv("POSTGRES_PASSWORD")
-	DBName     = os.Getenv("POSTGRES_DB")
-	Port       = os.Getenv("POSTGRES_PORT")
+import (
+	"fmt"
+	"strings"
+	"time"
 )
 
-//redis env
-var (
-	RedisHost     = os.Getenv("REDIS_HOST")
-	RedisPort     = os.Getenv("REDIS_PORT")
-	RedisPassword = os.Getenv("REDIS_PASSWORD")
+const (
+	EnvDev  = "dev"
+	EnvProd = "prod"
 )
 
-//token secret env
-var (
-	AccessTokenKey  = os.Getenv("ACCESS_TOKEN_SECRETE")
-	RefreshTokenKey = os.Getenv("REFRESH_TOKEN_SECRETE")
-	Issuer          = os.Getenv("TOKEN_ISSUER")
-)
+// Config 서버 설정, Load로 default < 설정 파일 < env < flag 순서로 덮어써서 만듦
+type Config struct {
+	Env          string             `yaml:"env" toml:"env"` // "dev" | "prod"
+	Server       ServerConfig       `yaml:"server" toml:"server"`
+	Log          LogConfig          `yaml:"log" toml:"log"`
+	Tracing      TracingConfig      `yaml:"tracing" toml:"tracing"`
+	Postgres     PostgresConfig     `yaml:"postgres" toml:"postgres"`
+	Redis        RedisConfig        `yaml:"redis" toml:"redis"`
+	Token        TokenConfig        `yaml:"token" toml:"token"`
+	Chat         ChatConfig         `yaml:"chat" toml:"chat"`
+	Attachment   AttachmentConfig   `yaml:"attachment" toml:"attachment"`
+	Notification NotificationConfig `yaml:"notification" toml:"notification"`
+}
+
+type ServerConfig struct {
+	Addr string `yaml:"addr" toml:"addr"`
+	// CORSAllowedOrigins: prod에서는 "*"를 쓸 수 없음 (AllowCredentials)
+	CORSAllowedOrigins []string `yaml:"cors_allowed_origins" toml:"cors_allowed_origins"`
+	// ShutdownTimeout: 종료 신호를 받은 후 연결을 정리하고 기다리는 최대 시간
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+	// ShutdownDelay: 종료할 때 readiness를 false로 바꾼 후 proxy가 알아챌 때까지 요청을 계속 받는 시간
+	ShutdownDelay time.Duration `yaml:"shutdown_delay" toml:"shutdown_delay"`
+	// HealthCheckTimeout: readiness 확인에서 postgres, redis 각각을 기다리는 최대 시간
+	HealthCheckTimeout time.Duration `yaml:"health_check_timeout" toml:"health_check_timeout"`
+	// RequestTimeout: 요청 하나가 postgres, redis를 쓸 수 있는 최대 시간 (websocket, SSE 제외, 0이면 제한 없음)
+	RequestTimeout time.Duration `yaml:"request_timeout" toml:"request_timeout"`
+}
+
+type LogConfig struct {
+	Level string `yaml:"level" toml:"level"` // "debug" | "info" | "warn" | "error"
+	// Format: "text" | "json", 비어 있으면 dev는 text, prod는 json
+	Format string `yaml:"format" toml:"format"`
+}
+
+type TracingConfig struct {
+	Exporter string `yaml:"exporter" toml:"exporter"` // "none" | "stdout" | "file" | "otlp"
+	// File: exporter가 "file"일 때 span을 JSON으로 이어 쓰는 파일
+	File string `yaml:"file" toml:"file"`
+	// OTLPEndpoint: OTLP/HTTP collector 주소 (host:port)
+	OTLPEndpoint string `yaml:"otlp_endpoint" toml:"otlp_endpoint"`
+	OTLPInsecure bool   `yaml:"otlp_insecure" toml:"otlp_insecure"`
+	// SampleRatio: 새로 시작하는 trace 중 남길 비율 (0~1), 부모 span이 있으면 부모를 따름
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio"`
+	ServiceName string  `yaml:"service_name" toml:"service_name"`
+}
+
+type PostgresConfig struct {
+	Host            string        `yaml:"host" toml:"host"`
+	Port            string        `yaml:"port" toml:"port"`
+	User            string        `yaml:"user" toml:"user"`
+	Password        string        `yaml:"password" toml:"password"`
+	DBName          string        `yaml:"db_name" toml:"db_name"`
+	MaxOpenConns    int           `yaml:"max_open_conns" toml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" toml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`
+	// AutoMigrate: 서버를 시작할 때 migration을 모두 적용 (dev)
+	AutoMigrate bool `yaml:"auto_migrate" toml:"auto_migrate"`
+}
+
+type RedisConfig struct {
+	Host     string `yaml:"host" toml:"host"`
+	Port     string `yaml:"port" toml:"port"`
+	Password string `yaml:"password" toml:"password"`
+}
+
+type TokenConfig struct {
+	AccessTokenKey  string        `yaml:"access_token_key" toml:"access_token_key"`
+	RefreshTokenKey string        `yaml:"refresh_token_key" toml:"refresh_token_key"`
+	Issuer          string        `yaml:"issuer" toml:"issuer"`
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl" toml:"access_token_ttl"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" toml:"refresh_token_ttl"`
+}
+
+type ChatConfig struct {
+	// MessageEditWindow: 메시지를 보낸 후 수정/삭제할 수 있는 시간
+	MessageEditWindow time.Duration `yaml:"message_edit_window" toml:"message_edit_window"`
+	RoomBus           string        `yaml:"room_bus" toml:"room_bus"` // "streams" | "pubsub"
+	// MaxFrameSize: websocket frame 최대 크기 (bytes)
+	MaxFrameSize int64 `yaml:"max_frame_size" toml:"max_frame_size"`
+	// SendBufferSize: 유저 한 명의 send queue에 쌓아둘 수 있는 메시지 수
+	SendBufferSize   int           `yaml:"send_buffer_size" toml:"send_buffer_size"`
+	MaxMessageLength int           `yaml:"max_message_length" toml:"max_message_length"` // 글자 수
+	RateLimit        int64         `yaml:"rate_limit" toml:"rate_limit"`                 // RateWindow 동안 보낼 수 있는 메시지 수
+	RateWindow       time.Duration `yaml:"rate_window" toml:"rate_window"`
+	BannedWords      []string      `yaml:"banned_words" toml:"banned_words"`
+	BlockLinks       bool          `yaml:"block_links" toml:"block_links"` // 링크가 있는 메시지를 거부
+	// ModeratorUserIDs: 신고를 확인하고 처리할 수 있는 유저
+	ModeratorUserIDs []int64 `yaml:"moderator_user_ids" toml:"moderator_user_ids"`
+}
+
+type AttachmentConfig struct {
+	Dir     string `yaml:"dir" toml:"dir"`
+	MaxSize int64  `yaml:"max_size" toml:"max_size"` // bytes
+	// URLKey: 다운로드 URL 서명 키, token 키와 따로 둬야 함
+	URLKey string        `yaml:"url_key" toml:"url_key"`
+	URLTTL time.Duration `yaml:"url_ttl" toml:"url_ttl"`
+}
+
+type NotificationConfig struct {
+	SMTPHost     string `yaml:"smtp_host" toml:"smtp_host"` // 비어 있으면 메일 대신 로그를 남김
+	SMTPPort     string `yaml:"smtp_port" toml:"smtp_port"`
+	SMTPUsername string `yaml:"smtp_username" toml:"smtp_username"`
+	SMTPPassword string `yaml:"smtp_password" toml:"smtp_password"`
+	MailFrom     string `yaml:"mail_from" toml:"mail_from"`
+	// VAPIDPrivateKey: base64url P-256 개인키, 비어 있으면 push 대신 로그를 남김
+	VAPIDPrivateKey string `yaml:"vapid_private_key" toml:"vapid_private_key"`
+	VAPIDSubject    string `yaml:"vapid_subject" toml:"vapid_subject"` // ex) mailto:admin@example.com
+	// RateWindow: 같은 채팅룸의 알림을 한 유저에게 다시 보내기까지의 시간
+	RateWindow time.Duration `yaml:"rate_window" toml:"rate_window"`
+	// DigestInterval: 이메일 요약을 보내는 주기
+	DigestInterval time.Duration `yaml:"digest_interval" toml:"digest_interval"`
+}
 
-//postgres config
-func postgresInit() {
-	// dbDriver := "pgx"
-	host := "127.0.0.1"
-	dbUser := "project"
-	dbPassword := "password"
-	dbName := "projectdb"
-	port := "54320"
-
-	if Host == "" {
-		Host = host
+// Default 비밀값을 뺀 기본 설정 (docker-compose.dev.yml의 포트 기준)
+func Default() *Config {
+	return &Config{
+		Env: EnvDev,
+		Server: ServerConfig{
+			Addr:               ":8080",
+			CORSAllowedOrigins: []string{"*"},
+			ShutdownTimeout:    10 * time.Second,
+			HealthCheckTimeout: 2 * time.Second,
+			RequestTimeout:     15 * time.Second,
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+		Tracing: TracingConfig{
+			Exporter:     "none",
+			OTLPEndpoint: "localhost:4318",
+			SampleRatio:  1,
+			ServiceName:  "go-wave-api",
+		},
+		Postgres: PostgresConfig{
+			Host:            "127.0.0.1",
+			Port:            "54320",
+			User:            "project",
+			DBName:          "projectdb",
+			MaxOpenConns:    10,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 5 * time.Minute,
+		},
+		Redis: RedisConfig{
+			Host: "127.0.0.1",
+			Port: "56379",
+		},
+		Token: TokenConfig{
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 7 * 24 * time.Hour,
+		},
+		Chat: ChatConfig{
+			MessageEditWindow: 15 * time.Minute,
+			RoomBus:           "streams",
+			MaxFrameSize:      10000,
+			SendBufferSize:    256,
+			MaxMessageLength:  2000,
+			RateLimit:         20,
+			RateWindow:        10 * time.Second,
+		},
+		Attachment: AttachmentConfig{
+			Dir:     "./attachments",
+			MaxSize: 10 << 20, // 10MB
+			URLTTL:  10 * time.Minute,
+		},
+		Notification: NotificationConfig{
+			SMTPPort:       "587",
+			MailFrom:       "no-reply@go-wave.com",
+			VAPIDSubject:   "mailto:no-reply@go-wave.com",
+			RateWindow:     5 * time.Minute,
+			DigestInterval: time.Hour,
+		},
 	}
-	if DBUser == "" {
-		DBUser = dbUser
+}
+
+// devSecrets dev에서 비어 있는 비밀값을 docker-compose.dev.yml의 값으로 채움 (prod에서는 채우지 않음)
+func (cfg *Config) devSecrets() []string {
+	var filled []string
+	fill := func(value *string, name, devValue string) {
+		if *value == "" {
+			*value = devValue
+			filled = append(filled, name)
+		}
 	}
-	if DBPassword == "" {
-		DBPassword = dbPassword
+
+	fill(&cfg.Postgres.Password, "POSTGRES_PASSWORD", "password")
+	fill(&cfg.Redis.Password, "REDIS_PASSWORD", "redis_password")
+	fill(&cfg.Token.AccessTokenKey, "ACCESS_TOKEN_KEY", "access_token")
+	fill(&cfg.Token.RefreshTokenKey, "REFRESH_TOKEN_KEY", "refresh_token")
+	fill(&cfg.Attachment.URLKey, "ATTACHMENT_URL_KEY", "attachment_url")
+
+	return filled
+}
+
+// Validate 잘못된 설정을 모두 모아서 반환
+func (cfg *Config) Validate() error {
+	var errs []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Sprintf(format, args...))
+		}
 	}
-	if DBName == "" {
-		DBName = dbName
+
+	check(cfg.Env == EnvDev || cfg.Env == EnvProd, "env must be %q or %q, got %q", EnvDev, EnvProd, cfg.Env)
+	check(cfg.Server.Addr != "", "server addr is required")
+	check(len(cfg.Server.CORSAllowedOrigins) > 0, "cors allowed origins are required")
+	check(cfg.Server.ShutdownTimeout > 0, "shutdown timeout must be positive")
+	check(cfg.Server.ShutdownDelay >= 0, "shutdown delay can't be negative")
+	check(cfg.Server.HealthCheckTimeout > 0, "health check timeout must be positive")
+	check(cfg.Server.RequestTimeout >= 0, "request timeout can't be negative")
+
+	switch cfg.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		check(false, "log level must be one of debug, info, warn, error, got %q", cfg.Log.Level)
 	}
-	if Port == "" {
-		Port = port
+	check(cfg.Log.Format == "text" || cfg.Log.Format == "json", "log format must be \"text\" or \"json\", got %q", cfg.Log.Format)
+
+	switch cfg.Tracing.Exporter {
+	case "none", "stdout", "file", "otlp":
+	default:
+		check(false, "tracing exporter must be one of none, stdout, file, otlp, got %q", cfg.Tracing.Exporter)
 	}
-}
+	check(cfg.Tracing.Exporter != "file" || cfg.Tracing.File != "", "tracing file is required for the file exporter")
+	check(cfg.Tracing.Exporter != "otlp" || cfg.Tracing.OTLPEndpoint != "", "tracing otlp endpoint is required for the otlp exporter")
+	check(cfg.Tracing.SampleRatio >= 0 && cfg.Tracing.SampleRatio <= 1, "tracing sample ratio must be between 0 and 1")
+	check(cfg.Tracing.ServiceName != "", "tracing service name is required")
 
-//redis config
-func redisInit() {
-	host := "127.0.0.1"
-	password := "redis_password"
-	port := "56379"
+	check(cfg.Postgres.Host != "" && cfg.Postgres.Port != "", "postgres host and port are required")
+	check(cfg.Postgres.User != "" && cfg.Postgres.DBName != "", "postgres user and db name are required")
+	check(cfg.Postgres.MaxOpenConns > 0, "postgres max open conns must be positive")
+	check(cfg.Postgres.MaxIdleConns >= 0 && cfg.Postgres.MaxIdleConns <= cfg.Postgres.MaxOpenConns,
+		"postgres max idle conns must be between 0 and max open conns")
+	check(cfg.Postgres.ConnMaxLifetime > 0, "postgres conn max lifetime must be positive")
 
-	if RedisHost == "" {
-		RedisHost = host
-	}
-	if RedisPort == "" {
-		RedisPort = port
+	check(cfg.Redis.Host != "" && cfg.Redis.Port != "", "redis host and port are required")
+
+	check(cfg.Token.AccessTokenKey != "", "access token key is required")
+	check(cfg.Token.RefreshTokenKey != "", "refresh token key is required")
+	check(cfg.Token.AccessTokenTTL > 0, "access token ttl must be positive")
+	check(cfg.Token.RefreshTokenTTL > cfg.Token.AccessTokenTTL, "refresh token ttl must be longer than access token ttl")
+
+	check(cfg.Chat.MessageEditWindow > 0, "chat message edit window must be positive")
+	check(cfg.Chat.RoomBus == "streams" || cfg.Chat.RoomBus == "pubsub", "chat room bus must be \"streams\" or \"pubsub\", got %q", cfg.Chat.RoomBus)
+	check(cfg.Chat.MaxFrameSize > 0, "chat max frame size must be positive")
+	check(cfg.Chat.SendBufferSize > 0, "chat send buffer size must be positive")
+	check(cfg.Chat.MaxMessageLength > 0, "chat max message length must be positive")
+	check(cfg.Chat.RateLimit > 0 && cfg.Chat.RateWindow > 0, "chat rate limit and rate window must be positive")
+
+	check(cfg.Attachment.Dir != "", "attachment dir is required")
+	check(cfg.Attachment.MaxSize > 0, "attachment max size must be positive")
+	check(cfg.Attachment.URLKey != "", "attachment url key is required")
+	check(cfg.Attachment.URLTTL > 0, "attachment url ttl must be positive")
+
+	check(cfg.Notification.RateWindow > 0, "notification rate window must be positive")
+	check(cfg.Notification.DigestInterval > 0, "notification digest interval must be positive")
+
+	if cfg.Env == EnvProd {
+		check(cfg.Token.AccessTokenKey == "" || cfg.Token.AccessTokenKey != cfg.Token.RefreshTokenKey, "access token key and refresh token key must be different in prod")
+		check(cfg.Attachment.URLKey == "" || (cfg.Attachment.URLKey != cfg.Token.AccessTokenKey && cfg.Attachment.URLKey != cfg.Token.RefreshTokenKey),
+			"attachment url key must be different from the token keys in prod")
+		for _, origin := range cfg.Server.CORSAllowedOrigins {
+			check(origin != "*", "cors allowed origins can't be \"*\" in prod")
+		}
 	}
-	if RedisPassword == "" {
-		RedisPassword = password
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config:\n  %s", strings.Join(errs, "\n  "))
 	}
-}
 
-func init() {
-	postgresInit()
-	redisInit()
+	return nil
 }
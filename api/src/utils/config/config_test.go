@@ -0,0 +1,135 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setenv(t *testing.T, key, value string) {
+	t.Helper()
+	prev, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, prev)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+server:
+  addr: ":9000"
+postgres:
+  max_open_conns: 20
+  conn_max_lifetime: 1m
+chat:
+  rate_limit: 5
+  banned_words: [foo, bar]
+`)
+	setenv(t, "CHAT_RATE_LIMIT", "7")
+	setenv(t, "POSTGRES_MAX_OPEN_CONNS", "30")
+
+	cfg, args, err := Load([]string{"-config", path, "-chat-rate-limit", "9", "-chat-block-links", "migrate", "up"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Server.Addr != ":9000" {
+		t.Errorf("addr from file: got %q", cfg.Server.Addr)
+	}
+	if cfg.Postgres.ConnMaxLifetime != time.Minute {
+		t.Errorf("conn max lifetime from file: got %v", cfg.Postgres.ConnMaxLifetime)
+	}
+	if cfg.Postgres.MaxOpenConns != 30 {
+		t.Errorf("env should override file: got %d", cfg.Postgres.MaxOpenConns)
+	}
+	if cfg.Chat.RateLimit != 9 || !cfg.Chat.BlockLinks {
+		t.Errorf("flags should override env: got %d, %v", cfg.Chat.RateLimit, cfg.Chat.BlockLinks)
+	}
+	if strings.Join(cfg.Chat.BannedWords, ",") != "foo,bar" {
+		t.Errorf("banned words from file: got %v", cfg.Chat.BannedWords)
+	}
+	if cfg.Postgres.MaxIdleConns != 5 {
+		t.Errorf("default should be kept: got %d", cfg.Postgres.MaxIdleConns)
+	}
+	if strings.Join(args, " ") != "migrate up" {
+		t.Errorf("remaining args: got %v", args)
+	}
+}
+
+func TestLoad_TOML(t *testing.T) {
+	path := writeFile(t, "config.toml", `
+env = "dev"
+
+[token]
+access_token_ttl = "5m"
+
+[chat]
+moderator_user_ids = [1, 2]
+`)
+
+	cfg, _, err := Load([]string{"-config", path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Token.AccessTokenTTL != 5*time.Minute {
+		t.Errorf("access token ttl: got %v", cfg.Token.AccessTokenTTL)
+	}
+	if len(cfg.Chat.ModeratorUserIDs) != 2 || cfg.Chat.ModeratorUserIDs[1] != 2 {
+		t.Errorf("moderator user ids: got %v", cfg.Chat.ModeratorUserIDs)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"prod without token keys", []string{"-env", "prod", "-cors-allowed-origins", "https://go-wave.com"}, "access token key is required"},
+		{"prod with wildcard origin", []string{"-env", "prod", "-access-token-key", "a", "-refresh-token-key", "b"}, "can't be \"*\" in prod"},
+		{"prod without attachment url key", []string{"-env", "prod", "-access-token-key", "a", "-refresh-token-key", "b"}, "attachment url key is required"},
+		{"prod attachment url key same as token key", []string{"-env", "prod", "-access-token-key", "a", "-refresh-token-key", "b", "-attachment-url-key", "a"},
+			"attachment url key must be different"},
+		{"unknown env", []string{"-env", "staging"}, "env must be"},
+		{"idle conns over open conns", []string{"-postgres-max-open-conns", "2", "-postgres-max-idle-conns", "3"}, "max idle conns"},
+		{"unknown room bus", []string{"-chat-room-bus", "kafka"}, "chat room bus"},
+		{"file exporter without file", []string{"-tracing-exporter", "file"}, "tracing file is required"},
+		{"sample ratio over 1", []string{"-tracing-sample-ratio", "1.5"}, "tracing sample ratio"},
+		{"negative request timeout", []string{"-request-timeout", "-1s"}, "request timeout"},
+		{"bad duration", []string{"-chat-rate-window", "soon"}, "invalid -chat-rate-window"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Load(tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_UnknownFileKey(t *testing.T) {
+	path := writeFile(t, "config.yml", "chat:\n  rate_limt: 5\n")
+
+	if _, _, err := Load([]string{"-config", path}); err == nil {
+		t.Error("expected error for unknown key")
+	}
+}
@@ -0,0 +1,314 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/code-wave/go-wave/infrastructure/logger"
+	"gopkg.in/yaml.v3"
+)
+
+// setting env와 flag 하나로 바꿀 수 있는 설정 값
+type setting struct {
+	env   string
+	flag  string
+	usage string
+	value flag.Value // Config의 필드를 가리킴
+}
+
+func (cfg *Config) settings() []setting {
+	return []setting{
+		{"APP_ENV", "env", "dev | prod", (*stringValue)(&cfg.Env)},
+		{"LISTEN_ADDR", "addr", "listen address", (*stringValue)(&cfg.Server.Addr)},
+		{"SHUTDOWN_TIMEOUT", "shutdown-timeout", "max time to drain connections on shutdown", (*durationValue)(&cfg.Server.ShutdownTimeout)},
+		{"SHUTDOWN_DELAY", "shutdown-delay", "time to keep serving after readiness turns false on shutdown", (*durationValue)(&cfg.Server.ShutdownDelay)},
+		{"HEALTH_CHECK_TIMEOUT", "health-check-timeout", "timeout of each readiness check", (*durationValue)(&cfg.Server.HealthCheckTimeout)},
+		{"REQUEST_TIMEOUT", "request-timeout", "max duration of a request's database calls, 0 disables", (*durationValue)(&cfg.Server.RequestTimeout)},
+		{"LOG_LEVEL", "log-level", "debug | info | warn | error", (*stringValue)(&cfg.Log.Level)},
+		{"LOG_FORMAT", "log-format", "text | json (default text in dev, json in prod)", (*stringValue)(&cfg.Log.Format)},
+		{"TRACING_EXPORTER", "tracing-exporter", "none | stdout | file | otlp", (*stringValue)(&cfg.Tracing.Exporter)},
+		{"TRACING_FILE", "tracing-file", "file to write spans to (file exporter)", (*stringValue)(&cfg.Tracing.File)},
+		{"TRACING_OTLP_ENDPOINT", "tracing-otlp-endpoint", "OTLP/HTTP collector host:port", (*stringValue)(&cfg.Tracing.OTLPEndpoint)},
+		{"TRACING_OTLP_INSECURE", "tracing-otlp-insecure", "send spans to the collector without TLS", (*boolValue)(&cfg.Tracing.OTLPInsecure)},
+		{"TRACING_SAMPLE_RATIO", "tracing-sample-ratio", "ratio of new traces to sample (0-1)", (*float64Value)(&cfg.Tracing.SampleRatio)},
+		{"TRACING_SERVICE_NAME", "tracing-service-name", "service name on spans", (*stringValue)(&cfg.Tracing.ServiceName)},
+		{"CORS_ALLOWED_ORIGINS", "cors-allowed-origins", "comma separated allowed origins", (*stringsValue)(&cfg.Server.CORSAllowedOrigins)},
+
+		{"POSTGRES_HOST", "postgres-host", "postgres host", (*stringValue)(&cfg.Postgres.Host)},
+		{"POSTGRES_PORT", "postgres-port", "postgres port", (*stringValue)(&cfg.Postgres.Port)},
+		{"POSTGRES_USER", "postgres-user", "postgres user", (*stringValue)(&cfg.Postgres.User)},
+		{"POSTGRES_PASSWORD", "postgres-password", "postgres password", (*stringValue)(&cfg.Postgres.Password)},
+		{"POSTGRES_DB", "postgres-db", "postgres db name", (*stringValue)(&cfg.Postgres.DBName)},
+		{"POSTGRES_MAX_OPEN_CONNS", "postgres-max-open-conns", "max open db connections", (*intValue)(&cfg.Postgres.MaxOpenConns)},
+		{"POSTGRES_MAX_IDLE_CONNS", "postgres-max-idle-conns", "max idle db connections", (*intValue)(&cfg.Postgres.MaxIdleConns)},
+		{"POSTGRES_CONN_MAX_LIFETIME", "postgres-conn-max-lifetime", "max lifetime of a db connection", (*durationValue)(&cfg.Postgres.ConnMaxLifetime)},
+		{"AUTO_MIGRATE", "auto-migrate", "apply all migrations on startup", (*boolValue)(&cfg.Postgres.AutoMigrate)},
+
+		{"REDIS_HOST", "redis-host", "redis host", (*stringValue)(&cfg.Redis.Host)},
+		{"REDIS_PORT", "redis-port", "redis port", (*stringValue)(&cfg.Redis.Port)},
+		{"REDIS_PASSWORD", "redis-password", "redis password", (*stringValue)(&cfg.Redis.Password)},
+
+		{"ACCESS_TOKEN_KEY", "access-token-key", "access token secret", (*stringValue)(&cfg.Token.AccessTokenKey)},
+		{"REFRESH_TOKEN_KEY", "refresh-token-key", "refresh token secret", (*stringValue)(&cfg.Token.RefreshTokenKey)},
+		{"TOKEN_ISSUER", "token-issuer", "token issuer", (*stringValue)(&cfg.Token.Issuer)},
+		{"ACCESS_TOKEN_TTL", "access-token-ttl", "access token lifetime", (*durationValue)(&cfg.Token.AccessTokenTTL)},
+		{"REFRESH_TOKEN_TTL", "refresh-token-ttl", "refresh token lifetime", (*durationValue)(&cfg.Token.RefreshTokenTTL)},
+
+		{"CHAT_MESSAGE_EDIT_WINDOW", "chat-message-edit-window", "time a message can be edited or deleted", (*durationValue)(&cfg.Chat.MessageEditWindow)},
+		{"CHAT_ROOM_BUS", "chat-room-bus", "streams | pubsub", (*stringValue)(&cfg.Chat.RoomBus)},
+		{"CHAT_MAX_FRAME_SIZE", "chat-max-frame-size", "max websocket frame size in bytes", (*int64Value)(&cfg.Chat.MaxFrameSize)},
+		{"CHAT_SEND_BUFFER_SIZE", "chat-send-buffer-size", "messages queued per connection", (*intValue)(&cfg.Chat.SendBufferSize)},
+		{"CHAT_MAX_MESSAGE_LENGTH", "chat-max-message-length", "max message length in characters", (*intValue)(&cfg.Chat.MaxMessageLength)},
+		{"CHAT_RATE_LIMIT", "chat-rate-limit", "messages a user can send per rate window", (*int64Value)(&cfg.Chat.RateLimit)},
+		{"CHAT_RATE_WINDOW", "chat-rate-window", "chat rate limit window", (*durationValue)(&cfg.Chat.RateWindow)},
+		{"CHAT_BANNED_WORDS", "chat-banned-words", "comma separated banned words", (*stringsValue)(&cfg.Chat.BannedWords)},
+		{"CHAT_BLOCK_LINKS", "chat-block-links", "reject messages with links", (*boolValue)(&cfg.Chat.BlockLinks)},
+		{"MODERATOR_USER_IDS", "moderator-user-ids", "comma separated moderator user ids", (*int64sValue)(&cfg.Chat.ModeratorUserIDs)},
+
+		{"CHAT_ATTACHMENT_DIR", "chat-attachment-dir", "attachment directory", (*stringValue)(&cfg.Attachment.Dir)},
+		{"CHAT_ATTACHMENT_MAX_SIZE", "chat-attachment-max-size", "max attachment size in bytes", (*int64Value)(&cfg.Attachment.MaxSize)},
+		{"ATTACHMENT_URL_KEY", "attachment-url-key", "attachment url signing key", (*stringValue)(&cfg.Attachment.URLKey)},
+		{"ATTACHMENT_URL_TTL", "attachment-url-ttl", "attachment url lifetime", (*durationValue)(&cfg.Attachment.URLTTL)},
+
+		{"SMTP_HOST", "smtp-host", "smtp host (log mails if empty)", (*stringValue)(&cfg.Notification.SMTPHost)},
+		{"SMTP_PORT", "smtp-port", "smtp port", (*stringValue)(&cfg.Notification.SMTPPort)},
+		{"SMTP_USERNAME", "smtp-username", "smtp username", (*stringValue)(&cfg.Notification.SMTPUsername)},
+		{"SMTP_PASSWORD", "smtp-password", "smtp password", (*stringValue)(&cfg.Notification.SMTPPassword)},
+		{"MAIL_FROM", "mail-from", "sender address", (*stringValue)(&cfg.Notification.MailFrom)},
+		{"VAPID_PRIVATE_KEY", "vapid-private-key", "base64url P-256 private key (log pushes if empty)", (*stringValue)(&cfg.Notification.VAPIDPrivateKey)},
+		{"VAPID_SUBJECT", "vapid-subject", "vapid subject", (*stringValue)(&cfg.Notification.VAPIDSubject)},
+		{"NOTIFICATION_RATE_WINDOW", "notification-rate-window", "time between notifications of the same chat room", (*durationValue)(&cfg.Notification.RateWindow)},
+		{"NOTIFICATION_DIGEST_INTERVAL", "notification-digest-interval", "email digest interval", (*durationValue)(&cfg.Notification.DigestInterval)},
+	}
+}
+
+// Load default < 설정 파일 (-config 또는 CONFIG_FILE, .yaml|.yml|.toml) < env < flag 순서로 설정을 만들고 검증함
+// flag 뒤에 남은 인자(서브커맨드)를 함께 반환
+func Load(args []string) (*Config, []string, error) {
+	cfg := Default()
+	settings := cfg.settings()
+
+	fs := flag.NewFlagSet("go-wave", flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "config file (.yaml, .yml, .toml)")
+	// flag는 파일과 env를 적용한 다음에 덮어써야 하므로 값만 모아둠
+	flagValues := make([]*rawValue, len(settings))
+	for i, s := range settings {
+		_, isBool := s.value.(*boolValue)
+		flagValues[i] = &rawValue{isBool: isBool}
+		fs.Var(flagValues[i], s.flag, fmt.Sprintf("%s (env %s)", s.usage, s.env))
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	if *configFile != "" {
+		if err := cfg.loadFile(*configFile); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, s := range settings {
+		if value := os.Getenv(s.env); value != "" {
+			if err := s.value.Set(value); err != nil {
+				return nil, nil, fmt.Errorf("invalid %s %q: %v", s.env, value, err)
+			}
+		}
+	}
+
+	for i, s := range settings {
+		if flagValues[i].isSet {
+			if err := s.value.Set(flagValues[i].value); err != nil {
+				return nil, nil, fmt.Errorf("invalid -%s %q: %v", s.flag, flagValues[i].value, err)
+			}
+		}
+	}
+
+	if cfg.Env == EnvDev {
+		if filled := cfg.devSecrets(); len(filled) > 0 {
+			logger.Warn("config: secrets not set, using dev defaults", logger.F("settings", filled))
+		}
+	}
+	if cfg.Log.Format == "" {
+		cfg.Log.Format = "text"
+		if cfg.Env == EnvProd {
+			cfg.Log.Format = "json"
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, fs.Args(), nil
+}
+
+func (cfg *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err = decoder.Decode(cfg); err == io.EOF {
+			err = nil
+		}
+	case ".toml":
+		var meta toml.MetaData
+		meta, err = toml.Decode(string(data), cfg)
+		if err == nil && len(meta.Undecoded()) > 0 {
+			err = fmt.Errorf("unknown keys %v", meta.Undecoded())
+		}
+	default:
+		return fmt.Errorf("unsupported config file %q: use .yaml, .yml or .toml", path)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+
+	return nil
+}
+
+// rawValue flag로 받은 값을 그대로 보관
+type rawValue struct {
+	value  string
+	isSet  bool
+	isBool bool
+}
+
+func (v *rawValue) String() string { return v.value }
+
+func (v *rawValue) Set(value string) error {
+	v.value, v.isSet = value, true
+	return nil
+}
+
+func (v *rawValue) IsBoolFlag() bool { return v.isBool }
+
+type stringValue string
+
+func (v *stringValue) String() string { return string(*v) }
+
+func (v *stringValue) Set(value string) error {
+	*v = stringValue(value)
+	return nil
+}
+
+type intValue int
+
+func (v *intValue) String() string { return strconv.Itoa(int(*v)) }
+
+func (v *intValue) Set(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	*v = intValue(n)
+	return nil
+}
+
+type int64Value int64
+
+func (v *int64Value) String() string { return strconv.FormatInt(int64(*v), 10) }
+
+func (v *int64Value) Set(value string) error {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return err
+	}
+	*v = int64Value(n)
+	return nil
+}
+
+type float64Value float64
+
+func (v *float64Value) String() string { return strconv.FormatFloat(float64(*v), 'g', -1, 64) }
+
+func (v *float64Value) Set(value string) error {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return err
+	}
+	*v = float64Value(f)
+	return nil
+}
+
+type boolValue bool
+
+func (v *boolValue) String() string { return strconv.FormatBool(bool(*v)) }
+
+func (v *boolValue) Set(value string) error {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	*v = boolValue(b)
+	return nil
+}
+
+type durationValue time.Duration
+
+func (v *durationValue) String() string { return time.Duration(*v).String() }
+
+func (v *durationValue) Set(value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*v = durationValue(d)
+	return nil
+}
+
+// stringsValue 쉼표로 구분한 목록
+type stringsValue []string
+
+func (v *stringsValue) String() string { return strings.Join(*v, ",") }
+
+func (v *stringsValue) Set(value string) error {
+	var values []string
+	for _, s := range strings.Split(value, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			values = append(values, s)
+		}
+	}
+	*v = values
+	return nil
+}
+
+// int64sValue 쉼표로 구분한 숫자 목록
+type int64sValue []int64
+
+func (v *int64sValue) String() string {
+	values := make([]string, len(*v))
+	for i, n := range *v {
+		values[i] = strconv.FormatInt(n, 10)
+	}
+	return strings.Join(values, ",")
+}
+
+func (v *int64sValue) Set(value string) error {
+	var values []int64
+	for _, s := range strings.Split(value, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		values = append(values, n)
+	}
+	*v = values
+	return nil
+}